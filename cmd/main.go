@@ -3,13 +3,24 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/alertmanager"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/analyzer"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/charts"
 	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/export"
 	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
 	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/monitor"
 	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/prometheus"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/storage"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/tui"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
@@ -25,6 +36,7 @@ var (
 	// CLI flags
 	cfgFile string
 	debug   bool
+	noTUI   bool
 )
 
 func main() {
@@ -48,13 +60,22 @@ Features:
   • Análise temporal e correlação`,
 	Version: fmt.Sprintf("%s (commit: %s, built: %s)", Version, Commit, BuildTime),
 	Run: func(cmd *cobra.Command, args []string) {
+		// Setup logging
+		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+		if debug {
+			zerolog.SetGlobalLevel(zerolog.DebugLevel)
+		}
+		log.Logger = log.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+
 		fmt.Println("🐕 HPA Watchdog starting...")
 		fmt.Printf("Version: %s\n", Version)
 		fmt.Printf("Config: %s\n", cfgFile)
-		fmt.Printf("Debug: %v\n", debug)
 		fmt.Println()
-		fmt.Println("⚠️  TUI não implementado ainda. Use 'make run' após implementação.")
-		// TODO: Inicializar TUI e monitoring loops
+
+		if err := runWatcher(!noTUI); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Watcher falhou: %v\n", err)
+			os.Exit(1)
+		}
 	},
 }
 
@@ -92,6 +113,7 @@ var validateCmd = &cobra.Command{
 		fmt.Printf("  Alertmanager: %v\n", cfg.AlertmanagerEnabled)
 		fmt.Printf("  Auto-discover Clusters: %v\n", cfg.AutoDiscoverClusters)
 		fmt.Printf("  Max Active Alerts: %d\n", cfg.MaxActiveAlerts)
+		fmt.Printf("  Threshold Overrides: %d\n", len(cfg.ThresholdOverrides))
 	},
 }
 
@@ -146,14 +168,148 @@ var clustersCmd = &cobra.Command{
 
 var exportCmd = &cobra.Command{
 	Use:   "export",
-	Short: "Exporta histórico de alertas",
-	Long:  "Exporta histórico de alertas em formato JSON ou CSV",
+	Short: "Exporta histórico de alertas e snapshots",
+	Long: `Exporta alertas ou snapshots persistidos (storage.enable_persistence) em JSON, CSV ou NDJSON.
+
+As colunas são estáveis (ver internal/export/README.md). --since/--until aceitam
+RFC3339, YYYY-MM-DD ou uma duração relativa (24h, 7d).
+
+Exemplos:
+  hpa-watchdog export --since 30d --format csv -o capacity.csv
+  hpa-watchdog export --data snapshots --cluster prod --namespace api --since 7d -o - --format ndjson`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runExport(cmd); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var silencesCmd = &cobra.Command{
+	Use:   "silences",
+	Short: "Gerencia silences criados pelo watchdog no Alertmanager",
+}
+
+var silencesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lista silences ativos criados pelo watchdog",
+	Run: func(cmd *cobra.Command, args []string) {
+		silencer, _, err := newSilencer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		silences, err := silencer.List(context.Background())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Falha ao listar silences: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(silences) == 0 {
+			fmt.Println("✅ Nenhum silence ativo criado pelo watchdog")
+			return
+		}
+
+		fmt.Printf("🔕 %d silence(s) do watchdog:\n\n", len(silences))
+		for _, silence := range silences {
+			fmt.Printf("%s  [%s] %s\n", silence.ID, silence.Cluster, silence.Status.State)
+			fmt.Printf("   Por:      %s\n", silence.CreatedBy)
+			fmt.Printf("   Expira:   %s (em %s)\n", silence.EndsAt.Local().Format("2006-01-02 15:04"), time.Until(silence.EndsAt).Round(time.Minute))
+			fmt.Printf("   Comment:  %s\n", silence.Comment)
+			fmt.Println()
+		}
+	},
+}
+
+var silencesCreateCmd = &cobra.Command{
+	Use:   "create <cluster> <alert-id>",
+	Short: "Cria um silence para um alerta do Alertmanager (ID do watchdog ou fingerprint)",
+	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		output, _ := cmd.Flags().GetString("output")
-		format, _ := cmd.Flags().GetString("format")
-		fmt.Printf("Exporting alerts to %s (format: %s)\n", output, format)
-		// TODO: Implementar export
-		fmt.Println("⚠️  Export não implementado ainda")
+		duration, _ := cmd.Flags().GetDuration("duration")
+		comment, _ := cmd.Flags().GetString("comment")
+		by, _ := cmd.Flags().GetString("by")
+
+		if strings.TrimSpace(comment) == "" {
+			fmt.Fprintln(os.Stderr, "❌ --comment é obrigatório")
+			os.Exit(1)
+		}
+		if duration < 0 {
+			fmt.Fprintln(os.Stderr, "❌ --duration deve ser positivo")
+			os.Exit(1)
+		}
+
+		silencer, cfg, err := newSilencer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+		if by == "" {
+			by = tui.ResolveAckUser(cfg.AckUser)
+		}
+		if duration == 0 {
+			duration = silencer.Duration()
+		}
+
+		id, err := silencer.Create(context.Background(), args[0], args[1], by, comment, duration, time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Falha ao criar silence: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("🔕 Silence %s criado no cluster %s por %s (expira em %s)\n", id, args[0], by, duration)
+	},
+}
+
+var silencesExpireCmd = &cobra.Command{
+	Use:   "expire <cluster> <silence-id>",
+	Short: "Expira um silence criado pelo watchdog",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		silencer, _, err := newSilencer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			os.Exit(1)
+		}
+
+		if err := silencer.Expire(context.Background(), args[0], args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Falha ao expirar silence: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("✅ Silence %s expirado no cluster %s\n", args[1], args[0])
+	},
+}
+
+var thresholdsCmd = &cobra.Command{
+	Use:   "thresholds",
+	Short: "Consulta os thresholds efetivos (globais + threshold_overrides)",
+}
+
+var thresholdsExplainCmd = &cobra.Command{
+	Use:   "explain <cluster> <namespace> <hpa>",
+	Short: "Mostra os thresholds efetivos de um HPA e a camada que definiu cada um",
+	Long: `Resolve os thresholds de um HPA como o watcher faz a cada snapshot: globais (thresholds:)
+e depois os threshold_overrides que casam, da camada cluster para namespace e HPA.
+
+Exemplo:
+  hpa-watchdog thresholds explain prod-cluster batch-jobs report-worker`,
+	Args: cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, err := config.Load(cfgFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Falha ao carregar config: %v\n", err)
+			os.Exit(1)
+		}
+
+		thresholds := config.NewThresholdManager(cfg.Thresholds)
+		if err := thresholds.SetOverrides(cfg.ThresholdOverrides); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ threshold_overrides inválidos: %v\n", err)
+			os.Exit(1)
+		}
+
+		printThresholdSources(args[0], args[1], args[2], thresholds.Explain(args[0], args[1], args[2]))
 	},
 }
 
@@ -219,6 +375,430 @@ Via variáveis de ambiente:
 	},
 }
 
+// runWatcher executa o monitoramento contínuo até o usuário sair da TUI (ou SIGINT/SIGTERM sem TUI)
+func runWatcher(interactive bool) error {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("falha ao carregar config: %w", err)
+	}
+
+	// Logs no terminal quebrariam a TUI: vão para logging.output
+	if interactive {
+		closeLog, err := redirectLogs(cfg)
+		if err != nil {
+			return err
+		}
+		defer closeLog()
+	}
+
+	clusters, err := config.DiscoverClusters(cfg)
+	if err != nil {
+		return fmt.Errorf("falha ao descobrir clusters: %w", err)
+	}
+
+	if len(clusters) == 0 {
+		return fmt.Errorf("nenhum cluster para monitorar")
+	}
+
+	clusterPtrs := make([]*models.ClusterInfo, len(clusters))
+	for i := range clusters {
+		clusterPtrs[i] = &clusters[i]
+	}
+
+	session, err := monitor.NewMonitoringSession(clusterPtrs)
+	if err != nil {
+		return fmt.Errorf("falha ao criar sessão de monitoramento: %w", err)
+	}
+	defer session.Shutdown()
+
+	watcher := monitor.NewWatcher(cfg, session)
+	thresholds := config.NewThresholdManager(cfg.Thresholds)
+	if err := thresholds.SetOverrides(cfg.ThresholdOverrides); err != nil {
+		return fmt.Errorf("threshold_overrides inválidos: %w", err)
+	}
+	watcher.SetAnalyzer(analyzer.NewEngine(thresholds))
+
+	if cfg.EnablePersistence {
+		store, err := storage.Open(cfg)
+		if err != nil {
+			return fmt.Errorf("falha ao abrir storage: %w", err)
+		}
+		defer store.Close()
+
+		watcher.SetStorage(store)
+
+		// Reconhecimentos de alertas que ainda estavam ativos antes do restart
+		if restored, err := watcher.RestoreAlerts(); err != nil {
+			log.Warn().Err(err).Msg("Failed to restore persisted alerts")
+		} else if restored > 0 {
+			log.Info().Int("alerts", restored).Msg("Restored persisted alerts")
+		}
+	}
+
+	// Informers: snapshots a partir do cache local + eventos em tempo real
+	session.StartInformers()
+
+	if cfg.PrometheusEnabled {
+		setupPrometheusEnrichers(cfg, session, watcher)
+	}
+
+	watcher.Start()
+	defer watcher.Stop()
+
+	var syncer *alertmanager.Syncer
+	if cfg.AlertmanagerEnabled {
+		syncer, err = setupAlertmanager(cfg, session, watcher)
+		if err != nil {
+			return fmt.Errorf("falha ao configurar Alertmanager: %w", err)
+		}
+		syncer.Start()
+		defer syncer.Stop()
+
+		if cfg.AlertmanagerPushEnabled {
+			pusher := alertmanager.NewPusher(syncer, watcher.AlertStore(), time.Duration(cfg.AlertmanagerPushResendSeconds)*time.Second)
+			pusher.Start()
+			defer pusher.Stop()
+		}
+	}
+
+	// Hot reload: watchdog.yaml alterado ou SIGHUP
+	reloader, err := config.NewReloader(cfgFile, cfg, (&configReloader{
+		session:    session,
+		watcher:    watcher,
+		thresholds: thresholds,
+		syncer:     syncer,
+	}).apply)
+	if err != nil {
+		return fmt.Errorf("falha ao configurar reload da config: %w", err)
+	}
+	if err := reloader.Start(); err != nil {
+		log.Warn().Err(err).Msg("Config hot reload disabled (SIGHUP and file changes ignored)")
+	} else {
+		defer reloader.Stop()
+	}
+
+	if interactive {
+		acknowledge, silenceDuration := tuiAcknowledge(cfg, watcher, syncer)
+		opts := tui.Options{
+			Acknowledge:     acknowledge,
+			SilenceDuration: silenceDuration,
+			Thresholds:      thresholds,
+			SaveThresholds: func(t models.Thresholds) error {
+				return config.WriteThresholds(cfgFile, t)
+			},
+		}
+		if err := tui.Run(cfg, watcher, opts); err != nil {
+			return fmt.Errorf("falha na TUI: %w", err)
+		}
+		log.Info().Msg("Shutting down HPA Watchdog")
+		return nil
+	}
+
+	// Aguarda sinal de encerramento
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-sigCh
+
+	log.Info().Str("signal", sig.String()).Msg("Shutting down HPA Watchdog")
+	return nil
+}
+
+// tuiAcknowledge reconhecimento usado pela TUI: cria silence (duração e comentário escolhidos no
+// prompt) para alertas do Alertmanager quando silences.on_ack está ativo e persiste o ack quando
+// há storage. Retorna também a duração sugerida no prompt (0 = sem silences).
+func tuiAcknowledge(cfg *models.WatchdogConfig, watcher *monitor.Watcher, syncer *alertmanager.Syncer) (tui.AckFunc, time.Duration) {
+	var silencer *alertmanager.Silencer
+	var silenceDuration time.Duration
+	if syncer != nil && cfg.AlertmanagerSilenceOnAck {
+		silencer = alertmanager.NewSilencer(syncer, watcher.AlertStore(), time.Duration(cfg.AlertmanagerSilenceMinutes)*time.Minute)
+		silenceDuration = silencer.Duration()
+	}
+
+	return func(ctx context.Context, id, by, comment string, duration time.Duration, now time.Time) error {
+		var err error
+		if silencer != nil {
+			_, err = silencer.Acknowledge(ctx, id, by, comment, duration, now)
+		} else {
+			err = watcher.AlertStore().Acknowledge(id, by, now)
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := watcher.PersistAlerts(); err != nil {
+			log.Warn().Err(err).Str("alert", id).Msg("Failed to persist acknowledgement")
+		}
+		return nil
+	}, silenceDuration
+}
+
+// redirectLogs envia os logs para logging.output (descartados se vazio) enquanto a TUI está ativa
+func redirectLogs(cfg *models.WatchdogConfig) (func(), error) {
+	if cfg.LogOutput == "" {
+		log.Logger = log.Output(io.Discard)
+		return func() {}, nil
+	}
+
+	path, err := config.ExpandPath(cfg.LogOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("falha ao criar diretório de logs: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir arquivo de log: %w", err)
+	}
+
+	log.Logger = log.Output(file)
+	fmt.Printf("📝 Logs em %s\n", path)
+
+	return func() { file.Close() }, nil
+}
+
+// setupPrometheusEnrichers conecta ao Prometheus de cada cluster e registra no watcher
+func setupPrometheusEnrichers(cfg *models.WatchdogConfig, session *monitor.MonitoringSession, watcher *monitor.Watcher) {
+	for _, cluster := range session.Clusters() {
+		if promClient := connectPrometheus(cfg, session, cluster.Name); promClient != nil {
+			watcher.SetEnricher(cluster.Name, promClient)
+		}
+	}
+}
+
+// connectPrometheus conecta ao Prometheus do cluster (endpoint da config ou auto-discovery).
+// Retorna nil se não configurado ou indisponível.
+func connectPrometheus(cfg *models.WatchdogConfig, session *monitor.MonitoringSession, clusterName string) *prometheus.Client {
+	var promClient *prometheus.Client
+	var err error
+
+	if endpoint, ok := cfg.PrometheusEndpoints[clusterName]; ok {
+		promClient, err = prometheus.NewClient(clusterName, endpoint)
+	} else if cfg.PrometheusAutoDiscover {
+		client, exists := session.GetClient(clusterName)
+		if !exists {
+			return nil
+		}
+		promClient, _, err = prometheus.DiscoverAndConnect(context.Background(), client.Clientset, clusterName, "monitoring")
+	} else {
+		return nil
+	}
+
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("cluster", clusterName).
+			Bool("fallback", cfg.PrometheusFallback).
+			Msg("Prometheus not available for cluster, continuing without metrics")
+		return nil
+	}
+
+	return promClient
+}
+
+// setupAlertmanager cria o syncer com o Alertmanager de cada cluster, entregando os alertas ao store do watcher
+func setupAlertmanager(cfg *models.WatchdogConfig, session *monitor.MonitoringSession, watcher *monitor.Watcher) (*alertmanager.Syncer, error) {
+	filter, err := alertmanager.FilterFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	syncer := alertmanager.NewSyncer(watcher.AlertStore(), time.Duration(cfg.AlertmanagerSyncInterval)*time.Second)
+	syncer.SetFilter(filter)
+
+	for _, client := range alertmanagerClients(cfg, session.Clusters()) {
+		syncer.AddClient(client)
+	}
+
+	return syncer, nil
+}
+
+// alertmanagerClients conecta ao Alertmanager de cada cluster (endpoint da config ou auto-discovery)
+func alertmanagerClients(cfg *models.WatchdogConfig, clusters []models.ClusterInfo) []*alertmanager.Client {
+	ctx := context.Background()
+
+	var clients []*alertmanager.Client
+	for _, cluster := range clusters {
+		var amClient *alertmanager.Client
+		var err error
+
+		if endpoint, ok := cfg.AlertmanagerEndpoints[cluster.Name]; ok {
+			amClient, err = alertmanager.NewClient(cluster.Name, endpoint)
+		} else if cfg.AlertmanagerAutoDiscover {
+			amClient, err = alertmanager.Discover(ctx, cluster.Name, cfg.AlertmanagerDiscoveryPatterns)
+		} else {
+			continue
+		}
+
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("cluster", cluster.Name).
+				Msg("Alertmanager not available for cluster, continuing without external alerts")
+			continue
+		}
+
+		clients = append(clients, amClient)
+	}
+
+	return clients
+}
+
+// runExport lê o histórico persistido e escreve no formato/arquivo pedidos
+func runExport(cmd *cobra.Command) error {
+	data, _ := cmd.Flags().GetString("data")
+	formatFlag, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	sinceFlag, _ := cmd.Flags().GetString("since")
+	untilFlag, _ := cmd.Flags().GetString("until")
+	cluster, _ := cmd.Flags().GetString("cluster")
+	namespace, _ := cmd.Flags().GetString("namespace")
+	hpaName, _ := cmd.Flags().GetString("hpa")
+	severityFlags, _ := cmd.Flags().GetStringSlice("severity")
+	typeFlags, _ := cmd.Flags().GetStringSlice("type")
+
+	if data != "alerts" && data != "snapshots" {
+		return fmt.Errorf("--data inválido %q (use alerts ou snapshots)", data)
+	}
+	if data == "snapshots" && (len(severityFlags) > 0 || len(typeFlags) > 0) {
+		return fmt.Errorf("--severity e --type só se aplicam a --data alerts")
+	}
+
+	format, err := export.ParseFormat(formatFlag)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	since, err := export.ParseTime(sinceFlag, now)
+	if err != nil {
+		return fmt.Errorf("--since: %w", err)
+	}
+	until, err := export.ParseTime(untilFlag, now)
+	if err != nil {
+		return fmt.Errorf("--until: %w", err)
+	}
+	if !since.IsZero() && !until.IsZero() && until.Before(since) {
+		return fmt.Errorf("--until (%s) é anterior a --since (%s)", until.Format(time.RFC3339), since.Format(time.RFC3339))
+	}
+
+	severities := make([]models.AlertSeverity, 0, len(severityFlags))
+	for _, value := range severityFlags {
+		severity, err := models.ParseAlertSeverity(value)
+		if err != nil {
+			return err
+		}
+		severities = append(severities, severity)
+	}
+
+	types := make([]models.AnomalyType, 0, len(typeFlags))
+	for _, value := range typeFlags {
+		anomaly, err := models.ParseAnomalyType(value)
+		if err != nil {
+			return err
+		}
+		types = append(types, anomaly)
+	}
+
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return fmt.Errorf("falha ao carregar config: %w", err)
+	}
+	if !cfg.EnablePersistence {
+		return fmt.Errorf("persistência desabilitada (storage.enable_persistence): não há histórico para exportar")
+	}
+
+	store, err := storage.Open(cfg)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir storage: %w", err)
+	}
+	defer store.Close()
+
+	if output == "" {
+		output = data + "." + format.Extension()
+	}
+
+	out := os.Stdout
+	if output != "-" {
+		file, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("falha ao criar %s: %w", output, err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	ctx := context.Background()
+	var count int
+
+	if data == "alerts" {
+		alerts, err := store.Alerts(ctx, storage.AlertQuery{
+			Since: since, Until: until,
+			Cluster: cluster, Namespace: namespace, HPAName: hpaName,
+			Severities: severities, Types: types,
+		})
+		if err != nil {
+			return err
+		}
+		count = len(alerts)
+		if err := export.WriteAlerts(out, format, alerts); err != nil {
+			return fmt.Errorf("falha ao exportar alertas: %w", err)
+		}
+	} else {
+		snapshots, err := store.Snapshots(ctx, storage.SnapshotQuery{
+			Since: since, Until: until,
+			Cluster: cluster, Namespace: namespace, Name: hpaName,
+		})
+		if err != nil {
+			return err
+		}
+		count = len(snapshots)
+		if err := export.WriteSnapshots(out, format, snapshots); err != nil {
+			return fmt.Errorf("falha ao exportar snapshots: %w", err)
+		}
+	}
+
+	if output != "-" {
+		if err := out.Close(); err != nil {
+			return fmt.Errorf("falha ao gravar %s: %w", output, err)
+		}
+		fmt.Printf("✅ %d registro(s) de %s exportados para %s (%s)\n", count, data, output, format)
+	}
+
+	return nil
+}
+
+// newSilencer cria um silencer com o Alertmanager dos clusters descobertos (para os comandos de silences)
+func newSilencer() (*alertmanager.Silencer, *models.WatchdogConfig, error) {
+	cfg, err := config.Load(cfgFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("falha ao carregar config: %w", err)
+	}
+
+	if !cfg.AlertmanagerEnabled {
+		return nil, nil, fmt.Errorf("integração com Alertmanager desabilitada (monitoring.alertmanager.enabled)")
+	}
+
+	clusters, err := config.DiscoverClusters(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("falha ao descobrir clusters: %w", err)
+	}
+
+	// Syncer usado só como registro de clients (sem polling)
+	syncer := alertmanager.NewSyncer(nil, 0)
+	for _, client := range alertmanagerClients(cfg, clusters) {
+		syncer.AddClient(client)
+	}
+
+	if len(syncer.Clients()) == 0 {
+		return nil, nil, fmt.Errorf("nenhum Alertmanager disponível")
+	}
+
+	return alertmanager.NewSilencer(syncer, nil, time.Duration(cfg.AlertmanagerSilenceMinutes)*time.Minute), cfg, nil
+}
+
 // runIntegratedTest executa teste integrado K8s + Prometheus
 func runIntegratedTest(cluster, namespace, hpaName string, collectPrometheus, showHistory, verbose bool) error {
 	ctx := context.Background()
@@ -239,6 +819,18 @@ func runIntegratedTest(cluster, namespace, hpaName string, collectPrometheus, sh
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println()
 
+	// Mesmo engine de detecção do watcher, com thresholds da config (ou padrões) e overrides por escopo
+	thresholds := config.NewThresholdManager(config.DefaultThresholds())
+	if cfg, err := config.Load(cfgFile); err == nil {
+		thresholds = config.NewThresholdManager(cfg.Thresholds)
+		if err := thresholds.SetOverrides(cfg.ThresholdOverrides); err != nil {
+			log.Warn().Err(err).Msg("⚠️  threshold_overrides ignorados")
+		}
+	} else {
+		log.Warn().Err(err).Msg("⚠️  Config não carregada, usando thresholds padrão")
+	}
+	engine := analyzer.NewEngine(thresholds)
+
 	// 1. Setup K8s Client
 	log.Info().Msg("🔌 Conectando ao cluster...")
 	clusterInfo := &models.ClusterInfo{
@@ -355,7 +947,7 @@ func runIntegratedTest(cluster, namespace, hpaName string, collectPrometheus, sh
 		}
 
 		// Print snapshot
-		printDetailedSnapshot(snapshot, showHistory)
+		printDetailedSnapshot(snapshot, engine.AnalyzeSnapshot(snapshot), showHistory, thresholds.For(snapshot.Cluster, snapshot.Namespace, snapshot.Name).TargetDeviationPercent)
 		fmt.Println()
 	}
 
@@ -363,7 +955,7 @@ func runIntegratedTest(cluster, namespace, hpaName string, collectPrometheus, sh
 }
 
 // printDetailedSnapshot imprime snapshot detalhado
-func printDetailedSnapshot(s *models.HPASnapshot, showHistory bool) {
+func printDetailedSnapshot(s *models.HPASnapshot, alerts []models.UnifiedAlert, showHistory bool, bandPercent float64) {
 	fmt.Printf("📍 Nome: %s/%s\n", s.Namespace, s.Name)
 	fmt.Printf("🕐 Timestamp: %s\n", s.Timestamp.Format("2006-01-02 15:04:05"))
 	fmt.Println()
@@ -376,6 +968,14 @@ func printDetailedSnapshot(s *models.HPASnapshot, showHistory bool) {
 	}
 	if s.MemoryTarget > 0 {
 		fmt.Printf("   Memory Target:     %d%%\n", s.MemoryTarget)
+	} else {
+		fmt.Printf("   Memory Target:     não configurado\n")
+	}
+	for _, spec := range s.MetricSpecs {
+		fmt.Printf("   Metric:            %s\n", spec)
+	}
+	if s.SpecManager != "" && s.SpecManagedAt != nil {
+		fmt.Printf("   Alterado por:      %s (%s ago)\n", s.SpecManager, formatDuration(time.Since(*s.SpecManagedAt)))
 	}
 	fmt.Println()
 
@@ -391,6 +991,12 @@ func printDetailedSnapshot(s *models.HPASnapshot, showHistory bool) {
 			s.LastScaleTime.Format("2006-01-02 15:04:05"),
 			formatDuration(ago))
 	}
+	for _, c := range s.Conditions {
+		fmt.Printf("   %-18s %s (%s, há %s)\n", c.Type+":", c.Status, c.Reason, formatDuration(time.Since(c.LastTransitionTime)))
+		if c.Message != "" {
+			fmt.Printf("   %-18s %s\n", "", c.Message)
+		}
+	}
 	fmt.Println()
 
 	// Resources
@@ -454,108 +1060,85 @@ func printDetailedSnapshot(s *models.HPASnapshot, showHistory bool) {
 
 	// History
 	if showHistory {
-		if len(s.CPUHistory) > 0 {
-			fmt.Println("📊 Histórico CPU (5 min):")
-			for i, val := range s.CPUHistory {
-				fmt.Printf("   T-%ds: %.2f%%\n", (len(s.CPUHistory)-i)*30, val)
-			}
-			fmt.Println()
-		}
-		if len(s.MemoryHistory) > 0 {
-			fmt.Println("📊 Histórico Memory (5 min):")
-			for i, val := range s.MemoryHistory {
-				fmt.Printf("   T-%ds: %.2f%%\n", (len(s.MemoryHistory)-i)*30, val)
-			}
-			fmt.Println()
-		}
-		if len(s.ReplicaHistory) > 0 {
-			fmt.Println("📊 Histórico Replicas (5 min):")
-			for i, val := range s.ReplicaHistory {
-				fmt.Printf("   T-%ds: %d\n", (len(s.ReplicaHistory)-i)*30, val)
-			}
-			fmt.Println()
-		}
+		printHistory(s, bandPercent)
 	}
 
-	// Quick anomaly analysis
-	fmt.Println("🔍 Análise Rápida:")
-	anomalies := detectQuickAnomalies(s)
-	if len(anomalies) == 0 {
+	// Anomaly analysis
+	fmt.Println("🔍 Análise:")
+	if len(alerts) == 0 {
 		fmt.Println("   ✅ Nenhuma anomalia detectada")
 	} else {
-		for _, anomaly := range anomalies {
-			fmt.Printf("   %s %s\n", anomaly.icon, anomaly.message)
+		for _, alert := range alerts {
+			fmt.Printf("   %s [%s] %s\n", severityIcon(alert.Severity), alert.Type, alert.Summary)
+			fmt.Printf("      %s\n", alert.Description)
 		}
 	}
 }
 
-type quickAnomaly struct {
-	icon    string
-	message string
-}
-
-func detectQuickAnomalies(s *models.HPASnapshot) []quickAnomaly {
-	var anomalies []quickAnomaly
-
-	// 1. Maxed out
-	if s.CurrentReplicas >= s.MaxReplicas && s.CPUCurrent > float64(s.CPUTarget)+20 {
-		anomalies = append(anomalies, quickAnomaly{
-			icon:    "🔴",
-			message: fmt.Sprintf("MAXED OUT: no limite (%d) com CPU %.2f%% (target: %d%%)",
-				s.MaxReplicas, s.CPUCurrent, s.CPUTarget),
-		})
+// printHistory imprime sparklines e o gráfico de CPU vs target com réplicas sobrepostas
+// (bandPercent = desvio do target sombreado)
+func printHistory(s *models.HPASnapshot, bandPercent float64) {
+	if len(s.CPUHistory) == 0 && len(s.MemoryHistory) == 0 && len(s.ReplicaHistory) == 0 {
+		fmt.Println("📊 Histórico: sem dados do Prometheus")
+		fmt.Println()
+		return
 	}
 
-	// 2. Underutilization
-	if s.CurrentReplicas > 3 && s.CPUTarget > 0 && s.CPUCurrent < float64(s.CPUTarget)-40 {
-		anomalies = append(anomalies, quickAnomaly{
-			icon:    "🟡",
-			message: fmt.Sprintf("UNDERUTILIZED: CPU %.2f%% muito abaixo do target %d%%",
-				s.CPUCurrent, s.CPUTarget),
-		})
+	fmt.Println("📊 Histórico (5 min):")
+	if len(s.CPUHistory) > 0 {
+		low, high := charts.Bounds(s.CPUHistory)
+		fmt.Printf("   CPU:       %s  %.0f%%..%.0f%%\n", charts.Sparkline(s.CPUHistory, 0), low, high)
 	}
-
-	// 3. Missing memory target
-	if s.MemoryTarget == 0 {
-		anomalies = append(anomalies, quickAnomaly{
-			icon:    "🟡",
-			message: "CONFIG: Memory target não configurado",
-		})
+	if len(s.MemoryHistory) > 0 {
+		low, high := charts.Bounds(s.MemoryHistory)
+		fmt.Printf("   Memory:    %s  %.0f%%..%.0f%%\n", charts.Sparkline(s.MemoryHistory, 0), low, high)
 	}
-
-	// 4. High error rate
-	if s.ErrorRate > 5.0 {
-		anomalies = append(anomalies, quickAnomaly{
-			icon:    "🔴",
-			message: fmt.Sprintf("HIGH ERROR RATE: %.2f%% (crítico >5%%)", s.ErrorRate),
-		})
+	if len(s.ReplicaHistory) > 0 {
+		low, high := charts.Bounds(charts.Replicas(s.ReplicaHistory))
+		fmt.Printf("   Replicas:  %s  %.0f..%.0f\n", charts.Sparkline(charts.Replicas(s.ReplicaHistory), 0), low, high)
 	}
+	fmt.Println()
 
-	// 5. High latency
-	if s.P95Latency > 1000 {
-		anomalies = append(anomalies, quickAnomaly{
-			icon:    "🔴",
-			message: fmt.Sprintf("HIGH LATENCY: P95 %.2fms (>1000ms)", s.P95Latency),
-		})
+	for _, line := range charts.HPA(s, charts.Options{Width: 72, Height: 10, BandPercent: bandPercent}) {
+		fmt.Println("   " + line)
 	}
+	fmt.Println()
+}
 
-	// 6. Oscillation
-	if len(s.ReplicaHistory) >= 5 {
-		changes := 0
-		for i := 1; i < len(s.ReplicaHistory); i++ {
-			if s.ReplicaHistory[i] != s.ReplicaHistory[i-1] {
-				changes++
-			}
+// printThresholdSources imprime cada threshold efetivo com a camada e o override que o definiu
+func printThresholdSources(cluster, namespace, hpa string, sources []config.ThresholdSource) {
+	fmt.Printf("🎚️  Thresholds efetivos de %s/%s/%s\n\n", cluster, namespace, hpa)
+
+	keyWidth := 0
+	for _, source := range sources {
+		if len(source.Key) > keyWidth {
+			keyWidth = len(source.Key)
 		}
-		if changes > 3 {
-			anomalies = append(anomalies, quickAnomaly{
-				icon:    "🔴",
-				message: fmt.Sprintf("OSCILLATION: %d mudanças de réplicas em 5min", changes),
-			})
+	}
+
+	overridden := 0
+	for _, source := range sources {
+		origin := source.Layer.String()
+		if source.Override >= 0 {
+			origin = fmt.Sprintf("%-9s threshold_overrides[%d] %s", origin, source.Override, source.Scope)
+			overridden++
 		}
+		fmt.Printf("  %-*s  %-8s  %s\n", keyWidth, source.Key, source.Value+source.Unit, origin)
 	}
 
-	return anomalies
+	fmt.Printf("\n%d de %d thresholds vêm de overrides\n", overridden, len(sources))
+}
+
+// severityIcon ícone de cada severidade
+func severityIcon(severity models.AlertSeverity) string {
+	switch severity {
+	case models.SeverityCritical:
+		return "🔴"
+	case models.SeverityWarning:
+		return "🟡"
+	default:
+		return "🔵"
+	}
 }
 
 func formatDuration(d time.Duration) string {
@@ -580,10 +1163,19 @@ func init() {
 	// Root command flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "configs/watchdog.yaml", "arquivo de configuração")
 	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "habilita modo debug (logs verbosos)")
+	rootCmd.Flags().BoolVar(&noTUI, "no-tui", false, "roda sem interface (headless, logs no terminal)")
 
 	// Export command flags
-	exportCmd.Flags().StringP("output", "o", "alerts.json", "arquivo de saída")
-	exportCmd.Flags().StringP("format", "f", "json", "formato de exportação (json, csv)")
+	exportCmd.Flags().StringP("output", "o", "", "arquivo de saída (padrão: <data>.<formato>; - para stdout)")
+	exportCmd.Flags().StringP("format", "f", "json", "formato de exportação (json, csv, ndjson)")
+	exportCmd.Flags().String("data", "alerts", "dados exportados (alerts, snapshots)")
+	exportCmd.Flags().String("since", "", "início do período (RFC3339, YYYY-MM-DD ou duração: 24h, 7d)")
+	exportCmd.Flags().String("until", "", "fim do período (RFC3339, YYYY-MM-DD ou duração: 24h, 7d)")
+	exportCmd.Flags().StringP("cluster", "c", "", "filtra por cluster")
+	exportCmd.Flags().StringP("namespace", "n", "", "filtra por namespace")
+	exportCmd.Flags().String("hpa", "", "filtra por HPA")
+	exportCmd.Flags().StringSlice("severity", nil, "filtra alertas por severidade (info, warning, critical; repetível)")
+	exportCmd.Flags().StringSlice("type", nil, "filtra alertas por tipo de anomalia (ex: MaxedOut, CPUSpike; repetível)")
 
 	// Test command flags
 	testCmd.Flags().StringP("cluster", "c", "", "cluster context (obrigatório)")
@@ -599,4 +1191,17 @@ func init() {
 	rootCmd.AddCommand(clustersCmd)
 	rootCmd.AddCommand(exportCmd)
 	rootCmd.AddCommand(testCmd)
+
+	thresholdsCmd.AddCommand(thresholdsExplainCmd)
+	rootCmd.AddCommand(thresholdsCmd)
+
+	// Silences create flags
+	silencesCreateCmd.Flags().Duration("duration", 0, "duração do silence (ex: 30m, 2h; padrão: alertmanager.silences.duration_minutes)")
+	silencesCreateCmd.Flags().String("comment", "", "motivo do silence (obrigatório)")
+	silencesCreateCmd.Flags().String("by", "", "autor do silence (padrão: alerts.ack_user ou usuário do SO)")
+
+	silencesCmd.AddCommand(silencesListCmd)
+	silencesCmd.AddCommand(silencesCreateCmd)
+	silencesCmd.AddCommand(silencesExpireCmd)
+	rootCmd.AddCommand(silencesCmd)
 }
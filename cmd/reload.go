@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/alertmanager"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/monitor"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// configReloader aplica no monitoramento em execução as mudanças recarregáveis do watchdog.yaml
+type configReloader struct {
+	session    *monitor.MonitoringSession
+	watcher    *monitor.Watcher
+	thresholds *config.ThresholdManager
+	syncer     *alertmanager.Syncer // nil = Alertmanager desabilitado
+}
+
+// apply implementa config.ApplyFunc: primeiro prepara o que pode falhar (descoberta de clusters,
+// clients dos endpoints novos) e só então troca thresholds, scan interval, exclusões e endpoints
+func (c *configReloader) apply(old, updated *models.WatchdogConfig) error {
+	monitored := make(map[string]bool)
+	for _, cluster := range c.watcher.Clusters() {
+		monitored[cluster.Name] = true
+	}
+
+	added, removed, err := clusterChanges(old, updated, monitored)
+	if err != nil {
+		return err
+	}
+
+	removing := make(map[string]bool, len(removed))
+	for _, name := range removed {
+		removing[name] = true
+	}
+
+	var promClients map[string]*prometheus.Client
+	if updated.PrometheusEnabled {
+		promClients = make(map[string]*prometheus.Client)
+		for _, cluster := range changedEndpoints(old.PrometheusEndpoints, updated.PrometheusEndpoints) {
+			endpoint, ok := updated.PrometheusEndpoints[cluster]
+			if !ok || !monitored[cluster] || removing[cluster] {
+				continue
+			}
+			client, err := prometheus.NewClient(cluster, endpoint)
+			if err != nil {
+				return fmt.Errorf("prometheus endpoint de %s: %w", cluster, err)
+			}
+			promClients[cluster] = client
+		}
+	}
+
+	var amClients map[string]*alertmanager.Client
+	if c.syncer != nil {
+		amClients = make(map[string]*alertmanager.Client)
+		for _, cluster := range changedEndpoints(old.AlertmanagerEndpoints, updated.AlertmanagerEndpoints) {
+			endpoint, ok := updated.AlertmanagerEndpoints[cluster]
+			if !ok || !monitored[cluster] || removing[cluster] {
+				continue
+			}
+			client, err := alertmanager.NewClient(cluster, endpoint)
+			if err != nil {
+				return fmt.Errorf("alertmanager endpoint de %s: %w", cluster, err)
+			}
+			amClients[cluster] = client
+		}
+	}
+
+	// A partir daqui nada rejeita o reload: thresholds e overrides já validados pelo Reloader
+	// (overrides novos são validados de novo contra os globais em uso, antes de qualquer troca)
+	if err := c.applyThresholds(old, updated); err != nil {
+		return err
+	}
+	c.watcher.SetConfig(updated)
+
+	for _, name := range removed {
+		c.watcher.RemoveCluster(name)
+		if c.syncer != nil {
+			c.syncer.RemoveClient(name)
+		}
+	}
+
+	if updated.PrometheusEnabled {
+		for _, cluster := range changedEndpoints(old.PrometheusEndpoints, updated.PrometheusEndpoints) {
+			if !monitored[cluster] || removing[cluster] {
+				continue
+			}
+			if client, ok := promClients[cluster]; ok {
+				c.watcher.SetEnricher(cluster, client)
+				continue
+			}
+			// Endpoint removido da config: auto-discovery (se ativo) ou sem Prometheus
+			c.watcher.SetEnricher(cluster, enricherOrNil(connectPrometheus(updated, c.session, cluster)))
+		}
+	}
+
+	if c.syncer != nil {
+		for _, cluster := range changedEndpoints(old.AlertmanagerEndpoints, updated.AlertmanagerEndpoints) {
+			if !monitored[cluster] || removing[cluster] {
+				continue
+			}
+			if client, ok := amClients[cluster]; ok {
+				c.syncer.AddClient(client)
+				continue
+			}
+			c.syncer.RemoveClient(cluster)
+			for _, client := range alertmanagerClients(updated, []models.ClusterInfo{{Name: cluster}}) {
+				c.syncer.AddClient(client)
+			}
+		}
+	}
+
+	// Clusters que saíram de clusters.exclude: conexão com falha só é registrada (como no startup)
+	for i := range added {
+		cluster := &added[i]
+		if err := c.watcher.AddCluster(cluster); err != nil {
+			log.Warn().Err(err).Str("cluster", cluster.Name).Msg("Failed to add cluster on config reload, skipping")
+			continue
+		}
+
+		if updated.PrometheusEnabled {
+			if client := connectPrometheus(updated, c.session, cluster.Name); client != nil {
+				c.watcher.SetEnricher(cluster.Name, client)
+			}
+		}
+		if c.syncer != nil {
+			for _, client := range alertmanagerClients(updated, []models.ClusterInfo{*cluster}) {
+				c.syncer.AddClient(client)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyThresholds troca thresholds e overrides só se mudaram no arquivo: um reload de outros
+// campos (scan interval, exclusões, endpoints) não reverte edições ao vivo ainda não gravadas.
+// Globais alterados trocam os dois juntos (sem globais novos com overrides antigos).
+func (c *configReloader) applyThresholds(old, updated *models.WatchdogConfig) error {
+	switch {
+	case old.Thresholds != updated.Thresholds:
+		if c.thresholds.Get() != old.Thresholds {
+			log.Warn().Msg("Thresholds changed in the configuration file, replacing unsaved live edits")
+		}
+		return c.thresholds.UpdateAllWithOverrides(updated.Thresholds, updated.ThresholdOverrides)
+	case !reflect.DeepEqual(old.ThresholdOverrides, updated.ThresholdOverrides):
+		return c.thresholds.SetOverrides(updated.ThresholdOverrides)
+	}
+	return nil
+}
+
+// clusterChanges clusters a adicionar (saíram de clusters.exclude) e a remover (entraram)
+func clusterChanges(old, updated *models.WatchdogConfig, monitored map[string]bool) ([]models.ClusterInfo, []string, error) {
+	if reflect.DeepEqual(old.ExcludeClusters, updated.ExcludeClusters) {
+		return nil, nil, nil
+	}
+
+	wasExcluded := make(map[string]bool)
+	for _, name := range old.ExcludeClusters {
+		wasExcluded[name] = true
+	}
+
+	excluded := make(map[string]bool)
+	var removed []string
+	for _, name := range updated.ExcludeClusters {
+		excluded[name] = true
+		if monitored[name] {
+			removed = append(removed, name)
+		}
+	}
+
+	var added []models.ClusterInfo
+	// Só redescobre se algum cluster pode ter saído da exclusão
+	if len(wasExcluded) > 0 {
+		discovered, err := config.DiscoverClusters(updated)
+		if err != nil {
+			return nil, nil, fmt.Errorf("falha ao descobrir clusters: %w", err)
+		}
+		for _, cluster := range discovered {
+			if wasExcluded[cluster.Name] && !excluded[cluster.Name] && !monitored[cluster.Name] {
+				added = append(added, cluster)
+			}
+		}
+	}
+
+	sort.Strings(removed)
+	return added, removed, nil
+}
+
+// changedEndpoints clusters cujo endpoint foi adicionado, alterado ou removido
+func changedEndpoints(old, updated map[string]string) []string {
+	var changed []string
+	for cluster, endpoint := range updated {
+		if old[cluster] != endpoint {
+			changed = append(changed, cluster)
+		}
+	}
+	for cluster := range old {
+		if _, ok := updated[cluster]; !ok {
+			changed = append(changed, cluster)
+		}
+	}
+
+	sort.Strings(changed)
+	return changed
+}
+
+// enricherOrNil evita guardar um *prometheus.Client nil na interface (SetEnricher(nil) remove)
+func enricherOrNil(client *prometheus.Client) monitor.SnapshotEnricher {
+	if client == nil {
+		return nil
+	}
+	return client
+}
@@ -0,0 +1,172 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Alert alerta retornado por GET /api/v2/alerts
+type Alert struct {
+	Annotations  map[string]string `json:"annotations"`
+	EndsAt       time.Time         `json:"endsAt"`
+	StartsAt     time.Time         `json:"startsAt"`
+	UpdatedAt    time.Time         `json:"updatedAt"`
+	Fingerprint  string            `json:"fingerprint"`
+	Status       AlertStatus       `json:"status"`
+	Labels       map[string]string `json:"labels"`
+	GeneratorURL string            `json:"generatorURL"`
+}
+
+// AlertStatus estado de um alerta no Alertmanager
+type AlertStatus struct {
+	State       string   `json:"state"` // "active", "suppressed", "unprocessed"
+	SilencedBy  []string `json:"silencedBy"`
+	InhibitedBy []string `json:"inhibitedBy"`
+}
+
+// Client client HTTP da API v2 do Alertmanager de um cluster
+type Client struct {
+	cluster    string
+	endpoint   string
+	httpClient *http.Client
+}
+
+// NewClient cria um client para o Alertmanager do cluster (ex: "http://alertmanager.monitoring.svc:9093")
+func NewClient(cluster, endpoint string) (*Client, error) {
+	if !strings.HasPrefix(endpoint, "http://") && !strings.HasPrefix(endpoint, "https://") {
+		return nil, fmt.Errorf("invalid Alertmanager endpoint %q: must start with http:// or https://", endpoint)
+	}
+
+	return &Client{
+		cluster:    cluster,
+		endpoint:   strings.TrimSuffix(endpoint, "/"),
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// Cluster retorna o cluster do client
+func (c *Client) Cluster() string {
+	return c.cluster
+}
+
+// Endpoint retorna o endpoint do Alertmanager
+func (c *Client) Endpoint() string {
+	return c.endpoint
+}
+
+// TestConnection verifica se o Alertmanager responde em /api/v2/status
+func (c *Client) TestConnection(ctx context.Context) error {
+	var status struct {
+		VersionInfo map[string]string `json:"versionInfo"`
+	}
+	if err := c.get(ctx, "/api/v2/status", &status); err != nil {
+		return fmt.Errorf("connection test failed: %w", err)
+	}
+
+	log.Debug().
+		Str("cluster", c.cluster).
+		Str("endpoint", c.endpoint).
+		Str("version", status.VersionInfo["version"]).
+		Msg("Alertmanager connection test successful")
+
+	return nil
+}
+
+// GetAlerts retorna os alertas do Alertmanager (ativos, silenciados e inibidos)
+func (c *Client) GetAlerts(ctx context.Context) ([]Alert, error) {
+	var alerts []Alert
+	if err := c.get(ctx, "/api/v2/alerts", &alerts); err != nil {
+		return nil, fmt.Errorf("failed to get alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// get executa um GET e decodifica a resposta JSON
+func (c *Client) get(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// do executa a requisição (body e out opcionais, em JSON) e valida o status da resposta
+func (c *Client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s request: %w", path, err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if len(msg) > 0 {
+			return fmt.Errorf("alertmanager returned status %d for %s %s: %s", resp.StatusCode, method, path, strings.TrimSpace(string(msg)))
+		}
+		return fmt.Errorf("alertmanager returned status %d for %s %s", resp.StatusCode, method, path)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s response: %w", path, err)
+	}
+
+	return nil
+}
+
+// Discover tenta os discovery_patterns (ex: "alertmanager.monitoring.svc:9093") e retorna
+// um client para o primeiro Alertmanager que responder
+func Discover(ctx context.Context, cluster string, patterns []string) (*Client, error) {
+	for _, pattern := range patterns {
+		endpoint := pattern
+		if !strings.Contains(endpoint, "://") {
+			endpoint = "http://" + endpoint
+		}
+
+		client, err := NewClient(cluster, endpoint)
+		if err != nil {
+			continue
+		}
+
+		testCtx, cancel := context.WithTimeout(ctx, 3*time.Second)
+		err = client.TestConnection(testCtx)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		log.Info().
+			Str("cluster", cluster).
+			Str("endpoint", endpoint).
+			Msg("Alertmanager discovered")
+
+		return client, nil
+	}
+
+	return nil, fmt.Errorf("no Alertmanager found for cluster %s (tried %d patterns)", cluster, len(patterns))
+}
@@ -0,0 +1,148 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+const alertsResponse = `[
+  {
+    "annotations": {"summary": "HPA production/api has been running at max replicas"},
+    "startsAt": "2025-01-10T10:00:00Z",
+    "endsAt": "2025-01-10T11:00:00Z",
+    "updatedAt": "2025-01-10T10:05:00Z",
+    "fingerprint": "abc123",
+    "status": {"state": "active", "silencedBy": [], "inhibitedBy": []},
+    "labels": {"alertname": "KubeHpaMaxedOut", "namespace": "production", "horizontalpodautoscaler": "api", "severity": "warning"},
+    "generatorURL": "http://prometheus/graph?g0.expr=kube_hpa"
+  },
+  {
+    "annotations": {"message": "Namespace quota exceeded"},
+    "startsAt": "2025-01-10T10:01:00Z",
+    "fingerprint": "def456",
+    "status": {"state": "suppressed", "silencedBy": ["s1"], "inhibitedBy": []},
+    "labels": {"alertname": "KubeQuotaExceeded", "namespace": "production", "severity": "critical"}
+  }
+]`
+
+// newAlertmanagerServer cria um Alertmanager fake respondendo /api/v2/status e /api/v2/alerts
+func newAlertmanagerServer(t *testing.T, alerts string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/status":
+			w.Write([]byte(`{"versionInfo": {"version": "0.27.0"}}`))
+		case "/api/v2/alerts":
+			w.Write([]byte(alerts))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGetAlerts(t *testing.T) {
+	server := newAlertmanagerServer(t, alertsResponse)
+
+	client, err := NewClient("test-cluster", server.URL+"/")
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+
+	if err := client.TestConnection(context.Background()); err != nil {
+		t.Errorf("TestConnection failed: %v", err)
+	}
+
+	alerts, err := client.GetAlerts(context.Background())
+	if err != nil {
+		t.Fatalf("GetAlerts failed: %v", err)
+	}
+
+	if len(alerts) != 2 {
+		t.Fatalf("Expected 2 alerts, got %d", len(alerts))
+	}
+	if alerts[0].Fingerprint != "abc123" || alerts[0].Labels[LabelHPA] != "api" {
+		t.Errorf("Unexpected first alert: %+v", alerts[0])
+	}
+	if alerts[1].Status.State != "suppressed" || len(alerts[1].Status.SilencedBy) != 1 {
+		t.Errorf("Expected suppressed alert with silence, got %+v", alerts[1].Status)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	if _, err := NewClient("test-cluster", "alertmanager:9093"); err == nil {
+		t.Error("Expected error for endpoint without scheme")
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-cluster", server.URL)
+	if _, err := client.GetAlerts(context.Background()); err == nil || !strings.Contains(err.Error(), "503") {
+		t.Errorf("Expected status 503 error, got %v", err)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	server := newAlertmanagerServer(t, "[]")
+
+	// Primeiro pattern não responde; o segundo (sem scheme) sim
+	patterns := []string{"http://127.0.0.1:1", strings.TrimPrefix(server.URL, "http://")}
+
+	client, err := Discover(context.Background(), "test-cluster", patterns)
+	if err != nil {
+		t.Fatalf("Discover failed: %v", err)
+	}
+	if client.Endpoint() != server.URL || client.Cluster() != "test-cluster" {
+		t.Errorf("Expected client for %s, got %s", server.URL, client.Endpoint())
+	}
+
+	if _, err := Discover(context.Background(), "test-cluster", patterns[:1]); err == nil {
+		t.Error("Expected error when no pattern responds")
+	}
+}
+
+func TestToUnifiedAlert(t *testing.T) {
+	server := newAlertmanagerServer(t, alertsResponse)
+	client, _ := NewClient("test-cluster", server.URL)
+
+	alerts, err := client.GetAlerts(context.Background())
+	if err != nil {
+		t.Fatalf("GetAlerts failed: %v", err)
+	}
+
+	maxed := ToUnifiedAlert("test-cluster", alerts[0])
+	if maxed.Source != models.AlertSourceAlertmanager || maxed.Type != models.AnomalyMaxedOut {
+		t.Errorf("Expected Alertmanager MaxedOut alert, got %s/%s", maxed.Source, maxed.Type)
+	}
+	if maxed.Cluster != "test-cluster" || maxed.Namespace != "production" || maxed.HPAName != "api" {
+		t.Errorf("Unexpected target %s/%s/%s", maxed.Cluster, maxed.Namespace, maxed.HPAName)
+	}
+	if maxed.Severity != models.SeverityWarning || maxed.Status != models.AlertStatusActive {
+		t.Errorf("Expected active Warning, got %s/%s", maxed.Severity, maxed.Status)
+	}
+	if maxed.Fingerprint != "abc123" || maxed.GeneratorURL == "" || maxed.Context == nil {
+		t.Errorf("Expected fingerprint, generator URL and kubectl context, got %+v", maxed)
+	}
+
+	quota := ToUnifiedAlert("test-cluster", alerts[1])
+	if quota.Type != models.AnomalyExternal || quota.AlertName != "KubeQuotaExceeded" {
+		t.Errorf("Expected External KubeQuotaExceeded, got %s/%s", quota.Type, quota.AlertName)
+	}
+	if quota.Severity != models.SeverityCritical || quota.Status != models.AlertStatusSuppressed {
+		t.Errorf("Expected suppressed Critical, got %s/%s", quota.Severity, quota.Status)
+	}
+	if quota.Summary != "Namespace quota exceeded" || quota.HPAName != "" || quota.Context != nil {
+		t.Errorf("Expected message as summary and no HPA context, got %+v", quota)
+	}
+}
@@ -0,0 +1,131 @@
+package alertmanager
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// DefaultHPAMatchers define "relacionado a HPA" quando a config não declara hpa_matchers:
+// alertas KubeHpa* do kube-prometheus ou qualquer alerta com o label horizontalpodautoscaler
+var DefaultHPAMatchers = []models.AlertMatcher{
+	{AlertName: "^KubeHpa"},
+	{Label: LabelHPA},
+}
+
+// Filter decide quais alertas do Alertmanager são ingeridos
+type Filter struct {
+	OnlyHPARelated  bool
+	ExcludeSilenced bool
+	MinSeverity     models.AlertSeverity
+	matchers        []matcher
+}
+
+// matcher AlertMatcher com as regex compiladas
+type matcher struct {
+	alertName *regexp.Regexp
+	label     string
+	value     *regexp.Regexp
+}
+
+// NewFilter cria um filtro; matchers vazio = DefaultHPAMatchers
+func NewFilter(onlyHPARelated, excludeSilenced bool, minSeverity models.AlertSeverity, matchers []models.AlertMatcher) (*Filter, error) {
+	if len(matchers) == 0 {
+		matchers = DefaultHPAMatchers
+	}
+
+	f := &Filter{
+		OnlyHPARelated:  onlyHPARelated,
+		ExcludeSilenced: excludeSilenced,
+		MinSeverity:     minSeverity,
+	}
+
+	for i, m := range matchers {
+		compiled, err := compileMatcher(m)
+		if err != nil {
+			return nil, fmt.Errorf("invalid HPA matcher %d: %w", i, err)
+		}
+		f.matchers = append(f.matchers, compiled)
+	}
+
+	return f, nil
+}
+
+// FilterFromConfig cria o filtro a partir de monitoring.alertmanager.filters
+func FilterFromConfig(cfg *models.WatchdogConfig) (*Filter, error) {
+	return NewFilter(cfg.AlertmanagerOnlyHPARelated, cfg.AlertmanagerExcludeSilenced, cfg.AlertmanagerMinSeverity, cfg.AlertmanagerHPAMatchers)
+}
+
+// compileMatcher compila as regex de um AlertMatcher
+func compileMatcher(m models.AlertMatcher) (matcher, error) {
+	if m.AlertName == "" && m.Label == "" {
+		return matcher{}, fmt.Errorf("alertname or label is required")
+	}
+
+	compiled := matcher{label: m.Label}
+
+	if m.AlertName != "" {
+		re, err := regexp.Compile(m.AlertName)
+		if err != nil {
+			return matcher{}, fmt.Errorf("invalid alertname regex: %w", err)
+		}
+		compiled.alertName = re
+	}
+
+	if m.Value != "" {
+		re, err := regexp.Compile(m.Value)
+		if err != nil {
+			return matcher{}, fmt.Errorf("invalid value regex: %w", err)
+		}
+		compiled.value = re
+	}
+
+	return compiled, nil
+}
+
+// matches verifica se os labels satisfazem todos os campos do matcher
+func (m matcher) matches(labels map[string]string) bool {
+	if m.alertName != nil && !m.alertName.MatchString(labels[LabelAlertName]) {
+		return false
+	}
+
+	if m.label != "" {
+		value, exists := labels[m.label]
+		if !exists {
+			return false
+		}
+		if m.value != nil && !m.value.MatchString(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HPARelated verifica se o alerta casa com algum dos matchers
+func (f *Filter) HPARelated(labels map[string]string) bool {
+	for _, m := range f.matchers {
+		if m.matches(labels) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow verifica se o alerta passa pelos filtros de severidade, silence e relação com HPA
+func (f *Filter) Allow(alert models.UnifiedAlert) bool {
+	if alert.Severity < f.MinSeverity {
+		return false
+	}
+
+	if f.ExcludeSilenced && len(alert.SilencedBy) > 0 {
+		return false
+	}
+
+	if f.OnlyHPARelated && !f.HPARelated(alert.Labels) {
+		return false
+	}
+
+	return true
+}
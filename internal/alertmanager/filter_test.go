@@ -0,0 +1,95 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+func TestFilterHPARelated(t *testing.T) {
+	f, err := NewFilter(true, false, models.SeverityInfo, nil)
+	if err != nil {
+		t.Fatalf("NewFilter failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   bool
+	}{
+		{"KubeHpa alertname", map[string]string{"alertname": "KubeHpaMaxedOut"}, true},
+		{"hpa label", map[string]string{"alertname": "CustomScaling", "horizontalpodautoscaler": "api"}, true},
+		{"unrelated", map[string]string{"alertname": "KubeNodeNotReady", "node": "n1"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := f.Allow(models.UnifiedAlert{Labels: tt.labels}); got != tt.want {
+				t.Errorf("Allow(%v) = %v, want %v", tt.labels, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFilterCustomMatchers(t *testing.T) {
+	f, err := NewFilter(true, false, models.SeverityInfo, []models.AlertMatcher{
+		{AlertName: "^KubeDeploymentReplicasMismatch$", Label: "namespace", Value: "^prod"},
+	})
+	if err != nil {
+		t.Fatalf("NewFilter failed: %v", err)
+	}
+
+	if !f.HPARelated(map[string]string{"alertname": "KubeDeploymentReplicasMismatch", "namespace": "production"}) {
+		t.Error("Expected alert matching all matcher fields to be HPA related")
+	}
+	if f.HPARelated(map[string]string{"alertname": "KubeDeploymentReplicasMismatch", "namespace": "staging"}) {
+		t.Error("Expected value regex mismatch not to be HPA related")
+	}
+	if f.HPARelated(map[string]string{"alertname": "KubeHpaMaxedOut", "horizontalpodautoscaler": "api"}) {
+		t.Error("Expected custom matchers to replace the defaults")
+	}
+
+	if _, err := NewFilter(true, false, models.SeverityInfo, []models.AlertMatcher{{AlertName: "("}}); err == nil {
+		t.Error("Expected error for invalid regex")
+	}
+}
+
+func TestFilterSeverityAndSilenced(t *testing.T) {
+	f, _ := NewFilter(false, true, models.SeverityWarning, nil)
+
+	if f.Allow(models.UnifiedAlert{Severity: models.SeverityInfo}) {
+		t.Error("Expected Info alert below min_severity to be dropped")
+	}
+	if !f.Allow(models.UnifiedAlert{Severity: models.SeverityCritical, Labels: map[string]string{"alertname": "KubeNodeNotReady"}}) {
+		t.Error("Expected unrelated Critical alert allowed when only_hpa_related is off")
+	}
+	if f.Allow(models.UnifiedAlert{Severity: models.SeverityCritical, SilencedBy: []string{"s1"}}) {
+		t.Error("Expected silenced alert to be dropped with exclude_silenced")
+	}
+}
+
+func TestSyncerAppliesFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(alertsResponse))
+	}))
+	defer server.Close()
+
+	sink := &fakeSink{}
+	syncer := NewSyncer(sink, 0)
+	client, _ := NewClient("test-cluster", server.URL)
+	syncer.AddClient(client)
+
+	// KubeQuotaExceeded não é relacionado a HPA e está silenciado
+	f, _ := NewFilter(true, true, models.SeverityInfo, nil)
+	syncer.SetFilter(f)
+
+	if count, err := syncer.SyncCluster(context.Background(), client); err != nil || count != 1 {
+		t.Fatalf("Expected 1 alert after filtering, got %d (%v)", count, err)
+	}
+	if len(sink.firing) != 1 || sink.firing[0].AlertName != "KubeHpaMaxedOut" {
+		t.Errorf("Expected only KubeHpaMaxedOut delivered, got %+v", sink.firing)
+	}
+}
@@ -0,0 +1,91 @@
+package alertmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// Labels usados no mapeamento para UnifiedAlert (convenções do kube-state-metrics / kube-prometheus)
+const (
+	LabelAlertName = "alertname"
+	LabelNamespace = "namespace"
+	LabelHPA       = "horizontalpodautoscaler"
+	LabelSeverity  = "severity"
+)
+
+// knownAnomalies alertas do kube-prometheus com anomalia equivalente no watchdog
+var knownAnomalies = map[string]models.AnomalyType{
+	"KubeHpaMaxedOut":         models.AnomalyMaxedOut,
+	"KubeHpaReplicasMismatch": models.AnomalyScalingStuck,
+}
+
+// ToUnifiedAlert converte um alerta do Alertmanager do cluster para UnifiedAlert
+func ToUnifiedAlert(cluster string, alert Alert) models.UnifiedAlert {
+	alertName := alert.Labels[LabelAlertName]
+
+	anomaly, known := knownAnomalies[alertName]
+	if !known {
+		anomaly = models.AnomalyExternal
+	}
+
+	summary := firstNonEmpty(alert.Annotations["summary"], alert.Annotations["message"], alertName)
+	description := firstNonEmpty(alert.Annotations["description"], alert.Annotations["message"])
+
+	unified := models.UnifiedAlert{
+		Source:       models.AlertSourceAlertmanager,
+		Severity:     ParseSeverity(alert.Labels[LabelSeverity]),
+		Type:         anomaly,
+		Cluster:      cluster,
+		Namespace:    alert.Labels[LabelNamespace],
+		HPAName:      alert.Labels[LabelHPA],
+		Timestamp:    alert.StartsAt,
+		Summary:      summary,
+		Description:  description,
+		AlertName:    alertName,
+		Labels:       alert.Labels,
+		Fingerprint:  alert.Fingerprint,
+		GeneratorURL: alert.GeneratorURL,
+		Status:       mapState(alert.Status.State),
+		SilencedBy:   alert.Status.SilencedBy,
+	}
+
+	if unified.Namespace != "" && unified.HPAName != "" {
+		unified.Context = &models.AlertContext{
+			KubectlCommand: fmt.Sprintf("kubectl -n %s describe hpa %s", unified.Namespace, unified.HPAName),
+		}
+	}
+
+	return unified
+}
+
+// ParseSeverity converte o label severity (critical, warning, info, none) para AlertSeverity
+func ParseSeverity(severity string) models.AlertSeverity {
+	switch strings.ToLower(severity) {
+	case "critical", "error", "page":
+		return models.SeverityCritical
+	case "warning", "warn":
+		return models.SeverityWarning
+	default:
+		return models.SeverityInfo
+	}
+}
+
+// mapState converte o estado do Alertmanager (silenciados e inibidos ficam "suppressed")
+func mapState(state string) string {
+	if state == "suppressed" {
+		return models.AlertStatusSuppressed
+	}
+	return models.AlertStatusActive
+}
+
+// firstNonEmpty retorna o primeiro valor não vazio
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
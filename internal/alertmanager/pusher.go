@@ -0,0 +1,252 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultResendInterval usado quando a config não define push.resend_interval_seconds
+	DefaultResendInterval = time.Minute
+
+	// resendFactor endsAt de alertas ativos = agora + resendFactor × intervalo (como o Prometheus),
+	// para o Alertmanager resolver sozinho se o watchdog parar de reenviar
+	resendFactor = 4
+
+	// LabelSource identifica alertas enviados pelo watchdog (ignorados na ingestão)
+	LabelSource = "source"
+
+	// SourceWatchdog valor de LabelSource nos alertas enviados pelo watchdog
+	SourceWatchdog = "hpa-watchdog"
+
+	// LabelCluster e LabelAnomaly labels adicionais dos alertas enviados
+	LabelCluster = "cluster"
+	LabelAnomaly = "anomaly"
+
+	// alertNamePrefix prefixo do alertname dos alertas enviados (ex: HPAWatchdogReplicaOscillation)
+	alertNamePrefix = "HPAWatchdog"
+)
+
+// AlertLister fornece os alertas ativos e resolvidos. Implementado por *monitor.AlertStore.
+type AlertLister interface {
+	Active() []models.UnifiedAlert
+	Resolved() []models.UnifiedAlert
+}
+
+// PostableAlert alerta enviado em POST /api/v2/alerts
+type PostableAlert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt,omitempty"`
+	EndsAt       time.Time         `json:"endsAt,omitempty"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// PostAlerts envia alertas ao Alertmanager (POST /api/v2/alerts)
+func (c *Client) PostAlerts(ctx context.Context, alerts []PostableAlert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+	return c.do(ctx, http.MethodPost, "/api/v2/alerts", alerts, nil)
+}
+
+// ToPostableAlert converte um alerta do watchdog para o formato do Alertmanager.
+// Alertas ativos recebem endsAt = now + validFor; resolvidos, endsAt = ResolvedAt.
+func ToPostableAlert(alert models.UnifiedAlert, now time.Time, validFor time.Duration) PostableAlert {
+	labels := map[string]string{
+		LabelAlertName: alertNamePrefix + alert.Type.String(),
+		LabelCluster:   alert.Cluster,
+		LabelAnomaly:   alert.Type.String(),
+		LabelSeverity:  strings.ToLower(alert.Severity.String()),
+		LabelSource:    SourceWatchdog,
+	}
+	if alert.Namespace != "" {
+		labels[LabelNamespace] = alert.Namespace
+	}
+	if alert.HPAName != "" {
+		labels[LabelHPA] = alert.HPAName
+	}
+
+	annotations := map[string]string{
+		"summary": alert.Summary,
+	}
+	if alert.Description != "" {
+		annotations["description"] = alert.Description
+	}
+	if alert.Context != nil && alert.Context.KubectlCommand != "" {
+		annotations["kubectl"] = alert.Context.KubectlCommand
+	}
+
+	postable := PostableAlert{
+		Labels:      labels,
+		Annotations: annotations,
+		StartsAt:    alert.Timestamp.UTC(),
+		EndsAt:      now.Add(validFor).UTC(),
+	}
+	if alert.ResolvedAt != nil {
+		postable.EndsAt = alert.ResolvedAt.UTC()
+	}
+
+	return postable
+}
+
+// pushable indica se o alerta deve ser enviado: só anomalias do watchdog ainda não reportadas
+// pelo Alertmanager (alertas mesclados já existem lá)
+func pushable(alert *models.UnifiedAlert) bool {
+	return alert.Source == models.AlertSourceWatchdog && len(alert.MergedFrom) == 0
+}
+
+// Pusher reenvia periodicamente os alertas do watchdog ao Alertmanager do cluster do alerta,
+// para que rotas, receivers, silences e inibições funcionem como para os demais alertas
+type Pusher struct {
+	clients  ClientSource
+	alerts   AlertLister
+	interval time.Duration
+	sent     map[string]bool // IDs enviados como ativos (para enviar a resolução)
+	mu       sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewPusher cria um pusher (interval <= 0 = DefaultResendInterval)
+func NewPusher(clients ClientSource, alerts AlertLister, interval time.Duration) *Pusher {
+	if interval <= 0 {
+		interval = DefaultResendInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Pusher{
+		clients:  clients,
+		alerts:   alerts,
+		interval: interval,
+		sent:     make(map[string]bool),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// Start inicia o envio em background (imediato e depois a cada intervalo)
+func (p *Pusher) Start() {
+	p.wg.Add(1)
+	go p.loop()
+
+	log.Info().
+		Dur("interval", p.interval).
+		Msg("Alertmanager pusher started")
+}
+
+// Stop encerra o envio
+func (p *Pusher) Stop() {
+	p.cancel()
+	p.wg.Wait()
+
+	log.Info().Msg("Alertmanager pusher stopped")
+}
+
+// loop envia imediatamente e depois a cada intervalo
+func (p *Pusher) loop() {
+	defer p.wg.Done()
+
+	p.Push(p.ctx, time.Now())
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-ticker.C:
+			p.Push(p.ctx, time.Now())
+		}
+	}
+}
+
+// Push envia os alertas ativos do watchdog e a resolução dos que já foram enviados.
+// Retorna quantos alertas foram aceitos pelo Alertmanager.
+func (p *Pusher) Push(ctx context.Context, now time.Time) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	validFor := resendFactor * p.interval
+	byCluster := make(map[string][]PostableAlert)
+	ids := make(map[string][]string)
+	resolved := make(map[string]bool)
+	seen := make(map[string]bool)
+
+	for _, alert := range p.alerts.Active() {
+		seen[alert.ID] = true
+		if !pushable(&alert) {
+			continue
+		}
+		byCluster[alert.Cluster] = append(byCluster[alert.Cluster], ToPostableAlert(alert, now, validFor))
+		ids[alert.Cluster] = append(ids[alert.Cluster], alert.ID)
+	}
+
+	for _, alert := range p.alerts.Resolved() {
+		seen[alert.ID] = true
+		if !p.sent[alert.ID] || alert.ResolvedAt == nil {
+			continue
+		}
+		byCluster[alert.Cluster] = append(byCluster[alert.Cluster], ToPostableAlert(alert, now, validFor))
+		ids[alert.Cluster] = append(ids[alert.Cluster], alert.ID)
+		resolved[alert.ID] = true
+	}
+
+	// Alertas que saíram do store (ou foram mesclados) expiram no Alertmanager pelo endsAt
+	for id := range p.sent {
+		if !seen[id] {
+			delete(p.sent, id)
+		}
+	}
+
+	clusters := make([]string, 0, len(byCluster))
+	for cluster := range byCluster {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	pushed := 0
+	for _, cluster := range clusters {
+		client, ok := p.clients.Client(cluster)
+		if !ok {
+			continue
+		}
+
+		if err := client.PostAlerts(ctx, byCluster[cluster]); err != nil {
+			log.Warn().
+				Err(err).
+				Str("cluster", cluster).
+				Int("alerts", len(byCluster[cluster])).
+				Msg("Failed to push alerts to Alertmanager (will retry)")
+			continue
+		}
+
+		for _, id := range ids[cluster] {
+			if resolved[id] {
+				delete(p.sent, id)
+			} else {
+				p.sent[id] = true
+			}
+		}
+		pushed += len(byCluster[cluster])
+	}
+
+	if pushed > 0 {
+		log.Debug().
+			Int("alerts", pushed).
+			Int("resolved", len(resolved)).
+			Msg("Alerts pushed to Alertmanager")
+	}
+
+	return pushed
+}
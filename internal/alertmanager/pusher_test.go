@@ -0,0 +1,165 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// fakeLister AlertLister com listas fixas
+type fakeLister struct {
+	active   []models.UnifiedAlert
+	resolved []models.UnifiedAlert
+}
+
+func (f *fakeLister) Active() []models.UnifiedAlert   { return f.active }
+func (f *fakeLister) Resolved() []models.UnifiedAlert { return f.resolved }
+
+// newPushServer Alertmanager fake que guarda os lotes recebidos em POST /api/v2/alerts
+func newPushServer(t *testing.T) (*httptest.Server, func() [][]PostableAlert) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var batches [][]PostableAlert
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v2/alerts" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var batch []PostableAlert
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		mu.Lock()
+		batches = append(batches, batch)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() [][]PostableAlert {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+}
+
+func TestToPostableAlert(t *testing.T) {
+	now := time.Now()
+	alert := models.UnifiedAlert{
+		Source:    models.AlertSourceWatchdog,
+		Severity:  models.SeverityCritical,
+		Type:      models.AnomalyReplicaOscillation,
+		Cluster:   "prod",
+		Namespace: "production",
+		HPAName:   "api",
+		Timestamp: now.Add(-5 * time.Minute),
+		Summary:   "HPA oscilando",
+	}
+
+	postable := ToPostableAlert(alert, now, 4*time.Minute)
+
+	want := map[string]string{
+		"alertname":               "HPAWatchdogReplicaOscillation",
+		"cluster":                 "prod",
+		"namespace":               "production",
+		"horizontalpodautoscaler": "api",
+		"anomaly":                 "ReplicaOscillation",
+		"severity":                "critical",
+		"source":                  "hpa-watchdog",
+	}
+	for name, value := range want {
+		if postable.Labels[name] != value {
+			t.Errorf("Label %s = %q, want %q", name, postable.Labels[name], value)
+		}
+	}
+
+	if !postable.StartsAt.Equal(alert.Timestamp) || !postable.EndsAt.Equal(now.Add(4*time.Minute)) {
+		t.Errorf("Unexpected startsAt/endsAt %s/%s", postable.StartsAt, postable.EndsAt)
+	}
+	if postable.Annotations["summary"] != "HPA oscilando" {
+		t.Errorf("Expected summary annotation, got %v", postable.Annotations)
+	}
+
+	resolvedAt := now.Add(-time.Minute)
+	alert.ResolvedAt = &resolvedAt
+	if postable := ToPostableAlert(alert, now, 4*time.Minute); !postable.EndsAt.Equal(resolvedAt) {
+		t.Errorf("Expected endsAt = ResolvedAt for resolved alert, got %s", postable.EndsAt)
+	}
+}
+
+func TestPusherLifecycle(t *testing.T) {
+	server, batches := newPushServer(t)
+
+	client, _ := NewClient("prod", server.URL)
+	syncer := NewSyncer(nil, 0)
+	syncer.AddClient(client)
+
+	start := time.Now()
+	oscillation := models.UnifiedAlert{
+		ID: "prod/production/api/ReplicaOscillation", Source: models.AlertSourceWatchdog,
+		Type: models.AnomalyReplicaOscillation, Cluster: "prod", Namespace: "production", HPAName: "api", Timestamp: start,
+	}
+	merged := models.UnifiedAlert{
+		ID: "prod/production/api/MaxedOut", Source: models.AlertSourceWatchdog, Type: models.AnomalyMaxedOut,
+		Cluster: "prod", MergedFrom: []string{"prod/alertmanager/abc"},
+	}
+	external := models.UnifiedAlert{ID: "prod/alertmanager/def", Source: models.AlertSourceAlertmanager, Cluster: "prod"}
+	otherCluster := models.UnifiedAlert{ID: "staging/x/y/ReplicaOscillation", Source: models.AlertSourceWatchdog, Cluster: "staging"}
+
+	lister := &fakeLister{active: []models.UnifiedAlert{oscillation, merged, external, otherCluster}}
+	pusher := NewPusher(syncer, lister, time.Minute)
+
+	// Só o alerta do watchdog não mesclado, no Alertmanager do seu cluster
+	if pushed := pusher.Push(context.Background(), start); pushed != 1 {
+		t.Fatalf("Expected 1 alert pushed, got %d", pushed)
+	}
+
+	// Reenvio periódico enquanto ativo, com endsAt renovado
+	pusher.Push(context.Background(), start.Add(time.Minute))
+	got := batches()
+	if len(got) != 2 || !got[1][0].EndsAt.Equal(start.Add(5*time.Minute)) {
+		t.Fatalf("Expected re-send with renewed endsAt, got %+v", got)
+	}
+
+	// Resolução enviada uma vez
+	resolvedAt := start.Add(2 * time.Minute)
+	oscillation.ResolvedAt = &resolvedAt
+	lister.active = nil
+	lister.resolved = []models.UnifiedAlert{oscillation}
+
+	if pushed := pusher.Push(context.Background(), start.Add(3*time.Minute)); pushed != 1 {
+		t.Fatalf("Expected resolution pushed, got %d", pushed)
+	}
+	if last := batches()[2][0]; !last.EndsAt.Equal(resolvedAt) {
+		t.Errorf("Expected endsAt = ResolvedAt, got %s", last.EndsAt)
+	}
+	if pushed := pusher.Push(context.Background(), start.Add(4*time.Minute)); pushed != 0 {
+		t.Errorf("Expected resolution sent only once, got %d", pushed)
+	}
+}
+
+func TestSyncerIgnoresPushedAlerts(t *testing.T) {
+	server := newAlertmanagerServer(t, `[
+  {"fingerprint": "a", "status": {"state": "active"}, "labels": {"alertname": "HPAWatchdogReplicaOscillation", "source": "hpa-watchdog"}},
+  {"fingerprint": "b", "status": {"state": "active"}, "labels": {"alertname": "KubeHpaMaxedOut"}}
+]`)
+
+	sink := &fakeSink{}
+	syncer := NewSyncer(sink, 0)
+	client, _ := NewClient("prod", server.URL)
+	syncer.AddClient(client)
+
+	if count, _ := syncer.SyncCluster(context.Background(), client); count != 1 || sink.firing[0].Fingerprint != "b" {
+		t.Errorf("Expected alerts pushed by the watchdog ignored on ingestion, got %+v", sink.firing)
+	}
+}
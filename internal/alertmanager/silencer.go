@@ -0,0 +1,221 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultSilenceDuration usado quando a config não define silence_duration_minutes
+const DefaultSilenceDuration = 2 * time.Hour
+
+// AckStore store onde reconhecimentos e silences são registrados. Implementado por *monitor.AlertStore.
+type AckStore interface {
+	Get(id string) (models.UnifiedAlert, bool)
+	Acknowledge(id, by string, now time.Time) error
+	AddSilence(id, silenceID string) error
+}
+
+// ClientSource fornece os clients de Alertmanager por cluster. Implementado por *Syncer.
+type ClientSource interface {
+	Client(cluster string) (*Client, bool)
+	Clients() []*Client
+}
+
+// ClusterSilence silence do watchdog em um cluster
+type ClusterSilence struct {
+	Cluster string
+	Silence
+}
+
+// Silencer reconhece alertas e, para alertas do Alertmanager, cria o silence equivalente
+type Silencer struct {
+	clients  ClientSource
+	store    AckStore
+	duration time.Duration
+}
+
+// NewSilencer cria um silencer (duration <= 0 = DefaultSilenceDuration)
+func NewSilencer(clients ClientSource, store AckStore, duration time.Duration) *Silencer {
+	if duration <= 0 {
+		duration = DefaultSilenceDuration
+	}
+
+	return &Silencer{
+		clients:  clients,
+		store:    store,
+		duration: duration,
+	}
+}
+
+// Duration retorna a duração padrão dos silences
+func (s *Silencer) Duration() time.Duration {
+	return s.duration
+}
+
+// Acknowledge reconhece o alerta e, se ele veio do Alertmanager, cria um silence com os labels
+// do alerta (duration <= 0 = duração padrão; comment obrigatório). Retorna o ID do silence
+// ("" para alertas do watchdog). Se o silence falhar, o alerta não é reconhecido.
+func (s *Silencer) Acknowledge(ctx context.Context, id, by, comment string, duration time.Duration, now time.Time) (string, error) {
+	alert, exists := s.store.Get(id)
+	if !exists {
+		return "", fmt.Errorf("alert %s not found", id)
+	}
+
+	if alert.Source != models.AlertSourceAlertmanager {
+		return "", s.store.Acknowledge(id, by, now)
+	}
+
+	client, ok := s.clients.Client(alert.Cluster)
+	if !ok {
+		return "", fmt.Errorf("no Alertmanager client for cluster %s", alert.Cluster)
+	}
+	if len(alert.Labels) == 0 {
+		return "", fmt.Errorf("alert %s has no labels to silence", id)
+	}
+	if duration <= 0 {
+		duration = s.duration
+	}
+
+	silenceID, err := s.silence(ctx, client, alert.Labels, by, comment, duration, now)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.store.Acknowledge(id, by, now); err != nil {
+		return silenceID, err
+	}
+	if err := s.store.AddSilence(id, silenceID); err != nil {
+		return silenceID, err
+	}
+
+	log.Info().
+		Str("cluster", alert.Cluster).
+		Str("alert", alert.AlertName).
+		Str("silence", silenceID).
+		Str("by", by).
+		Dur("duration", duration).
+		Msg("Alert acknowledged and silenced")
+
+	return silenceID, nil
+}
+
+// Create cria um silence para um alerta ativo no Alertmanager do cluster, buscado pelo fingerprint
+// (aceita também o ID do watchdog, "cluster/alertmanager/<fingerprint>"). Não passa pelo AckStore:
+// um watchdog em execução vê o silence em SilencedBy no próximo sync.
+func (s *Silencer) Create(ctx context.Context, cluster, alertID, by, comment string, duration time.Duration, now time.Time) (string, error) {
+	client, ok := s.clients.Client(cluster)
+	if !ok {
+		return "", fmt.Errorf("no Alertmanager client for cluster %s", cluster)
+	}
+
+	if duration <= 0 {
+		duration = s.duration
+	}
+
+	fingerprint := strings.TrimPrefix(alertID, cluster+"/alertmanager/")
+
+	alerts, err := client.GetAlerts(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, alert := range alerts {
+		if alert.Fingerprint != fingerprint {
+			continue
+		}
+
+		silenceID, err := s.silence(ctx, client, alert.Labels, by, comment, duration, now)
+		if err != nil {
+			return "", err
+		}
+
+		log.Info().
+			Str("cluster", cluster).
+			Str("alert", alert.Labels["alertname"]).
+			Str("silence", silenceID).
+			Str("by", by).
+			Dur("duration", duration).
+			Msg("Silence created")
+
+		return silenceID, nil
+	}
+
+	return "", fmt.Errorf("alert %s not found in Alertmanager of cluster %s", fingerprint, cluster)
+}
+
+// silence cria o silence com os labels do alerta; o comentário é obrigatório
+func (s *Silencer) silence(ctx context.Context, client *Client, labels map[string]string, by, comment string, duration time.Duration, now time.Time) (string, error) {
+	if strings.TrimSpace(comment) == "" {
+		return "", fmt.Errorf("silence comment is required")
+	}
+	if len(labels) == 0 {
+		return "", fmt.Errorf("alert has no labels to silence")
+	}
+
+	return client.CreateSilence(ctx, SilenceMatchers(labels), now, duration, by, comment)
+}
+
+// List retorna os silences não expirados criados pelo watchdog em todos os clusters.
+// Clusters com erro são ignorados (logados); o erro só é retornado se nenhum cluster responder.
+func (s *Silencer) List(ctx context.Context) ([]ClusterSilence, error) {
+	var silences []ClusterSilence
+	var lastErr error
+	ok := 0
+
+	for _, client := range s.clients.Clients() {
+		clusterSilences, err := client.WatchdogSilences(ctx)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("cluster", client.Cluster()).
+				Msg("Failed to list silences")
+			lastErr = err
+			continue
+		}
+		ok++
+
+		for _, silence := range clusterSilences {
+			silences = append(silences, ClusterSilence{Cluster: client.Cluster(), Silence: silence})
+		}
+	}
+
+	if ok == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+
+	return silences, nil
+}
+
+// Expire expira um silence criado pelo watchdog. Silences de outros autores são recusados.
+func (s *Silencer) Expire(ctx context.Context, cluster, silenceID string) error {
+	client, ok := s.clients.Client(cluster)
+	if !ok {
+		return fmt.Errorf("no Alertmanager client for cluster %s", cluster)
+	}
+
+	silences, err := client.WatchdogSilences(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, silence := range silences {
+		if silence.ID == silenceID {
+			if err := client.ExpireSilence(ctx, silenceID); err != nil {
+				return err
+			}
+
+			log.Info().
+				Str("cluster", cluster).
+				Str("silence", silenceID).
+				Msg("Silence expired")
+			return nil
+		}
+	}
+
+	return fmt.Errorf("silence %s not found among active watchdog silences in cluster %s", silenceID, cluster)
+}
@@ -0,0 +1,150 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SilenceCommentPrefix identifica os silences criados pelo watchdog
+const SilenceCommentPrefix = "[hpa-watchdog]"
+
+// Estados de um silence no Alertmanager
+const (
+	SilenceStateActive  = "active"
+	SilenceStatePending = "pending"
+	SilenceStateExpired = "expired"
+)
+
+// Matcher matcher de label de um silence
+type Matcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+// Silence silence retornado por GET /api/v2/silences
+type Silence struct {
+	ID        string        `json:"id"`
+	Matchers  []Matcher     `json:"matchers"`
+	StartsAt  time.Time     `json:"startsAt"`
+	EndsAt    time.Time     `json:"endsAt"`
+	UpdatedAt time.Time     `json:"updatedAt"`
+	CreatedBy string        `json:"createdBy"`
+	Comment   string        `json:"comment"`
+	Status    SilenceStatus `json:"status"`
+}
+
+// SilenceStatus estado de um silence
+type SilenceStatus struct {
+	State string `json:"state"` // "active", "pending", "expired"
+}
+
+// postableSilence corpo de POST /api/v2/silences
+type postableSilence struct {
+	Matchers  []Matcher `json:"matchers"`
+	StartsAt  time.Time `json:"startsAt"`
+	EndsAt    time.Time `json:"endsAt"`
+	CreatedBy string    `json:"createdBy"`
+	Comment   string    `json:"comment"`
+}
+
+// IsWatchdog indica se o silence foi criado pelo watchdog
+func (s Silence) IsWatchdog() bool {
+	return strings.HasPrefix(s.Comment, SilenceCommentPrefix)
+}
+
+// Expired indica se o silence já expirou
+func (s Silence) Expired() bool {
+	return s.Status.State == SilenceStateExpired
+}
+
+// SilenceMatchers cria matchers de igualdade para todos os labels do alerta (ordenados por nome)
+func SilenceMatchers(labels map[string]string) []Matcher {
+	matchers := make([]Matcher, 0, len(labels))
+	for name, value := range labels {
+		matchers = append(matchers, Matcher{Name: name, Value: value, IsEqual: true})
+	}
+
+	sort.Slice(matchers, func(i, j int) bool {
+		return matchers[i].Name < matchers[j].Name
+	})
+
+	return matchers
+}
+
+// CreateSilence cria um silence de start até start+duration e retorna o ID.
+// O comentário recebe o SilenceCommentPrefix para identificar silences do watchdog.
+func (c *Client) CreateSilence(ctx context.Context, matchers []Matcher, start time.Time, duration time.Duration, createdBy, comment string) (string, error) {
+	if len(matchers) == 0 {
+		return "", fmt.Errorf("failed to create silence: no matchers")
+	}
+	if duration <= 0 {
+		return "", fmt.Errorf("failed to create silence: duration must be positive, got %s", duration)
+	}
+
+	body := postableSilence{
+		Matchers:  matchers,
+		StartsAt:  start.UTC(),
+		EndsAt:    start.Add(duration).UTC(),
+		CreatedBy: createdBy,
+		Comment:   strings.TrimSpace(SilenceCommentPrefix + " " + comment),
+	}
+
+	var resp struct {
+		SilenceID string `json:"silenceID"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v2/silences", body, &resp); err != nil {
+		return "", fmt.Errorf("failed to create silence: %w", err)
+	}
+
+	return resp.SilenceID, nil
+}
+
+// GetSilences retorna todos os silences do Alertmanager (incluindo expirados)
+func (c *Client) GetSilences(ctx context.Context) ([]Silence, error) {
+	var silences []Silence
+	if err := c.get(ctx, "/api/v2/silences", &silences); err != nil {
+		return nil, fmt.Errorf("failed to get silences: %w", err)
+	}
+	return silences, nil
+}
+
+// WatchdogSilences retorna os silences não expirados criados pelo watchdog, do que expira primeiro ao último
+func (c *Client) WatchdogSilences(ctx context.Context) ([]Silence, error) {
+	silences, err := c.GetSilences(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var watchdog []Silence
+	for _, silence := range silences {
+		if silence.IsWatchdog() && !silence.Expired() {
+			watchdog = append(watchdog, silence)
+		}
+	}
+
+	sort.Slice(watchdog, func(i, j int) bool {
+		return watchdog[i].EndsAt.Before(watchdog[j].EndsAt)
+	})
+
+	return watchdog, nil
+}
+
+// ExpireSilence expira um silence (DELETE /api/v2/silence/{id})
+func (c *Client) ExpireSilence(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("failed to expire silence: empty ID")
+	}
+
+	if err := c.do(ctx, http.MethodDelete, "/api/v2/silence/"+url.PathEscape(id), nil, nil); err != nil {
+		return fmt.Errorf("failed to expire silence %s: %w", id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,237 @@
+package alertmanager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// fakeSilences Alertmanager fake que guarda silences em memória
+type fakeSilences struct {
+	silences []Silence
+	alerts   []Alert
+	mu       sync.Mutex
+}
+
+func newSilencesServer(t *testing.T, existing ...Silence) (*fakeSilences, *httptest.Server) {
+	t.Helper()
+
+	fake := &fakeSilences{silences: existing}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/silences":
+			var body postableSilence
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			id := fmt.Sprintf("silence-%d", len(fake.silences)+1)
+			fake.silences = append(fake.silences, Silence{
+				ID:        id,
+				Matchers:  body.Matchers,
+				StartsAt:  body.StartsAt,
+				EndsAt:    body.EndsAt,
+				CreatedBy: body.CreatedBy,
+				Comment:   body.Comment,
+				Status:    SilenceStatus{State: SilenceStateActive},
+			})
+			json.NewEncoder(w).Encode(map[string]string{"silenceID": id})
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/silences":
+			json.NewEncoder(w).Encode(fake.silences)
+
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/alerts":
+			json.NewEncoder(w).Encode(fake.alerts)
+
+		case r.Method == http.MethodDelete && strings.HasPrefix(r.URL.Path, "/api/v2/silence/"):
+			id := strings.TrimPrefix(r.URL.Path, "/api/v2/silence/")
+			for i := range fake.silences {
+				if fake.silences[i].ID == id {
+					fake.silences[i].Status.State = SilenceStateExpired
+					return
+				}
+			}
+			http.Error(w, "silence not found", http.StatusNotFound)
+
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return fake, server
+}
+
+// fakeAckStore AckStore em memória
+type fakeAckStore struct {
+	alerts map[string]*models.UnifiedAlert
+}
+
+func (f *fakeAckStore) Get(id string) (models.UnifiedAlert, bool) {
+	alert, ok := f.alerts[id]
+	if !ok {
+		return models.UnifiedAlert{}, false
+	}
+	return *alert, true
+}
+
+func (f *fakeAckStore) Acknowledge(id, by string, now time.Time) error {
+	f.alerts[id].Acknowledged = true
+	f.alerts[id].AckedBy = by
+	f.alerts[id].AckedAt = &now
+	return nil
+}
+
+func (f *fakeAckStore) AddSilence(id, silenceID string) error {
+	f.alerts[id].SilencedBy = append(f.alerts[id].SilencedBy, silenceID)
+	return nil
+}
+
+func TestSilenceLifecycle(t *testing.T) {
+	manual := Silence{ID: "manual", Comment: "maintenance", Status: SilenceStatus{State: SilenceStateActive}}
+	fake, server := newSilencesServer(t, manual)
+
+	client, _ := NewClient("test-cluster", server.URL)
+	syncer := NewSyncer(nil, 0)
+	syncer.AddClient(client)
+
+	store := &fakeAckStore{alerts: map[string]*models.UnifiedAlert{
+		"am": {
+			ID:        "am",
+			Source:    models.AlertSourceAlertmanager,
+			Cluster:   "test-cluster",
+			AlertName: "KubeHpaMaxedOut",
+			Labels:    map[string]string{"alertname": "KubeHpaMaxedOut", "namespace": "production"},
+		},
+		"wd": {ID: "wd", Source: models.AlertSourceWatchdog, Cluster: "test-cluster"},
+	}}
+
+	silencer := NewSilencer(syncer, store, 0)
+	now := time.Now()
+
+	id, err := silencer.Acknowledge(context.Background(), "am", "alice", "investigating", 0, now)
+	if err != nil {
+		t.Fatalf("Acknowledge failed: %v", err)
+	}
+
+	alert := store.alerts["am"]
+	if !alert.Acknowledged || alert.AckedBy != "alice" || len(alert.SilencedBy) != 1 || alert.SilencedBy[0] != id {
+		t.Errorf("Expected alert acknowledged by alice and silenced by %s, got %+v", id, alert)
+	}
+
+	created := fake.silences[1]
+	if len(created.Matchers) != 2 || created.Matchers[0].Name != "alertname" || !created.Matchers[0].IsEqual {
+		t.Errorf("Expected equality matchers for all labels sorted by name, got %+v", created.Matchers)
+	}
+	if created.EndsAt.Sub(created.StartsAt) != DefaultSilenceDuration || created.Comment != "[hpa-watchdog] investigating" {
+		t.Errorf("Unexpected silence %+v", created)
+	}
+
+	// Silence sem comentário é recusado e o alerta não é reconhecido
+	store.alerts["am2"] = &models.UnifiedAlert{ID: "am2", Source: models.AlertSourceAlertmanager, Cluster: "test-cluster",
+		Labels: map[string]string{"alertname": "KubeHpaReplicasMismatch"}}
+	if _, err := silencer.Acknowledge(context.Background(), "am2", "alice", "  ", 0, now); err == nil || !strings.Contains(err.Error(), "comment is required") {
+		t.Errorf("Expected empty comment rejected, got %v", err)
+	}
+	if store.alerts["am2"].Acknowledged || len(fake.silences) != 2 {
+		t.Error("Expected no ack and no silence without comment")
+	}
+
+	// Alertas do watchdog são apenas reconhecidos
+	if id, err := silencer.Acknowledge(context.Background(), "wd", "alice", "", 0, now); err != nil || id != "" {
+		t.Errorf("Expected plain ack for watchdog alert, got %q/%v", id, err)
+	}
+
+	// List retorna só os silences do watchdog
+	silences, err := silencer.List(context.Background())
+	if err != nil || len(silences) != 1 || silences[0].ID != id || silences[0].Cluster != "test-cluster" {
+		t.Fatalf("Expected only the watchdog silence, got %+v (%v)", silences, err)
+	}
+
+	if err := silencer.Expire(context.Background(), "test-cluster", "manual"); err == nil {
+		t.Error("Expected error expiring a silence not created by the watchdog")
+	}
+	if err := silencer.Expire(context.Background(), "test-cluster", silences[0].ID); err != nil {
+		t.Fatalf("Expire failed: %v", err)
+	}
+
+	if silences, _ := silencer.List(context.Background()); len(silences) != 0 {
+		t.Errorf("Expected no active watchdog silences after expire, got %+v", silences)
+	}
+}
+
+func TestSilencerCreate(t *testing.T) {
+	fake, server := newSilencesServer(t)
+	fake.alerts = []Alert{{Fingerprint: "fp1", Labels: map[string]string{"alertname": "KubeHpaMaxedOut", "hpa": "api"}}}
+
+	client, _ := NewClient("test-cluster", server.URL)
+	syncer := NewSyncer(nil, 0)
+	syncer.AddClient(client)
+	silencer := NewSilencer(syncer, nil, time.Hour)
+
+	// ID do watchdog ou fingerprint, com duração escolhida
+	id, err := silencer.Create(context.Background(), "test-cluster", "test-cluster/alertmanager/fp1", "alice", "deploy em andamento", 30*time.Minute, time.Now())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	created := fake.silences[0]
+	if created.ID != id || created.EndsAt.Sub(created.StartsAt) != 30*time.Minute || created.Comment != "[hpa-watchdog] deploy em andamento" || len(created.Matchers) != 2 {
+		t.Errorf("Unexpected silence %+v", created)
+	}
+
+	tests := []struct {
+		name    string
+		cluster string
+		alert   string
+		comment string
+		wantErr string
+	}{
+		{"empty comment", "test-cluster", "fp1", "", "comment is required"},
+		{"unknown alert", "test-cluster", "fp2", "x", "alert fp2 not found"},
+		{"unknown cluster", "staging", "fp1", "x", "no Alertmanager client"},
+	}
+	for _, tt := range tests {
+		_, err := silencer.Create(context.Background(), tt.cluster, tt.alert, "alice", tt.comment, 0, time.Now())
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+	if len(fake.silences) != 1 {
+		t.Errorf("Expected no extra silences, got %d", len(fake.silences))
+	}
+}
+
+func TestAcknowledgeSilenceFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad matchers", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, _ := NewClient("test-cluster", server.URL)
+	syncer := NewSyncer(nil, 0)
+	syncer.AddClient(client)
+
+	store := &fakeAckStore{alerts: map[string]*models.UnifiedAlert{
+		"am": {ID: "am", Source: models.AlertSourceAlertmanager, Cluster: "test-cluster", Labels: map[string]string{"alertname": "X"}},
+	}}
+
+	_, err := NewSilencer(syncer, store, time.Hour).Acknowledge(context.Background(), "am", "alice", "investigating", 0, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "bad matchers") {
+		t.Errorf("Expected Alertmanager error message, got %v", err)
+	}
+	if store.alerts["am"].Acknowledged {
+		t.Error("Expected alert not acknowledged when the silence fails")
+	}
+}
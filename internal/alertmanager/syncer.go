@@ -0,0 +1,265 @@
+package alertmanager
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultSyncInterval usado quando a config não define sync_interval_seconds
+const DefaultSyncInterval = 30 * time.Second
+
+// AlertSink recebe os alertas disparando em um escopo. Implementado por *monitor.AlertStore.
+type AlertSink interface {
+	Sync(scope string, firing []models.UnifiedAlert, now time.Time) []models.UnifiedAlert
+	Correlate() // Recalcula incidentes depois de um lote de Syncs
+}
+
+// Scope escopo dos alertas do Alertmanager de um cluster no AlertSink
+func Scope(cluster string) string {
+	return "alertmanager/" + cluster
+}
+
+// ClusterSyncStatus resultado do último sync de um cluster
+type ClusterSyncStatus struct {
+	Cluster  string
+	LastSync time.Time
+	Alerts   int
+	Err      error
+}
+
+// Syncer consulta o Alertmanager de cada cluster a cada intervalo e entrega os alertas ao sink.
+// Alertas que somem do Alertmanager são resolvidos pelo sink; falhas mantêm os alertas do último sync.
+type Syncer struct {
+	clients  map[string]*Client // cluster -> client
+	sink     AlertSink
+	filter   *Filter
+	interval time.Duration
+	status   map[string]ClusterSyncStatus
+	mu       sync.RWMutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewSyncer cria um syncer (interval <= 0 = DefaultSyncInterval)
+func NewSyncer(sink AlertSink, interval time.Duration) *Syncer {
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Syncer{
+		clients:  make(map[string]*Client),
+		sink:     sink,
+		interval: interval,
+		status:   make(map[string]ClusterSyncStatus),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// AddClient registra o Alertmanager de um cluster
+func (s *Syncer) AddClient(client *Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.clients[client.Cluster()] = client
+}
+
+// RemoveClient remove o Alertmanager de um cluster e resolve no sink os alertas que vieram dele
+func (s *Syncer) RemoveClient(cluster string) bool {
+	s.mu.Lock()
+	_, exists := s.clients[cluster]
+	delete(s.clients, cluster)
+	delete(s.status, cluster)
+	s.mu.Unlock()
+
+	if exists && s.sink != nil {
+		s.sink.Sync(Scope(cluster), nil, time.Now())
+		s.sink.Correlate()
+	}
+	return exists
+}
+
+// SetFilter define o filtro aplicado aos alertas antes de entregá-los ao sink (nil = sem filtro)
+func (s *Syncer) SetFilter(filter *Filter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.filter = filter
+}
+
+// Client retorna o client do Alertmanager do cluster
+func (s *Syncer) Client(cluster string) (*Client, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	client, ok := s.clients[cluster]
+	return client, ok
+}
+
+// Clients retorna os clients registrados, ordenados por cluster
+func (s *Syncer) Clients() []*Client {
+	s.mu.RLock()
+	clients := make([]*Client, 0, len(s.clients))
+	for _, client := range s.clients {
+		clients = append(clients, client)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].Cluster() < clients[j].Cluster()
+	})
+
+	return clients
+}
+
+// Start inicia o polling em background (sync imediato e depois a cada intervalo)
+func (s *Syncer) Start() {
+	s.wg.Add(1)
+	go s.loop()
+
+	log.Info().
+		Dur("interval", s.interval).
+		Int("clusters", len(s.clients)).
+		Msg("Alertmanager syncer started")
+}
+
+// Stop encerra o polling e aguarda o sync em andamento
+func (s *Syncer) Stop() {
+	s.cancel()
+	s.wg.Wait()
+
+	log.Info().Msg("Alertmanager syncer stopped")
+}
+
+// loop executa um sync imediato e depois um a cada intervalo
+func (s *Syncer) loop() {
+	defer s.wg.Done()
+
+	s.SyncAll(s.ctx)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.SyncAll(s.ctx)
+		}
+	}
+}
+
+// SyncAll sincroniza todos os clusters em paralelo. Retorna o total de alertas entregues.
+func (s *Syncer) SyncAll(ctx context.Context) int {
+	clients := s.Clients()
+
+	var total int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, client := range clients {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+
+			count, err := s.SyncCluster(ctx, client)
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			total += count
+			mu.Unlock()
+		}(client)
+	}
+
+	wg.Wait()
+
+	// Uma correlação por rodada, não por cluster
+	s.sink.Correlate()
+	return total
+}
+
+// SyncCluster busca os alertas de um cluster, aplica o filtro e entrega ao sink.
+// Retorna quantos alertas foram entregues.
+func (s *Syncer) SyncCluster(ctx context.Context, client *Client) (int, error) {
+	cluster := client.Cluster()
+	now := time.Now()
+
+	alerts, err := client.GetAlerts(ctx)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("cluster", cluster).
+			Str("endpoint", client.Endpoint()).
+			Msg("Failed to sync Alertmanager alerts (keeping previous alerts)")
+
+		s.setStatus(ClusterSyncStatus{Cluster: cluster, LastSync: now, Err: err})
+		return 0, err
+	}
+
+	s.mu.RLock()
+	filter := s.filter
+	s.mu.RUnlock()
+
+	unified := make([]models.UnifiedAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		// Alertas enviados pelo próprio watchdog (Pusher) já estão no store
+		if alert.Labels[LabelSource] == SourceWatchdog {
+			continue
+		}
+
+		converted := ToUnifiedAlert(cluster, alert)
+		if filter != nil && !filter.Allow(converted) {
+			continue
+		}
+		unified = append(unified, converted)
+	}
+
+	opened := s.sink.Sync(Scope(cluster), unified, now)
+
+	s.setStatus(ClusterSyncStatus{Cluster: cluster, LastSync: now, Alerts: len(unified)})
+
+	log.Debug().
+		Str("cluster", cluster).
+		Int("alerts", len(unified)).
+		Int("filtered", len(alerts)-len(unified)).
+		Int("new", len(opened)).
+		Msg("Alertmanager alerts synced")
+
+	return len(unified), nil
+}
+
+// setStatus registra o resultado do último sync do cluster
+func (s *Syncer) setStatus(status ClusterSyncStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.status[status.Cluster] = status
+}
+
+// Status retorna o resultado do último sync de cada cluster, ordenado por cluster
+func (s *Syncer) Status() []ClusterSyncStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status := make([]ClusterSyncStatus, 0, len(s.status))
+	for _, st := range s.status {
+		status = append(status, st)
+	}
+
+	sort.Slice(status, func(i, j int) bool {
+		return status[i].Cluster < status[j].Cluster
+	})
+
+	return status
+}
@@ -0,0 +1,78 @@
+package alertmanager
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// fakeSink registra as chamadas de Sync
+type fakeSink struct {
+	calls        int
+	correlations int
+	scope        string
+	firing       []models.UnifiedAlert
+}
+
+func (f *fakeSink) Sync(scope string, firing []models.UnifiedAlert, now time.Time) []models.UnifiedAlert {
+	f.calls++
+	f.scope = scope
+	f.firing = firing
+	return firing
+}
+
+func (f *fakeSink) Correlate() {
+	f.correlations++
+}
+
+func TestSyncerSyncCluster(t *testing.T) {
+	var failing atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(alertsResponse))
+	}))
+	defer server.Close()
+
+	sink := &fakeSink{}
+	syncer := NewSyncer(sink, 0)
+	client, _ := NewClient("test-cluster", server.URL)
+	syncer.AddClient(client)
+
+	if total := syncer.SyncAll(context.Background()); total != 2 {
+		t.Fatalf("Expected 2 alerts synced, got %d", total)
+	}
+	if sink.calls != 1 || sink.scope != "alertmanager/test-cluster" || len(sink.firing) != 2 {
+		t.Errorf("Expected one Sync in the cluster scope with 2 alerts, got %+v", sink)
+	}
+	if sink.correlations != 1 {
+		t.Errorf("Expected one correlation per SyncAll, got %d", sink.correlations)
+	}
+
+	// Falha no Alertmanager não resolve os alertas existentes
+	failing.Store(true)
+	if _, err := syncer.SyncCluster(context.Background(), client); err == nil {
+		t.Error("Expected error from failing Alertmanager")
+	}
+	if sink.calls != 1 {
+		t.Errorf("Expected no Sync on fetch error, got %d calls", sink.calls)
+	}
+
+	status := syncer.Status()
+	if len(status) != 1 || status[0].Err == nil {
+		t.Errorf("Expected last sync error in status, got %+v", status)
+	}
+
+	// Cluster removido no reload: alertas do escopo são resolvidos
+	if !syncer.RemoveClient("test-cluster") || sink.calls != 2 || sink.firing != nil || len(syncer.Clients()) != 0 {
+		t.Errorf("Expected client removed and scope resolved, got %+v", sink)
+	}
+}
@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// historyStep intervalo entre pontos dos históricos do Prometheus (CPUHistory, MemoryHistory,
+// ReplicaHistory): step das query range
+const historyStep = 30 * time.Second
+
+// Detector detecta um tipo de anomalia em um HPA.
+// Retorna nil quando a anomalia não está presente.
+type Detector interface {
+	Type() models.AnomalyType
+	Detect(in Input, t models.Thresholds) *models.UnifiedAlert
+}
+
+// StatefulDetector detector que mantém estado por HPA entre análises
+// (ex: desde quando uma condição persiste). Forget descarta o estado de um HPA removido.
+type StatefulDetector interface {
+	Detector
+	Forget(key string)
+}
+
+// Input dados de um HPA entregues aos detectores
+type Input struct {
+	Current *models.HPASnapshot
+	History []models.HPASnapshot // Histórico do watcher, mais antigo primeiro (pode incluir Current)
+}
+
+// Previous retorna o snapshot anterior ao atual (nil se não houver histórico)
+func (in Input) Previous() *models.HPASnapshot {
+	for i := len(in.History) - 1; i >= 0; i-- {
+		if in.History[i].Timestamp.Before(in.Current.Timestamp) {
+			return &in.History[i]
+		}
+	}
+	return nil
+}
+
+// Engine executa os detectores registrados com os thresholds efetivos de cada HPA no ThresholdManager
+type Engine struct {
+	thresholds *config.ThresholdManager
+	detectors  []Detector
+	mu         sync.RWMutex
+}
+
+// NewEngine cria um engine com os detectores informados (ou DefaultDetectors se nenhum)
+func NewEngine(thresholds *config.ThresholdManager, detectors ...Detector) *Engine {
+	if len(detectors) == 0 {
+		detectors = DefaultDetectors()
+	}
+
+	return &Engine{
+		thresholds: thresholds,
+		detectors:  detectors,
+	}
+}
+
+// DefaultDetectors retorna um detector para cada models.AnomalyType
+func DefaultDetectors() []Detector {
+	return []Detector{
+		&replicaSpikeDetector{},
+		&replicaDropDetector{},
+		&cpuSpikeDetector{},
+		&memorySpikeDetector{},
+		&resourceChangeDetector{},
+		&configChangeDetector{},
+		&scalingStuckDetector{},
+		&targetMissDetector{},
+		&oscillationDetector{},
+		&maxedOutDetector{},
+		&errorRateDetector{},
+		&latencyDetector{},
+	}
+}
+
+// Register adiciona um detector ao engine
+func (e *Engine) Register(d Detector) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.detectors = append(e.detectors, d)
+}
+
+// Thresholds retorna o ThresholdManager usado pelo engine
+func (e *Engine) Thresholds() *config.ThresholdManager {
+	return e.thresholds
+}
+
+// Analyze executa todos os detectores e retorna os alertas ordenados por severidade
+func (e *Engine) Analyze(in Input) []models.UnifiedAlert {
+	if in.Current == nil {
+		return nil
+	}
+
+	// Thresholds efetivos do HPA (globais + overrides de cluster, namespace e HPA)
+	t := e.thresholds.For(in.Current.Cluster, in.Current.Namespace, in.Current.Name)
+
+	e.mu.RLock()
+	detectors := e.detectors
+	e.mu.RUnlock()
+
+	var alerts []models.UnifiedAlert
+	for _, d := range detectors {
+		if alert := d.Detect(in, t); alert != nil {
+			alerts = append(alerts, *alert)
+		}
+	}
+
+	sort.SliceStable(alerts, func(i, j int) bool {
+		return alerts[i].Severity > alerts[j].Severity
+	})
+
+	return alerts
+}
+
+// Forget descarta o estado dos detectores para um HPA ("cluster/namespace/name") removido
+func (e *Engine) Forget(key string) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, d := range e.detectors {
+		if stateful, ok := d.(StatefulDetector); ok {
+			stateful.Forget(key)
+		}
+	}
+}
+
+// AnalyzeSnapshot analisa um snapshot isolado (sem histórico do watcher)
+func (e *Engine) AnalyzeSnapshot(snapshot *models.HPASnapshot) []models.UnifiedAlert {
+	return e.Analyze(Input{Current: snapshot})
+}
+
+// AlertID monta o ID de um alerta do watchdog: um por HPA e tipo de anomalia
+func AlertID(cluster, namespace, name string, anomaly models.AnomalyType) string {
+	return models.HPAKey(cluster, namespace, name) + "/" + anomaly.String()
+}
+
+// newAlert cria um alerta do watchdog para o snapshot
+func newAlert(s *models.HPASnapshot, anomaly models.AnomalyType, severity models.AlertSeverity, summary, description string) *models.UnifiedAlert {
+	snapshot := *s
+
+	return &models.UnifiedAlert{
+		ID:          AlertID(s.Cluster, s.Namespace, s.Name, anomaly),
+		Source:      models.AlertSourceWatchdog,
+		Severity:    severity,
+		Type:        anomaly,
+		Cluster:     s.Cluster,
+		Namespace:   s.Namespace,
+		HPAName:     s.Name,
+		Timestamp:   s.Timestamp,
+		Summary:     summary,
+		Description: description,
+		Status:      models.AlertStatusActive,
+		Snapshot:    &snapshot,
+		Context: &models.AlertContext{
+			RequestRate:    s.RequestRate,
+			ErrorRate:      s.ErrorRate,
+			P95Latency:     s.P95Latency,
+			KubectlCommand: fmt.Sprintf("kubectl -n %s describe hpa %s", s.Namespace, s.Name),
+		},
+	}
+}
@@ -0,0 +1,317 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// newTestSnapshot cria um snapshot saudável (nenhum detector dispara)
+func newTestSnapshot() *models.HPASnapshot {
+	return &models.HPASnapshot{
+		Timestamp:       time.Now(),
+		Cluster:         "test-cluster",
+		Namespace:       "production",
+		Name:            "api",
+		MinReplicas:     2,
+		MaxReplicas:     10,
+		CurrentReplicas: 4,
+		DesiredReplicas: 4,
+		CPUTarget:       70,
+		MemoryTarget:    80,
+		CPUCurrent:      65,
+		MemoryCurrent:   70,
+		CPURequest:      "500m",
+		MemoryRequest:   "512Mi",
+	}
+}
+
+// findAlert retorna o alerta do tipo informado
+func findAlert(alerts []models.UnifiedAlert, anomaly models.AnomalyType) *models.UnifiedAlert {
+	for i := range alerts {
+		if alerts[i].Type == anomaly {
+			return &alerts[i]
+		}
+	}
+	return nil
+}
+
+func TestDefaultDetectorsCoverAllTypes(t *testing.T) {
+	covered := make(map[models.AnomalyType]bool)
+	for _, d := range DefaultDetectors() {
+		if covered[d.Type()] {
+			t.Errorf("Duplicate detector for %s", d.Type())
+		}
+		covered[d.Type()] = true
+	}
+
+	for anomaly := models.AnomalyReplicaSpike; anomaly <= models.AnomalyHighLatency; anomaly++ {
+		if !covered[anomaly] {
+			t.Errorf("No detector for %s", anomaly)
+		}
+	}
+}
+
+func TestEngineHealthySnapshot(t *testing.T) {
+	engine := NewEngine(config.NewThresholdManager(config.DefaultThresholds()))
+
+	if alerts := engine.AnalyzeSnapshot(newTestSnapshot()); len(alerts) != 0 {
+		t.Errorf("Expected no alerts for healthy snapshot, got %+v", alerts)
+	}
+}
+
+func TestEngineUsesCurrentThresholds(t *testing.T) {
+	tm := config.NewThresholdManager(config.DefaultThresholds())
+	engine := NewEngine(tm)
+
+	s := newTestSnapshot()
+	s.CPUCurrent = 87
+
+	alert := findAlert(engine.AnalyzeSnapshot(s), models.AnomalyCPUSpike)
+	if alert == nil || alert.Severity != models.SeverityWarning {
+		t.Fatalf("Expected CPUSpike warning at 87%%, got %+v", alert)
+	}
+
+	// Threshold alterado em runtime vale para a próxima análise
+	if err := tm.UpdateCPUWarning(88); err != nil {
+		t.Fatalf("UpdateCPUWarning() error = %v", err)
+	}
+
+	if alert := findAlert(engine.AnalyzeSnapshot(s), models.AnomalyCPUSpike); alert != nil {
+		t.Errorf("Expected no CPUSpike after raising warning threshold, got %+v", alert)
+	}
+}
+
+func TestEngineUsesThresholdOverrides(t *testing.T) {
+	tm := config.NewThresholdManager(config.DefaultThresholds())
+	err := tm.SetOverrides([]models.ThresholdOverride{
+		{Namespace: "batch-*", Thresholds: map[string]string{"cpu_warning_percent": "95", "cpu_critical_percent": "98"}},
+		{Namespace: "api-*", Thresholds: map[string]string{"cpu_warning_percent": "75", "cpu_critical_percent": "85"}},
+	})
+	if err != nil {
+		t.Fatalf("SetOverrides() error = %v", err)
+	}
+	engine := NewEngine(tm)
+
+	// Mesmo uso de CPU: normal em batch, warning no global, crítico em api
+	tests := []struct {
+		namespace string
+		want      *models.AlertSeverity
+	}{
+		{"batch-reports", nil},
+		{"production", severityPtr(models.SeverityWarning)},
+		{"api-public", severityPtr(models.SeverityCritical)},
+	}
+
+	for _, tt := range tests {
+		s := newTestSnapshot()
+		s.Namespace = tt.namespace
+		s.CPUCurrent = 88
+
+		alert := findAlert(engine.AnalyzeSnapshot(s), models.AnomalyCPUSpike)
+		switch {
+		case tt.want == nil && alert != nil:
+			t.Errorf("%s: expected no CPUSpike, got %s", tt.namespace, alert.Severity)
+		case tt.want != nil && (alert == nil || alert.Severity != *tt.want):
+			t.Errorf("%s: expected CPUSpike %s, got %+v", tt.namespace, *tt.want, alert)
+		}
+	}
+
+	s := newTestSnapshot()
+	s.Namespace = "batch-reports"
+	s.CPUCurrent = 99
+	if alert := findAlert(engine.AnalyzeSnapshot(s), models.AnomalyCPUSpike); alert == nil || alert.Severity != models.SeverityCritical {
+		t.Errorf("Expected CPUSpike critical above batch override, got %+v", alert)
+	}
+}
+
+func severityPtr(s models.AlertSeverity) *models.AlertSeverity {
+	return &s
+}
+
+func TestEngineAlertFields(t *testing.T) {
+	engine := NewEngine(config.NewThresholdManager(config.DefaultThresholds()))
+
+	s := newTestSnapshot()
+	s.CPUCurrent = 95
+	s.ErrorRate = 7.5
+
+	alerts := engine.AnalyzeSnapshot(s)
+	if len(alerts) == 0 {
+		t.Fatal("Expected alerts")
+	}
+
+	// Ordenados por severidade
+	for i := 1; i < len(alerts); i++ {
+		if alerts[i].Severity > alerts[i-1].Severity {
+			t.Errorf("Alerts not sorted by severity: %s before %s", alerts[i-1].Severity, alerts[i].Severity)
+		}
+	}
+
+	alert := findAlert(alerts, models.AnomalyHighErrorRate)
+	if alert == nil {
+		t.Fatal("Expected HighErrorRate alert")
+	}
+
+	if alert.ID != "test-cluster/production/api/HighErrorRate" {
+		t.Errorf("Unexpected alert ID %q", alert.ID)
+	}
+	if alert.Source != models.AlertSourceWatchdog || alert.Severity != models.SeverityCritical {
+		t.Errorf("Unexpected source/severity: %s/%s", alert.Source, alert.Severity)
+	}
+	if alert.Snapshot == nil || alert.Snapshot == s {
+		t.Error("Expected alert to carry a copy of the snapshot")
+	}
+	if alert.Context == nil || alert.Context.ErrorRate != 7.5 {
+		t.Errorf("Expected context with error rate, got %+v", alert.Context)
+	}
+}
+
+func TestDetectors(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+
+	tests := []struct {
+		name     string
+		mutate   func(s *models.HPASnapshot, prev *models.HPASnapshot)
+		anomaly  models.AnomalyType
+		severity models.AlertSeverity
+	}{
+		{
+			name:     "cpu critical",
+			mutate:   func(s, _ *models.HPASnapshot) { s.CPUCurrent = 92 },
+			anomaly:  models.AnomalyCPUSpike,
+			severity: models.SeverityCritical,
+		},
+		{
+			name:     "memory warning",
+			mutate:   func(s, _ *models.HPASnapshot) { s.MemoryCurrent = 86 },
+			anomaly:  models.AnomalyMemorySpike,
+			severity: models.SeverityWarning,
+		},
+		{
+			name: "maxed out",
+			mutate: func(s, _ *models.HPASnapshot) {
+				s.CurrentReplicas, s.DesiredReplicas = 10, 10
+				s.CPUCurrent = 80
+			},
+			anomaly:  models.AnomalyMaxedOut,
+			severity: models.SeverityWarning,
+		},
+		{
+			name: "target miss above",
+			mutate: func(s, _ *models.HPASnapshot) {
+				s.CPUCurrent = 98 // +40% do target 70, sustentado por 3 min
+				s.CPUHistory = []float64{98, 98, 98, 98, 98, 98, 98}
+			},
+			anomaly:  models.AnomalyTargetMiss,
+			severity: models.SeverityWarning,
+		},
+		{
+			name: "target miss below (underutilized)",
+			mutate: func(s, _ *models.HPASnapshot) {
+				s.CPUCurrent = 20
+				s.CPUHistory = []float64{20, 20, 20, 20, 20, 20, 20}
+			},
+			anomaly:  models.AnomalyTargetMiss,
+			severity: models.SeverityInfo,
+		},
+		{
+			name:     "replica spike",
+			mutate:   func(s, _ *models.HPASnapshot) { s.ReplicaHistory = []int32{4, 5, 9} },
+			anomaly:  models.AnomalyReplicaSpike,
+			severity: models.SeverityWarning,
+		},
+		{
+			name:     "replica drop",
+			mutate:   func(s, _ *models.HPASnapshot) { s.ReplicaHistory = []int32{8, 6, 4} },
+			anomaly:  models.AnomalyReplicaDrop,
+			severity: models.SeverityWarning,
+		},
+		{
+			name:     "oscillation",
+			mutate:   func(s, _ *models.HPASnapshot) { s.ReplicaHistory = []int32{4, 5, 4, 5, 4} },
+			anomaly:  models.AnomalyReplicaOscillation,
+			severity: models.SeverityWarning,
+		},
+		{
+			name:     "max replicas lowered",
+			mutate:   func(s, prev *models.HPASnapshot) { prev.MaxReplicas = 20 },
+			anomaly:  models.AnomalyHPAConfigChange,
+			severity: models.SeverityWarning,
+		},
+		{
+			name:     "cpu request changed",
+			mutate:   func(s, prev *models.HPASnapshot) { prev.CPURequest = "1" },
+			anomaly:  models.AnomalyResourceChange,
+			severity: models.SeverityWarning,
+		},
+		{
+			name: "scaling stuck",
+			mutate: func(s, _ *models.HPASnapshot) {
+				s.CurrentReplicas, s.DesiredReplicas = 10, 10
+				s.Conditions = []models.HPACondition{{
+					Type:               models.ConditionScalingLimited,
+					Status:             "True",
+					Reason:             "TooManyReplicas",
+					LastTransitionTime: s.Timestamp.Add(-15 * time.Minute),
+				}}
+			},
+			anomaly:  models.AnomalyScalingStuck,
+			severity: models.SeverityWarning,
+		},
+		{
+			name:     "high latency",
+			mutate:   func(s, _ *models.HPASnapshot) { s.P95Latency = 1500 },
+			anomaly:  models.AnomalyHighLatency,
+			severity: models.SeverityCritical,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSnapshot()
+			prev := newTestSnapshot()
+			prev.Timestamp = s.Timestamp.Add(-30 * time.Second)
+			tt.mutate(s, prev)
+
+			in := Input{Current: s, History: []models.HPASnapshot{*prev, *s}}
+
+			var alert *models.UnifiedAlert
+			for _, d := range DefaultDetectors() {
+				if d.Type() == tt.anomaly {
+					alert = d.Detect(in, thresholds)
+				}
+			}
+
+			if alert == nil {
+				t.Fatalf("Expected %s alert", tt.anomaly)
+			}
+			if alert.Severity != tt.severity {
+				t.Errorf("Expected severity %s, got %s (%s)", tt.severity, alert.Severity, alert.Summary)
+			}
+		})
+	}
+}
+
+func TestChangeDetectorsRespectToggles(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+	thresholds.AlertOnConfigChange = false
+	thresholds.AlertOnResourceChange = false
+
+	s := newTestSnapshot()
+	prev := newTestSnapshot()
+	prev.Timestamp = s.Timestamp.Add(-30 * time.Second)
+	prev.MaxReplicas = 20
+	prev.CPURequest = "1"
+
+	in := Input{Current: s, History: []models.HPASnapshot{*prev}}
+
+	if alert := (&configChangeDetector{}).Detect(in, thresholds); alert != nil {
+		t.Errorf("Expected no config change alert when disabled, got %+v", alert)
+	}
+	if alert := (&resourceChangeDetector{}).Detect(in, thresholds); alert != nil {
+		t.Errorf("Expected no resource change alert when disabled, got %+v", alert)
+	}
+}
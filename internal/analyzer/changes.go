@@ -0,0 +1,312 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// snapshotSequence retorna os snapshots do histórico anteriores ao atual, seguidos do atual
+func snapshotSequence(in Input) []*models.HPASnapshot {
+	sequence := make([]*models.HPASnapshot, 0, len(in.History)+1)
+	for i := range in.History {
+		if in.History[i].Timestamp.Before(in.Current.Timestamp) {
+			sequence = append(sequence, &in.History[i])
+		}
+	}
+	return append(sequence, in.Current)
+}
+
+// formatChanges formata mudanças como "campo antes → depois"
+func formatChanges(changes []models.FieldChange) string {
+	parts := make([]string, len(changes))
+	for i, c := range changes {
+		parts[i] = fmt.Sprintf("%s %s → %s", c.Field, c.Before, c.After)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// configChangeDetector mudanças de min/max replicas, targets e spec.metrics entre snapshots
+// consecutivos (alert_on_config_change). Fica ativo enquanto a mudança estiver no histórico.
+type configChangeDetector struct{}
+
+func (d *configChangeDetector) Type() models.AnomalyType { return models.AnomalyHPAConfigChange }
+
+func (d *configChangeDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	if !t.AlertOnConfigChange {
+		return nil
+	}
+
+	sequence := snapshotSequence(in)
+
+	var changes []models.FieldChange
+	var managers []string
+	severity := models.SeverityInfo
+
+	for i := 1; i < len(sequence); i++ {
+		prev, cur := sequence[i-1], sequence[i]
+
+		pairChanges := configDiff(prev, cur)
+		if len(pairChanges) == 0 {
+			continue
+		}
+		changes = append(changes, pairChanges...)
+
+		// Reduzir maxReplicas limita a capacidade de escalar
+		if cur.MaxReplicas < prev.MaxReplicas {
+			severity = models.SeverityWarning
+		}
+
+		if manager := changeManager(prev, cur); manager != "" && !contains(managers, manager) {
+			managers = append(managers, manager)
+		}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	s := in.Current
+	summary := fmt.Sprintf("Config do HPA alterada: %s", formatChanges(changes))
+	if len(managers) > 0 {
+		summary = fmt.Sprintf("Config do HPA alterada por %s: %s", strings.Join(managers, ", "), formatChanges(changes))
+	}
+
+	alert := newAlert(s, d.Type(), severity, summary,
+		fmt.Sprintf("Configuração do HPA mudou às %s: %s.",
+			changes[len(changes)-1].At.Format("15:04:05"), formatChanges(changes)))
+	alert.Context.Changes = changes
+	alert.Context.ChangedBy = strings.Join(managers, ", ")
+
+	return alert
+}
+
+// configDiff compara a configuração do HPA entre dois snapshots
+func configDiff(prev, cur *models.HPASnapshot) []models.FieldChange {
+	var changes []models.FieldChange
+
+	add := func(field, before, after string) {
+		if before != after {
+			changes = append(changes, models.FieldChange{Field: field, Before: before, After: after, At: cur.Timestamp})
+		}
+	}
+
+	add("minReplicas", fmt.Sprint(prev.MinReplicas), fmt.Sprint(cur.MinReplicas))
+	add("maxReplicas", fmt.Sprint(prev.MaxReplicas), fmt.Sprint(cur.MaxReplicas))
+	add("cpuTarget", percentOrNone(prev.CPUTarget), percentOrNone(cur.CPUTarget))
+	add("memoryTarget", percentOrNone(prev.MemoryTarget), percentOrNone(cur.MemoryTarget))
+
+	// Demais mudanças em spec.metrics (targets de CPU/memory já reportados acima)
+	if before, after := otherMetricSpecs(prev), otherMetricSpecs(cur); !equalStrings(before, after) {
+		add("metrics", listOrNone(before), listOrNone(after))
+	}
+
+	return changes
+}
+
+// otherMetricSpecs retorna spec.metrics sem os targets de utilização de CPU/memory
+func otherMetricSpecs(s *models.HPASnapshot) []string {
+	var specs []string
+	for _, spec := range s.MetricSpecs {
+		if strings.HasPrefix(spec, "resource:cpu/Utilization=") || strings.HasPrefix(spec, "resource:memory/Utilization=") {
+			continue
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// changeManager retorna o manager que alterou o spec entre os dois snapshots (vazio se desconhecido)
+func changeManager(prev, cur *models.HPASnapshot) string {
+	if cur.SpecManager == "" || cur.SpecManagedAt == nil {
+		return ""
+	}
+
+	// managedFields não mudou: a alteração não foi registrada por um manager
+	if prev.SpecManagedAt != nil && prev.SpecManager == cur.SpecManager && !cur.SpecManagedAt.After(*prev.SpecManagedAt) {
+		return ""
+	}
+
+	return cur.SpecManager
+}
+
+// percentOrNone formata um target percentual (0 = não configurado)
+func percentOrNone(value int32) string {
+	if value == 0 {
+		return "<none>"
+	}
+	return fmt.Sprintf("%d%%", value)
+}
+
+// listOrNone formata uma lista de specs
+func listOrNone(values []string) string {
+	if len(values) == 0 {
+		return "<none>"
+	}
+	return "[" + strings.Join(values, " ") + "]"
+}
+
+// equalStrings compara dois slices de strings
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// contains verifica se o slice contém o item
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceChangeDetector mudanças de requests/limits do workload alvo entre snapshots consecutivos
+// (alert_on_resource_change), com o efeito implícito na utilização vista pelo HPA
+type resourceChangeDetector struct{}
+
+func (d *resourceChangeDetector) Type() models.AnomalyType { return models.AnomalyResourceChange }
+
+func (d *resourceChangeDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	if !t.AlertOnResourceChange {
+		return nil
+	}
+
+	var changes []models.FieldChange
+	var effects []string
+
+	// Snapshots sem resources (falha ao obter o workload alvo) são ignorados: compara com o
+	// último snapshot conhecido, senão "<none> → 500m" viraria uma mudança falsa
+	var prev *models.HPASnapshot
+	for _, cur := range snapshotSequence(in) {
+		if cur.ResourcesUnknown {
+			continue
+		}
+		if prev == nil {
+			prev = cur
+			continue
+		}
+
+		for _, change := range resourceDiff(prev, cur) {
+			changes = append(changes, change)
+
+			switch {
+			case change.UtilizationFactor == 0:
+			case change.Field == "cpu request":
+				effects = append(effects, utilizationEffect("CPU", change.UtilizationFactor, prev.CPUCurrent, cur.CPUTarget, cur))
+			case change.Field == "memory request":
+				effects = append(effects, utilizationEffect("memory", change.UtilizationFactor, prev.MemoryCurrent, cur.MemoryTarget, cur))
+			}
+		}
+		prev = cur
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// Só limits mudaram: não afeta a utilização vista pelo HPA
+	severity := models.SeverityInfo
+	if len(effects) > 0 {
+		severity = models.SeverityWarning
+	}
+
+	description := fmt.Sprintf("Requests/limits do workload alvo mudaram às %s: %s.",
+		changes[len(changes)-1].At.Format("15:04:05"), formatChanges(changes))
+	if len(effects) > 0 {
+		description += " " + strings.Join(effects, " ")
+	}
+
+	alert := newAlert(in.Current, d.Type(), severity,
+		fmt.Sprintf("Resources alterados: %s", formatChanges(changes)),
+		description)
+	alert.Context.Changes = changes
+
+	return alert
+}
+
+// resourceDiff compara requests/limits entre dois snapshots
+func resourceDiff(prev, cur *models.HPASnapshot) []models.FieldChange {
+	var changes []models.FieldChange
+
+	add := func(field, before, after string, factor float64) {
+		if before != after {
+			changes = append(changes, models.FieldChange{
+				Field:             field,
+				Before:            valueOrNone(before),
+				After:             valueOrNone(after),
+				At:                cur.Timestamp,
+				UtilizationFactor: factor,
+			})
+		}
+	}
+
+	// Utilização do HPA é relativa ao request: só requests alteram o que o HPA vê
+	add("cpu request", prev.CPURequest, cur.CPURequest, requestFactor(prev.CPURequest, cur.CPURequest))
+	add("cpu limit", prev.CPULimit, cur.CPULimit, 0)
+	add("memory request", prev.MemoryRequest, cur.MemoryRequest, requestFactor(prev.MemoryRequest, cur.MemoryRequest))
+	add("memory limit", prev.MemoryLimit, cur.MemoryLimit, 0)
+
+	return changes
+}
+
+// requestFactor fator aplicado à utilização quando o request muda (antes/depois); 0 se não calculável
+func requestFactor(before, after string) float64 {
+	b, err := resource.ParseQuantity(before)
+	if err != nil || b.IsZero() {
+		return 0
+	}
+
+	a, err := resource.ParseQuantity(after)
+	if err != nil || a.IsZero() {
+		return 0
+	}
+
+	return b.AsApproximateFloat64() / a.AsApproximateFloat64()
+}
+
+// utilizationEffect descreve o efeito de uma mudança de request na utilização e nas réplicas
+func utilizationEffect(resourceName string, factor, before float64, target int32, s *models.HPASnapshot) string {
+	effect := fmt.Sprintf("Utilização de %s vista pelo HPA ×%.2f para o mesmo consumo", resourceName, factor)
+
+	if before <= 0 {
+		return effect + "."
+	}
+
+	projected := before * factor
+	effect += fmt.Sprintf(": %.0f%% → %.0f%%", before, projected)
+
+	if target <= 0 {
+		return effect + "."
+	}
+
+	// Mesma fórmula do HPA: desired = ceil(current * utilização / target), limitado a min/max
+	desired := int32(math.Ceil(float64(s.CurrentReplicas) * projected / float64(target)))
+	if desired > s.MaxReplicas {
+		desired = s.MaxReplicas
+	}
+	if desired < s.MinReplicas {
+		desired = s.MinReplicas
+	}
+
+	return effect + fmt.Sprintf(" (target %d%%), HPA tende a ~%d réplicas (hoje %d).", target, desired, s.CurrentReplicas)
+}
+
+// valueOrNone formata valores vazios
+func valueOrNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
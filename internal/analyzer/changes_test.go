@@ -0,0 +1,207 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// newTestHistory cria n snapshots saudáveis com 30s de intervalo (o último é o atual)
+func newTestHistory(n int) []models.HPASnapshot {
+	start := time.Now().Add(-time.Duration(n) * 30 * time.Second)
+
+	history := make([]models.HPASnapshot, n)
+	for i := range history {
+		s := newTestSnapshot()
+		s.Timestamp = start.Add(time.Duration(i) * 30 * time.Second)
+		s.MetricSpecs = []string{"resource:cpu/Utilization=70", "resource:memory/Utilization=80"}
+		history[i] = *s
+	}
+	return history
+}
+
+func TestConfigChangeBeforeAfter(t *testing.T) {
+	history := newTestHistory(4)
+
+	// maxReplicas reduzido por kubectl-edit no terceiro snapshot
+	editedAt := history[2].Timestamp.Add(-10 * time.Second)
+	for i := 2; i < len(history); i++ {
+		history[i].MaxReplicas = 5
+		history[i].SpecManager = "kubectl-edit"
+		history[i].SpecManagedAt = &editedAt
+	}
+	argoAt := history[0].Timestamp.Add(-time.Hour)
+	history[0].SpecManager, history[0].SpecManagedAt = "argocd-controller", &argoAt
+	history[1].SpecManager, history[1].SpecManagedAt = "argocd-controller", &argoAt
+
+	current := &history[len(history)-1]
+	alert := (&configChangeDetector{}).Detect(Input{Current: current, History: history}, config.DefaultThresholds())
+	if alert == nil {
+		t.Fatal("Expected HPAConfigChange alert")
+	}
+
+	if alert.Severity != models.SeverityWarning {
+		t.Errorf("Expected Warning when maxReplicas is lowered, got %s", alert.Severity)
+	}
+
+	changes := alert.Context.Changes
+	if len(changes) != 1 {
+		t.Fatalf("Expected 1 change, got %+v", changes)
+	}
+	if changes[0].Field != "maxReplicas" || changes[0].Before != "10" || changes[0].After != "5" {
+		t.Errorf("Unexpected change %+v", changes[0])
+	}
+	if !changes[0].At.Equal(history[2].Timestamp) {
+		t.Errorf("Expected change at %s, got %s", history[2].Timestamp, changes[0].At)
+	}
+
+	if alert.Context.ChangedBy != "kubectl-edit" {
+		t.Errorf("Expected ChangedBy kubectl-edit, got %q", alert.Context.ChangedBy)
+	}
+	if !strings.Contains(alert.Summary, "kubectl-edit") || !strings.Contains(alert.Summary, "maxReplicas 10 → 5") {
+		t.Errorf("Unexpected summary %q", alert.Summary)
+	}
+
+	// Mudança saiu do histórico: alerta resolve
+	if alert := (&configChangeDetector{}).Detect(Input{Current: current, History: history[2:]}, config.DefaultThresholds()); alert != nil {
+		t.Errorf("Expected no alert once the change left the history, got %+v", alert)
+	}
+}
+
+func TestConfigChangeMetricSpec(t *testing.T) {
+	history := newTestHistory(2)
+
+	current := &history[1]
+	current.CPUTarget = 60
+	current.MetricSpecs = []string{
+		"resource:cpu/Utilization=60",
+		"resource:memory/Utilization=80",
+		"pods:http_requests_per_second/AverageValue=100",
+	}
+
+	alert := (&configChangeDetector{}).Detect(Input{Current: current, History: history}, config.DefaultThresholds())
+	if alert == nil {
+		t.Fatal("Expected HPAConfigChange alert")
+	}
+
+	if alert.Severity != models.SeverityInfo {
+		t.Errorf("Expected Info, got %s", alert.Severity)
+	}
+
+	fields := make(map[string]models.FieldChange)
+	for _, c := range alert.Context.Changes {
+		fields[c.Field] = c
+	}
+
+	if c, ok := fields["cpuTarget"]; !ok || c.Before != "70%" || c.After != "60%" {
+		t.Errorf("Expected cpuTarget 70%% → 60%%, got %+v", c)
+	}
+	if c, ok := fields["metrics"]; !ok || c.Before != "<none>" || !strings.Contains(c.After, "pods:http_requests_per_second") {
+		t.Errorf("Expected new pods metric in metrics change, got %+v", c)
+	}
+	if len(fields) != 2 {
+		t.Errorf("Expected CPU target change not to be repeated under metrics, got %+v", alert.Context.Changes)
+	}
+	if alert.Context.ChangedBy != "" {
+		t.Errorf("Expected unknown manager, got %q", alert.Context.ChangedBy)
+	}
+}
+
+func TestResourceChangeUtilizationEffect(t *testing.T) {
+	history := newTestHistory(3)
+
+	// CPU request cortado pela metade no último snapshot
+	current := &history[2]
+	current.CPURequest = "250m"
+
+	alert := (&resourceChangeDetector{}).Detect(Input{Current: current, History: history}, config.DefaultThresholds())
+	if alert == nil {
+		t.Fatal("Expected ResourceChange alert")
+	}
+
+	if alert.Severity != models.SeverityWarning {
+		t.Errorf("Expected Warning for request change, got %s", alert.Severity)
+	}
+
+	changes := alert.Context.Changes
+	if len(changes) != 1 || changes[0].Before != "500m" || changes[0].After != "250m" {
+		t.Fatalf("Expected cpu request 500m → 250m, got %+v", changes)
+	}
+	if changes[0].UtilizationFactor != 2 {
+		t.Errorf("Expected utilization factor 2, got %.2f", changes[0].UtilizationFactor)
+	}
+
+	// 65% com 500m → 130% com 250m; ceil(4 * 130 / 70) = 8 réplicas
+	for _, expected := range []string{"×2.00", "65% → 130%", "~8 réplicas"} {
+		if !strings.Contains(alert.Description, expected) {
+			t.Errorf("Expected %q in description, got %q", expected, alert.Description)
+		}
+	}
+}
+
+func TestResourceChangeLimitsOnly(t *testing.T) {
+	history := newTestHistory(2)
+	history[0].MemoryLimit = "1Gi"
+	history[1].MemoryLimit = "2Gi"
+
+	alert := (&resourceChangeDetector{}).Detect(Input{Current: &history[1], History: history}, config.DefaultThresholds())
+	if alert == nil {
+		t.Fatal("Expected ResourceChange alert")
+	}
+
+	if alert.Severity != models.SeverityInfo {
+		t.Errorf("Expected Info when only limits change, got %s", alert.Severity)
+	}
+	if alert.Context.Changes[0].UtilizationFactor != 0 {
+		t.Errorf("Expected no utilization factor for limits, got %.2f", alert.Context.Changes[0].UtilizationFactor)
+	}
+}
+
+func TestResourceChangeScaleTargetFetchFailed(t *testing.T) {
+	history := newTestHistory(4)
+
+	// Falha ao obter o deployment: snapshot sem requests/limits
+	history[1].CPURequest, history[1].MemoryRequest = "", ""
+	history[1].ResourcesUnknown = true
+
+	alert := (&resourceChangeDetector{}).Detect(Input{Current: &history[3], History: history}, config.DefaultThresholds())
+	if alert != nil {
+		t.Fatalf("Expected no ResourceChange after scale target fetch failure, got %+v", alert.Context.Changes)
+	}
+
+	// Mudança real durante a falha: compara com o último snapshot conhecido
+	history[2].ResourcesUnknown = true
+	history[2].CPURequest = ""
+	history[3].CPURequest = "250m"
+
+	alert = (&resourceChangeDetector{}).Detect(Input{Current: &history[3], History: history}, config.DefaultThresholds())
+	if alert == nil {
+		t.Fatal("Expected ResourceChange alert")
+	}
+	changes := alert.Context.Changes
+	if len(changes) != 1 || changes[0].Before != "500m" || changes[0].After != "250m" || changes[0].UtilizationFactor != 2 {
+		t.Errorf("Expected cpu request 500m → 250m against last known snapshot, got %+v", changes)
+	}
+}
+
+func TestRequestFactor(t *testing.T) {
+	tests := []struct {
+		before, after string
+		expected      float64
+	}{
+		{"500m", "250m", 2},
+		{"1", "2", 0.5},
+		{"512Mi", "1Gi", 0.5},
+		{"", "500m", 0},
+		{"500m", "invalid", 0},
+	}
+
+	for _, tt := range tests {
+		if got := requestFactor(tt.before, tt.after); got != tt.expected {
+			t.Errorf("requestFactor(%q, %q) = %.2f, want %.2f", tt.before, tt.after, got, tt.expected)
+		}
+	}
+}
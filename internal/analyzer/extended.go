@@ -0,0 +1,43 @@
+package analyzer
+
+import (
+	"fmt"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// errorRateDetector taxa de erros 5xx acima de error_rate_critical_percent
+type errorRateDetector struct{}
+
+func (d *errorRateDetector) Type() models.AnomalyType { return models.AnomalyHighErrorRate }
+
+func (d *errorRateDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	s := in.Current
+
+	if t.ErrorRateCriticalPercent <= 0 || s.ErrorRate <= t.ErrorRateCriticalPercent {
+		return nil
+	}
+
+	return newAlert(s, d.Type(), models.SeverityCritical,
+		fmt.Sprintf("Error rate %.2f%% (crítico >%.1f%%)", s.ErrorRate, t.ErrorRateCriticalPercent),
+		fmt.Sprintf("Taxa de erros 5xx em %.2f%% com %.2f req/s e %d réplicas.",
+			s.ErrorRate, s.RequestRate, s.CurrentReplicas))
+}
+
+// latencyDetector latência P95 acima de p95_latency_critical_ms
+type latencyDetector struct{}
+
+func (d *latencyDetector) Type() models.AnomalyType { return models.AnomalyHighLatency }
+
+func (d *latencyDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	s := in.Current
+
+	if t.P95LatencyCriticalMs <= 0 || s.P95Latency <= t.P95LatencyCriticalMs {
+		return nil
+	}
+
+	return newAlert(s, d.Type(), models.SeverityCritical,
+		fmt.Sprintf("P95 %.0fms (crítico >%.0fms)", s.P95Latency, t.P95LatencyCriticalMs),
+		fmt.Sprintf("Latência P95 em %.2fms com %.2f req/s e %d réplicas.",
+			s.P95Latency, s.RequestRate, s.CurrentReplicas))
+}
@@ -0,0 +1,222 @@
+package analyzer
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// defaultOscillationMaxChanges usado quando oscillation_max_changes não está configurado
+const defaultOscillationMaxChanges = 3
+
+// replicaSeries retorna a série de réplicas do HPA, mais antiga primeiro.
+// Prefere ReplicaHistory (Prometheus); senão usa o histórico do watcher.
+func replicaSeries(in Input) []int32 {
+	if len(in.Current.ReplicaHistory) >= 2 {
+		return in.Current.ReplicaHistory
+	}
+
+	series := make([]int32, 0, len(in.History)+1)
+	for _, snapshot := range in.History {
+		if snapshot.Timestamp.Before(in.Current.Timestamp) {
+			series = append(series, snapshot.CurrentReplicas)
+		}
+	}
+	return append(series, in.Current.CurrentReplicas)
+}
+
+// defaultReplicaDeltaWindow usado quando replica_delta_window_minutes não está configurado
+const defaultReplicaDeltaWindow = 5 * time.Minute
+
+// replicaPoint réplicas observadas em um instante
+type replicaPoint struct {
+	at       time.Time
+	replicas int32
+}
+
+// timedReplicaSeries retorna a série de réplicas com timestamps, mais antiga primeiro.
+// Pontos de ReplicaHistory (Prometheus) recebem timestamps a partir do snapshot atual (1 ponto a
+// cada 30s) e substituem só o trecho do histórico do watcher que cobrem: pontos do watcher mais
+// antigos continuam na série, para janelas maiores que a ReplicaHistory (5 min).
+func timedReplicaSeries(in Input) []replicaPoint {
+	s := in.Current
+
+	points := make([]replicaPoint, 0, len(in.History)+len(s.ReplicaHistory)+1)
+	for _, snapshot := range in.History {
+		if snapshot.Timestamp.Before(s.Timestamp) {
+			points = append(points, replicaPoint{at: snapshot.Timestamp, replicas: snapshot.CurrentReplicas})
+		}
+	}
+
+	if len(s.ReplicaHistory) < 2 {
+		return append(points, replicaPoint{at: s.Timestamp, replicas: s.CurrentReplicas})
+	}
+
+	first := s.Timestamp.Add(-time.Duration(len(s.ReplicaHistory)-1) * historyStep)
+	older := points[:0]
+	for _, p := range points {
+		if p.at.Before(first) {
+			older = append(older, p)
+		}
+	}
+
+	for i, replicas := range s.ReplicaHistory {
+		age := time.Duration(len(s.ReplicaHistory)-1-i) * historyStep
+		older = append(older, replicaPoint{at: s.Timestamp.Add(-age), replicas: replicas})
+	}
+	return older
+}
+
+// replicaDeltaWindow janela de detecção de spike/drop
+func replicaDeltaWindow(t models.Thresholds) time.Duration {
+	if t.ReplicaDeltaWindowMinutes < 1 {
+		return defaultReplicaDeltaWindow
+	}
+	return time.Duration(t.ReplicaDeltaWindowMinutes) * time.Minute
+}
+
+// replicaWindowChange compara as réplicas atuais com o extremo da janela (mínimo para spike,
+// máximo para drop). Retorna nil se não houve variação na direção pedida.
+func replicaWindowChange(in Input, t models.Thresholds, up bool) *models.ReplicaChange {
+	window := replicaDeltaWindow(t)
+	points := timedReplicaSeries(in)
+	end := points[len(points)-1]
+	cutoff := end.at.Add(-window)
+
+	// Extremo mais recente da janela: a variação começa logo depois dele
+	var start *replicaPoint
+	for i := range points[:len(points)-1] {
+		p := &points[i]
+		if p.at.Before(cutoff) {
+			continue
+		}
+		if start == nil || (up && p.replicas <= start.replicas) || (!up && p.replicas >= start.replicas) {
+			start = p
+		}
+	}
+
+	if start == nil || (up && end.replicas <= start.replicas) || (!up && end.replicas >= start.replicas) {
+		return nil
+	}
+
+	change := &models.ReplicaChange{
+		Window: window,
+		From:   start.replicas,
+		To:     end.replicas,
+		FromAt: start.at,
+	}
+
+	// Primeiro ponto da série após o extremo onde as réplicas já tinham mudado
+	for _, p := range points {
+		if p.at.After(start.at) && p.replicas != start.replicas {
+			change.ScaledAt = p.at
+			break
+		}
+	}
+
+	// LastScaleTime do HPA é mais preciso que a resolução da série
+	if last := in.Current.LastScaleTime; last != nil && last.After(start.at) && !last.After(end.at) {
+		change.ScaledAt = *last
+	}
+
+	return change
+}
+
+// exceedsReplicaDelta verifica se a mudança ultrapassa replica_delta_percent ou replica_delta_absolute
+func exceedsReplicaDelta(from, delta int32, t models.Thresholds) bool {
+	if t.ReplicaDeltaAbsolute > 0 && delta >= t.ReplicaDeltaAbsolute {
+		return true
+	}
+
+	if t.ReplicaDeltaPercent > 0 && from > 0 && float64(delta)/float64(from)*100 >= t.ReplicaDeltaPercent {
+		return true
+	}
+
+	return false
+}
+
+// replicaChangeDescription descreve a variação de réplicas dentro da janela
+func replicaChangeDescription(verb string, c *models.ReplicaChange, t models.Thresholds) string {
+	delta := c.To - c.From
+	if delta < 0 {
+		delta = -delta
+	}
+
+	description := fmt.Sprintf("%s de %d réplicas (%d → %d) em %.0f min ultrapassou os thresholds (%.0f%% / ±%d).",
+		verb, delta, c.From, c.To, c.Window.Minutes(), t.ReplicaDeltaPercent, t.ReplicaDeltaAbsolute)
+	if !c.ScaledAt.IsZero() {
+		description += fmt.Sprintf(" Scale às %s.", c.ScaledAt.Format("15:04:05"))
+	}
+	return description
+}
+
+// replicaSpikeDetector aumento de réplicas acima dos thresholds de delta dentro de replica_delta_window_minutes
+type replicaSpikeDetector struct{}
+
+func (d *replicaSpikeDetector) Type() models.AnomalyType { return models.AnomalyReplicaSpike }
+
+func (d *replicaSpikeDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	change := replicaWindowChange(in, t, true)
+	if change == nil || !exceedsReplicaDelta(change.From, change.To-change.From, t) {
+		return nil
+	}
+
+	s := in.Current
+	alert := newAlert(s, d.Type(), models.SeverityWarning,
+		fmt.Sprintf("Réplicas subiram de %d para %d em %.0f min", change.From, change.To, change.Window.Minutes()),
+		replicaChangeDescription("Aumento", change, t)+fmt.Sprintf(" Max: %d.", s.MaxReplicas))
+	alert.Context.ReplicaChange = change
+
+	return alert
+}
+
+// replicaDropDetector queda de réplicas acima dos thresholds de delta dentro de replica_delta_window_minutes
+type replicaDropDetector struct{}
+
+func (d *replicaDropDetector) Type() models.AnomalyType { return models.AnomalyReplicaDrop }
+
+func (d *replicaDropDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	change := replicaWindowChange(in, t, false)
+	if change == nil || !exceedsReplicaDelta(change.From, change.From-change.To, t) {
+		return nil
+	}
+
+	s := in.Current
+	alert := newAlert(s, d.Type(), models.SeverityWarning,
+		fmt.Sprintf("Réplicas caíram de %d para %d em %.0f min", change.From, change.To, change.Window.Minutes()),
+		replicaChangeDescription("Queda", change, t)+fmt.Sprintf(" Min: %d.", s.MinReplicas))
+	alert.Context.ReplicaChange = change
+
+	return alert
+}
+
+// oscillationDetector réplicas mudando mais que oscillation_max_changes vezes no histórico
+type oscillationDetector struct{}
+
+func (d *oscillationDetector) Type() models.AnomalyType { return models.AnomalyReplicaOscillation }
+
+func (d *oscillationDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	maxChanges := t.OscillationMaxChanges
+	if maxChanges < 1 {
+		maxChanges = defaultOscillationMaxChanges
+	}
+
+	series := replicaSeries(in)
+
+	changes := 0
+	for i := 1; i < len(series); i++ {
+		if series[i] != series[i-1] {
+			changes++
+		}
+	}
+
+	if changes <= maxChanges {
+		return nil
+	}
+
+	return newAlert(in.Current, d.Type(), models.SeverityWarning,
+		fmt.Sprintf("Réplicas oscilando: %d mudanças", changes),
+		fmt.Sprintf("Réplicas mudaram %d vezes em %d pontos do histórico (máximo: %d): %v.",
+			changes, len(series), maxChanges, series))
+}
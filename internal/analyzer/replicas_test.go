@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// newReplicaHistory cria snapshots com as réplicas dadas, 1 minuto de intervalo (o último é o atual)
+func newReplicaHistory(replicas ...int32) []models.HPASnapshot {
+	start := time.Now().Add(-time.Duration(len(replicas)) * time.Minute)
+
+	history := make([]models.HPASnapshot, len(replicas))
+	for i, r := range replicas {
+		s := newTestSnapshot()
+		s.Timestamp = start.Add(time.Duration(i) * time.Minute)
+		s.CurrentReplicas, s.DesiredReplicas = r, r
+		history[i] = *s
+	}
+	return history
+}
+
+func TestReplicaSpikeWindow(t *testing.T) {
+	thresholds := config.DefaultThresholds() // 50% / ±5 em 5 min
+	history := newReplicaHistory(4, 4, 4, 5, 7)
+	current := &history[len(history)-1]
+
+	scaledAt := history[3].Timestamp.Add(-20 * time.Second)
+	current.LastScaleTime = &scaledAt
+
+	alert := (&replicaSpikeDetector{}).Detect(Input{Current: current, History: history}, thresholds)
+	if alert == nil {
+		t.Fatal("Expected ReplicaSpike alert")
+	}
+
+	change := alert.Context.ReplicaChange
+	if change == nil {
+		t.Fatal("Expected ReplicaChange in alert context")
+	}
+	if change.From != 4 || change.To != 7 || change.Window != 5*time.Minute {
+		t.Errorf("Expected 4 → 7 in 5m, got %+v", change)
+	}
+	if !change.FromAt.Equal(history[2].Timestamp) {
+		t.Errorf("Expected change to start at the last point with 4 replicas, got %s", change.FromAt)
+	}
+	if !change.ScaledAt.Equal(scaledAt) {
+		t.Errorf("Expected ScaledAt from LastScaleTime %s, got %s", scaledAt, change.ScaledAt)
+	}
+	if !strings.Contains(alert.Summary, "4 para 7 em 5 min") {
+		t.Errorf("Unexpected summary %q", alert.Summary)
+	}
+
+	// Janela de 1 min só enxerga 5 → 7 (+40%, +2): abaixo dos thresholds
+	thresholds.ReplicaDeltaWindowMinutes = 1
+	if alert := (&replicaSpikeDetector{}).Detect(Input{Current: current, History: history}, thresholds); alert != nil {
+		t.Errorf("Expected no alert with 1 minute window, got %+v", alert.Context.ReplicaChange)
+	}
+}
+
+func TestReplicaDropAbsoluteThreshold(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+	thresholds.ReplicaDeltaPercent = 0 // só o absoluto
+
+	history := newReplicaHistory(20, 20, 17, 14)
+	current := &history[len(history)-1]
+
+	alert := (&replicaDropDetector{}).Detect(Input{Current: current, History: history}, thresholds)
+	if alert == nil {
+		t.Fatal("Expected ReplicaDrop alert (-6 >= 5)")
+	}
+
+	change := alert.Context.ReplicaChange
+	if change.From != 20 || change.To != 14 {
+		t.Errorf("Expected 20 → 14, got %+v", change)
+	}
+	// Sem LastScaleTime: primeiro ponto após o máximo
+	if !change.ScaledAt.Equal(history[2].Timestamp) {
+		t.Errorf("Expected ScaledAt at first changed point %s, got %s", history[2].Timestamp, change.ScaledAt)
+	}
+
+	if alert := (&replicaSpikeDetector{}).Detect(Input{Current: current, History: history}, thresholds); alert != nil {
+		t.Errorf("Expected no ReplicaSpike on a drop, got %+v", alert)
+	}
+}
+
+func TestReplicaSpikeFromPrometheusHistory(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+	thresholds.ReplicaDeltaWindowMinutes = 2
+
+	s := newTestSnapshot()
+	// 1 ponto/30s: os 4 primeiros pontos (3 → 3) ficam fora da janela de 2 min
+	s.ReplicaHistory = []int32{3, 3, 3, 3, 5, 5, 5, 5, 6}
+
+	alert := (&replicaSpikeDetector{}).Detect(Input{Current: s}, thresholds)
+	if alert != nil {
+		t.Errorf("Expected 5 → 6 within the window to be below thresholds, got %+v", alert.Context.ReplicaChange)
+	}
+
+	thresholds.ReplicaDeltaWindowMinutes = 5
+	alert = (&replicaSpikeDetector{}).Detect(Input{Current: s}, thresholds)
+	if alert == nil {
+		t.Fatal("Expected ReplicaSpike with 5 minute window (3 → 6)")
+	}
+	if change := alert.Context.ReplicaChange; !change.ScaledAt.Equal(s.Timestamp.Add(-2 * time.Minute)) {
+		t.Errorf("Expected ScaledAt 2m before the snapshot, got %s", change.ScaledAt)
+	}
+}
+
+func TestReplicaSpikeLongWindowWithPrometheusHistory(t *testing.T) {
+	thresholds := config.DefaultThresholds() // 50% / ±5
+	thresholds.ReplicaDeltaWindowMinutes = 15
+
+	// Watcher: 3 réplicas até 10 min atrás, 8 desde então
+	history := newReplicaHistory(3, 3, 3, 3, 3, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8)
+	current := &history[len(history)-1]
+
+	// Prometheus cobre só os últimos 5 min, todos com 8 réplicas
+	current.ReplicaHistory = []int32{8, 8, 8, 8, 8, 8, 8, 8, 8, 8}
+
+	alert := (&replicaSpikeDetector{}).Detect(Input{Current: current, History: history}, thresholds)
+	if alert == nil {
+		t.Fatal("Expected ReplicaSpike over the 15 minute window (3 → 8)")
+	}
+	change := alert.Context.ReplicaChange
+	if change.From != 3 || change.To != 8 || change.Window != 15*time.Minute {
+		t.Errorf("Expected 3 → 8 in 15m, got %+v", change)
+	}
+	if !change.ScaledAt.Equal(history[5].Timestamp) {
+		t.Errorf("Expected ScaledAt from watcher history %s, got %s", history[5].Timestamp, change.ScaledAt)
+	}
+
+	// Janela de 5 min: só a ReplicaHistory, sem variação
+	thresholds.ReplicaDeltaWindowMinutes = 5
+	if alert := (&replicaSpikeDetector{}).Detect(Input{Current: current, History: history}, thresholds); alert != nil {
+		t.Errorf("Expected no spike within 5 minutes, got %+v", alert.Context.ReplicaChange)
+	}
+}
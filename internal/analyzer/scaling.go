@@ -0,0 +1,141 @@
+package analyzer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// hpaTolerance tolerância padrão do HPA controller (--horizontal-pod-autoscaler-tolerance):
+// utilização até 10% acima do target não dispara scale up
+const hpaTolerance = 0.1
+
+// stuckSignal motivo pelo qual o HPA parece travado
+type stuckSignal struct {
+	reason   string    // Ex: "ScalingLimited/TooManyReplicas"
+	message  string    // Mensagem reportada pelo HPA (ou montada pelo detector)
+	since    time.Time // LastTransitionTime da condição (zero se o sinal não vem de condição)
+	severity models.AlertSeverity
+}
+
+// stuckState desde quando o mesmo sinal é observado para um HPA
+type stuckState struct {
+	reason string
+	since  time.Time
+}
+
+// scalingStuckDetector HPA travado há mais de scaling_stuck_minutes: não consegue escalar
+// (AbleToScale/ScalingActive=False), limitado (ScalingLimited=True), desired > current
+// ou utilização acima do target sem escalar
+type scalingStuckDetector struct {
+	state map[string]stuckState // "cluster/namespace/name" -> sinal atual
+	mu    sync.Mutex
+}
+
+func (d *scalingStuckDetector) Type() models.AnomalyType { return models.AnomalyScalingStuck }
+
+func (d *scalingStuckDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	s := in.Current
+	key := s.Key()
+
+	signal := stuckSignalFor(s)
+
+	d.mu.Lock()
+	if d.state == nil {
+		d.state = make(map[string]stuckState)
+	}
+	if signal == nil {
+		delete(d.state, key)
+		d.mu.Unlock()
+		return nil
+	}
+
+	state, exists := d.state[key]
+	if !exists || state.reason != signal.reason {
+		state = stuckState{reason: signal.reason, since: s.Timestamp}
+		d.state[key] = state
+	}
+	d.mu.Unlock()
+
+	// Condições do HPA sabem desde quando estão nesse estado (sobrevive a restart do watchdog)
+	since := state.since
+	if !signal.since.IsZero() && signal.since.Before(since) {
+		since = signal.since
+	}
+
+	stuckFor := s.Timestamp.Sub(since)
+	if t.ScalingStuckMinutes < 1 || stuckFor < time.Duration(t.ScalingStuckMinutes)*time.Minute {
+		return nil
+	}
+
+	return newAlert(s, d.Type(), signal.severity,
+		fmt.Sprintf("Scaling travado há %s: %s", stuckFor.Round(time.Minute), signal.reason),
+		fmt.Sprintf("HPA reporta %s: %s. Réplicas: current %d, desired %d (min %d, max %d). Travado há %s (threshold: %dm).",
+			signal.reason, signal.message, s.CurrentReplicas, s.DesiredReplicas, s.MinReplicas, s.MaxReplicas,
+			stuckFor.Round(time.Second), t.ScalingStuckMinutes))
+}
+
+// Forget descarta o estado de um HPA removido
+func (d *scalingStuckDetector) Forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.state, key)
+}
+
+// stuckSignalFor retorna o sinal mais grave de HPA travado (nil se o HPA está saudável)
+func stuckSignalFor(s *models.HPASnapshot) *stuckSignal {
+	fromCondition := func(c *models.HPACondition, severity models.AlertSeverity) *stuckSignal {
+		return &stuckSignal{
+			reason:   c.Type + "/" + c.Reason,
+			message:  c.Message,
+			since:    c.LastTransitionTime,
+			severity: severity,
+		}
+	}
+
+	// Não consegue ler/atualizar o scale do workload (ex: FailedGetScale, FailedUpdateScale)
+	if c := s.Condition(models.ConditionAbleToScale); c != nil && !c.IsTrue() {
+		return fromCondition(c, models.SeverityCritical)
+	}
+
+	// Não consegue calcular réplicas (ex: FailedGetResourceMetric). ScalingDisabled = scale a zero intencional.
+	if c := s.Condition(models.ConditionScalingActive); c != nil && !c.IsTrue() && c.Reason != "ScalingDisabled" {
+		return fromCondition(c, models.SeverityCritical)
+	}
+
+	// Limitado pelo maxReplicas ou pela policy de scale up. TooFewReplicas/ScaleDownLimit são esperados com carga baixa.
+	if c := s.Condition(models.ConditionScalingLimited); c != nil && c.IsTrue() &&
+		c.Reason != "TooFewReplicas" && c.Reason != "ScaleDownLimit" {
+		return fromCondition(c, models.SeverityWarning)
+	}
+
+	if s.DesiredReplicas > s.CurrentReplicas {
+		return &stuckSignal{
+			reason:   "DesiredAboveCurrent",
+			message:  fmt.Sprintf("desired %d > current %d (pods não ficam prontos?)", s.DesiredReplicas, s.CurrentReplicas),
+			severity: models.SeverityWarning,
+		}
+	}
+
+	if s.CurrentReplicas < s.MaxReplicas {
+		if s.CPUTarget > 0 && s.CPUCurrent > float64(s.CPUTarget)*(1+hpaTolerance) {
+			return &stuckSignal{
+				reason:   "AboveTargetNotScaling",
+				message:  fmt.Sprintf("CPU %.1f%% acima do target %d%% sem aumentar réplicas", s.CPUCurrent, s.CPUTarget),
+				severity: models.SeverityWarning,
+			}
+		}
+		if s.MemoryTarget > 0 && s.MemoryCurrent > float64(s.MemoryTarget)*(1+hpaTolerance) {
+			return &stuckSignal{
+				reason:   "AboveTargetNotScaling",
+				message:  fmt.Sprintf("memory %.1f%% acima do target %d%% sem aumentar réplicas", s.MemoryCurrent, s.MemoryTarget),
+				severity: models.SeverityWarning,
+			}
+		}
+	}
+
+	return nil
+}
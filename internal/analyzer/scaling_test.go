@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+func TestScalingStuckTracksDuration(t *testing.T) {
+	thresholds := config.DefaultThresholds() // scaling_stuck_minutes: 10
+	d := &scalingStuckDetector{}
+
+	start := time.Now()
+	s := newTestSnapshot()
+	s.DesiredReplicas = 6
+
+	s.Timestamp = start
+	if alert := d.Detect(Input{Current: s}, thresholds); alert != nil {
+		t.Fatalf("Expected no alert on first observation, got %+v", alert)
+	}
+
+	s.Timestamp = start.Add(5 * time.Minute)
+	if alert := d.Detect(Input{Current: s}, thresholds); alert != nil {
+		t.Fatalf("Expected no alert before scaling_stuck_minutes, got %+v", alert)
+	}
+
+	s.Timestamp = start.Add(11 * time.Minute)
+	alert := d.Detect(Input{Current: s}, thresholds)
+	if alert == nil {
+		t.Fatal("Expected ScalingStuck alert after scaling_stuck_minutes")
+	}
+	if !strings.Contains(alert.Summary, "DesiredAboveCurrent") {
+		t.Errorf("Expected reason in summary, got %q", alert.Summary)
+	}
+
+	// HPA alcançou o desired: estado é limpo e a contagem recomeça
+	s.CurrentReplicas = 6
+	s.Timestamp = start.Add(12 * time.Minute)
+	if alert := d.Detect(Input{Current: s}, thresholds); alert != nil {
+		t.Errorf("Expected alert to clear, got %+v", alert)
+	}
+
+	s.DesiredReplicas = 8
+	s.Timestamp = start.Add(13 * time.Minute)
+	if alert := d.Detect(Input{Current: s}, thresholds); alert != nil {
+		t.Errorf("Expected duration to restart after recovery, got %+v", alert)
+	}
+}
+
+func TestScalingStuckUsesConditionReason(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+
+	s := newTestSnapshot()
+	s.Conditions = []models.HPACondition{
+		{Type: models.ConditionAbleToScale, Status: "True", Reason: "ReadyForNewScale"},
+		{
+			Type:               models.ConditionScalingActive,
+			Status:             "False",
+			Reason:             "FailedGetResourceMetric",
+			Message:            "the HPA was unable to compute the replica count: failed to get cpu utilization",
+			LastTransitionTime: s.Timestamp.Add(-20 * time.Minute),
+		},
+	}
+
+	// LastTransitionTime da condição vale mesmo na primeira observação
+	alert := (&scalingStuckDetector{}).Detect(Input{Current: s}, thresholds)
+	if alert == nil {
+		t.Fatal("Expected ScalingStuck alert from condition")
+	}
+
+	if alert.Severity != models.SeverityCritical {
+		t.Errorf("Expected Critical for FailedGetResourceMetric, got %s", alert.Severity)
+	}
+	if !strings.Contains(alert.Summary, "ScalingActive/FailedGetResourceMetric") {
+		t.Errorf("Expected condition reason in summary, got %q", alert.Summary)
+	}
+	if !strings.Contains(alert.Description, "failed to get cpu utilization") {
+		t.Errorf("Expected condition message in description, got %q", alert.Description)
+	}
+}
+
+func TestScalingStuckIgnoresExpectedLimits(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+
+	for _, reason := range []string{"TooFewReplicas", "ScaleDownLimit"} {
+		s := newTestSnapshot()
+		s.Conditions = []models.HPACondition{{
+			Type:               models.ConditionScalingLimited,
+			Status:             "True",
+			Reason:             reason,
+			LastTransitionTime: s.Timestamp.Add(-time.Hour),
+		}}
+
+		if alert := (&scalingStuckDetector{}).Detect(Input{Current: s}, thresholds); alert != nil {
+			t.Errorf("Expected no alert for ScalingLimited/%s, got %+v", reason, alert)
+		}
+	}
+}
+
+func TestScalingStuckForget(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+	d := &scalingStuckDetector{}
+	engine := NewEngine(config.NewThresholdManager(thresholds), d)
+
+	start := time.Now()
+	s := newTestSnapshot()
+	s.DesiredReplicas = 6
+	s.Timestamp = start
+	engine.AnalyzeSnapshot(s)
+
+	engine.Forget(s.Key())
+
+	// Sem estado anterior, a contagem recomeça do zero
+	s.Timestamp = start.Add(11 * time.Minute)
+	if alerts := engine.AnalyzeSnapshot(s); len(alerts) != 0 {
+		t.Errorf("Expected no alert after Forget, got %+v", alerts)
+	}
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// levelSeverity compara um valor com os thresholds de warning/critical
+func levelSeverity(value float64, warning, critical int32) (models.AlertSeverity, int32, bool) {
+	switch {
+	case critical > 0 && value >= float64(critical):
+		return models.SeverityCritical, critical, true
+	case warning > 0 && value >= float64(warning):
+		return models.SeverityWarning, warning, true
+	default:
+		return models.SeverityInfo, 0, false
+	}
+}
+
+// cpuSpikeDetector CPU atual acima de cpu_warning/critical_percent
+type cpuSpikeDetector struct{}
+
+func (d *cpuSpikeDetector) Type() models.AnomalyType { return models.AnomalyCPUSpike }
+
+func (d *cpuSpikeDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	s := in.Current
+
+	severity, threshold, ok := levelSeverity(s.CPUCurrent, t.CPUWarningPercent, t.CPUCriticalPercent)
+	if !ok {
+		return nil
+	}
+
+	return newAlert(s, d.Type(), severity,
+		fmt.Sprintf("CPU em %.1f%% (%s: %d%%)", s.CPUCurrent, severity, threshold),
+		fmt.Sprintf("Utilização de CPU %.1f%% atingiu o threshold %s de %d%%. Target do HPA: %d%%, réplicas: %d/%d.",
+			s.CPUCurrent, severity, threshold, s.CPUTarget, s.CurrentReplicas, s.MaxReplicas))
+}
+
+// memorySpikeDetector memória atual acima de memory_warning/critical_percent
+type memorySpikeDetector struct{}
+
+func (d *memorySpikeDetector) Type() models.AnomalyType { return models.AnomalyMemorySpike }
+
+func (d *memorySpikeDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	s := in.Current
+
+	severity, threshold, ok := levelSeverity(s.MemoryCurrent, t.MemoryWarningPercent, t.MemoryCriticalPercent)
+	if !ok {
+		return nil
+	}
+
+	return newAlert(s, d.Type(), severity,
+		fmt.Sprintf("Memory em %.1f%% (%s: %d%%)", s.MemoryCurrent, severity, threshold),
+		fmt.Sprintf("Utilização de memória %.1f%% atingiu o threshold %s de %d%%. Target do HPA: %d%%, réplicas: %d/%d.",
+			s.MemoryCurrent, severity, threshold, s.MemoryTarget, s.CurrentReplicas, s.MaxReplicas))
+}
+
+// maxedOutDetector HPA no maxReplicas com utilização ainda acima do target
+type maxedOutDetector struct{}
+
+func (d *maxedOutDetector) Type() models.AnomalyType { return models.AnomalyMaxedOut }
+
+func (d *maxedOutDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	s := in.Current
+
+	if s.MaxReplicas == 0 || s.CurrentReplicas < s.MaxReplicas {
+		return nil
+	}
+
+	cpuAbove := s.CPUTarget > 0 && s.CPUCurrent > float64(s.CPUTarget)
+	memAbove := s.MemoryTarget > 0 && s.MemoryCurrent > float64(s.MemoryTarget)
+	if !cpuAbove && !memAbove {
+		return nil
+	}
+
+	severity := models.SeverityWarning
+	if (cpuAbove && s.CPUCurrent >= float64(t.CPUCriticalPercent)) ||
+		(memAbove && s.MemoryCurrent >= float64(t.MemoryCriticalPercent)) {
+		severity = models.SeverityCritical
+	}
+
+	var parts []string
+	if cpuAbove {
+		parts = append(parts, fmt.Sprintf("CPU %.1f%% (target %d%%)", s.CPUCurrent, s.CPUTarget))
+	}
+	if memAbove {
+		parts = append(parts, fmt.Sprintf("memory %.1f%% (target %d%%)", s.MemoryCurrent, s.MemoryTarget))
+	}
+
+	return newAlert(s, d.Type(), severity,
+		fmt.Sprintf("No limite de %d réplicas com %s", s.MaxReplicas, strings.Join(parts, ", ")),
+		fmt.Sprintf("HPA está em maxReplicas (%d) e a utilização continua acima do target: %s. O HPA não consegue escalar mais.",
+			s.MaxReplicas, strings.Join(parts, ", ")))
+}
+
+// utilizationPoint utilização observada em um instante
+type utilizationPoint struct {
+	at    time.Time
+	value float64
+}
+
+// utilizationSeries retorna a série de utilização com timestamps, mais antiga primeiro.
+// Prefere o histórico do Prometheus (1 ponto a cada 30s); senão usa o histórico do watcher.
+func utilizationSeries(in Input, history []float64, current func(*models.HPASnapshot) float64) []utilizationPoint {
+	s := in.Current
+
+	if len(history) >= 2 {
+		points := make([]utilizationPoint, len(history))
+		for i, value := range history {
+			age := time.Duration(len(history)-1-i) * historyStep
+			points[i] = utilizationPoint{at: s.Timestamp.Add(-age), value: value}
+		}
+		return points
+	}
+
+	points := make([]utilizationPoint, 0, len(in.History)+1)
+	for i := range in.History {
+		if in.History[i].Timestamp.Before(s.Timestamp) {
+			points = append(points, utilizationPoint{at: in.History[i].Timestamp, value: current(&in.History[i])})
+		}
+	}
+	return append(points, utilizationPoint{at: s.Timestamp, value: current(s)})
+}
+
+// targetDeviation desvio percentual relativo ao target
+func targetDeviation(value float64, target int32) float64 {
+	return (value - float64(target)) / float64(target) * 100
+}
+
+// missSince retorna desde quando a série está continuamente fora do target, do mesmo
+// lado do ponto mais recente (zero se o ponto mais recente está dentro do target ± deviation)
+func missSince(series []utilizationPoint, target int32, deviation float64) time.Time {
+	latest := targetDeviation(series[len(series)-1].value, target)
+	if math.Abs(latest) <= deviation {
+		return time.Time{}
+	}
+
+	since := series[len(series)-1].at
+	for i := len(series) - 2; i >= 0; i-- {
+		d := targetDeviation(series[i].value, target)
+		if math.Abs(d) <= deviation || (d > 0) != (latest > 0) {
+			break
+		}
+		since = series[i].at
+	}
+	return since
+}
+
+// targetMissDetector utilização fora de target ± target_deviation_percent (desvio relativo ao target)
+// por mais de target_miss_minutes. Com histerese: o alerta só resolve quando a utilização volta
+// a target ± target_clear_deviation_percent.
+type targetMissDetector struct {
+	active map[string]time.Time // "cluster/namespace/name/recurso" -> início do desvio
+	mu     sync.Mutex
+}
+
+func (d *targetMissDetector) Type() models.AnomalyType { return models.AnomalyTargetMiss }
+
+func (d *targetMissDetector) Detect(in Input, t models.Thresholds) *models.UnifiedAlert {
+	s := in.Current
+
+	if t.TargetDeviationPercent <= 0 {
+		d.Forget(s.Key())
+		return nil
+	}
+
+	clearDeviation := t.TargetClearDeviationPercent
+	if clearDeviation <= 0 || clearDeviation > t.TargetDeviationPercent {
+		clearDeviation = t.TargetDeviationPercent
+	}
+	duration := time.Duration(t.TargetMissMinutes) * time.Minute
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.active == nil {
+		d.active = make(map[string]time.Time)
+	}
+
+	var parts []string
+	severity := models.SeverityInfo
+
+	check := func(resource string, series []utilizationPoint, target int32) {
+		stateKey := s.Key() + "/" + resource
+		latest := series[len(series)-1]
+
+		// Abaixo do target só importa se o HPA ainda pode reduzir réplicas
+		if target <= 0 || latest.value <= 0 || (latest.value < float64(target) && s.CurrentReplicas <= s.MinReplicas) {
+			delete(d.active, stateKey)
+			return
+		}
+
+		deviation := targetDeviation(latest.value, target)
+
+		since, active := d.active[stateKey]
+		if active && math.Abs(deviation) <= clearDeviation {
+			delete(d.active, stateKey)
+			return
+		}
+
+		if !active {
+			since = missSince(series, target, t.TargetDeviationPercent)
+			if since.IsZero() || latest.at.Sub(since) < duration {
+				return
+			}
+			d.active[stateKey] = since
+		}
+
+		if deviation > 0 {
+			severity = models.SeverityWarning
+		}
+		parts = append(parts, fmt.Sprintf("%s %.1f%% vs target %d%% (%+.0f%%) há %s",
+			resource, latest.value, target, deviation, latest.at.Sub(since).Round(time.Second)))
+	}
+
+	check("CPU", utilizationSeries(in, s.CPUHistory, func(h *models.HPASnapshot) float64 { return h.CPUCurrent }), s.CPUTarget)
+	check("memory", utilizationSeries(in, s.MemoryHistory, func(h *models.HPASnapshot) float64 { return h.MemoryCurrent }), s.MemoryTarget)
+
+	if len(parts) == 0 {
+		return nil
+	}
+
+	return newAlert(s, d.Type(), severity,
+		fmt.Sprintf("Fora do target: %s", strings.Join(parts, ", ")),
+		fmt.Sprintf("Utilização desviou mais de %.0f%% do target por pelo menos %dm: %s. Resolve ao voltar a ±%.0f%%. Réplicas: %d (min %d, max %d).",
+			t.TargetDeviationPercent, t.TargetMissMinutes, strings.Join(parts, ", "), clearDeviation,
+			s.CurrentReplicas, s.MinReplicas, s.MaxReplicas))
+}
+
+// Forget descarta o estado de um HPA removido
+func (d *targetMissDetector) Forget(key string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.active, key+"/CPU")
+	delete(d.active, key+"/memory")
+}
@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+func TestTargetMissRequiresSustainedDeviation(t *testing.T) {
+	thresholds := config.DefaultThresholds() // ±30% por 3 min, resolve em ±20%
+
+	s := newTestSnapshot()
+	s.CPUCurrent = 98
+
+	// Pico isolado: só os últimos 2 min (5 pontos de 30s) acima de 91% (target 70 + 30%)
+	s.CPUHistory = []float64{70, 72, 71, 95, 98, 97, 99, 98}
+	if alert := (&targetMissDetector{}).Detect(Input{Current: s}, thresholds); alert != nil {
+		t.Fatalf("Expected no alert before target_miss_minutes, got %q", alert.Summary)
+	}
+
+	// Desvio sustentado por 3 min e meio
+	s.CPUHistory = []float64{70, 95, 98, 97, 99, 98, 96, 97, 98}
+	alert := (&targetMissDetector{}).Detect(Input{Current: s}, thresholds)
+	if alert == nil {
+		t.Fatal("Expected TargetMiss alert after target_miss_minutes")
+	}
+	if alert.Severity != models.SeverityWarning {
+		t.Errorf("Expected Warning above target, got %s", alert.Severity)
+	}
+	if !strings.Contains(alert.Summary, "há 3m30s") {
+		t.Errorf("Expected deviation duration in summary, got %q", alert.Summary)
+	}
+}
+
+func TestTargetMissHysteresis(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+	thresholds.TargetMissMinutes = 0
+	d := &targetMissDetector{}
+
+	s := newTestSnapshot()
+	detect := func(cpu float64) *models.UnifiedAlert {
+		s.CPUCurrent = cpu
+		s.CPUHistory = nil
+		return d.Detect(Input{Current: s}, thresholds)
+	}
+
+	if detect(95) == nil { // +36%
+		t.Fatal("Expected alert above target_deviation_percent")
+	}
+
+	// +24%: dentro do trigger (30%) mas fora do clear (20%): continua ativo
+	if detect(87) == nil {
+		t.Error("Expected alert to stay active between clear and trigger thresholds")
+	}
+
+	// +14%: dentro do clear, resolve
+	if alert := detect(80); alert != nil {
+		t.Errorf("Expected alert to clear within target_clear_deviation_percent, got %q", alert.Summary)
+	}
+
+	// +24% sem estado ativo: não dispara de novo
+	if alert := detect(87); alert != nil {
+		t.Errorf("Expected no alert below trigger after clearing, got %q", alert.Summary)
+	}
+}
+
+func TestTargetMissIgnoresUnderutilizationAtMin(t *testing.T) {
+	thresholds := config.DefaultThresholds()
+
+	s := newTestSnapshot()
+	s.CurrentReplicas = s.MinReplicas
+	s.CPUCurrent = 20
+	s.CPUHistory = []float64{20, 20, 20, 20, 20, 20, 20, 20}
+
+	if alert := (&targetMissDetector{}).Detect(Input{Current: s}, thresholds); alert != nil {
+		t.Errorf("Expected no alert below target at minReplicas, got %q", alert.Summary)
+	}
+}
@@ -0,0 +1,264 @@
+package charts
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// DefaultStep intervalo entre pontos dos históricos do Prometheus (step das query range)
+const DefaultStep = 30 * time.Second
+
+// Símbolos do gráfico
+const (
+	markCPU     = '●'
+	markReplica = '■'
+	markOverlap = '◆' // CPU e réplicas na mesma célula
+	markTarget  = '─'
+	markBand    = '░'
+	markGuide   = '┈' // min/max réplicas
+)
+
+// sparkBlocks níveis da sparkline (do menor para o maior)
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renderiza os valores em uma linha de blocos escalada entre o mínimo e o máximo.
+// width <= 0 usa um caractere por ponto; caso contrário os pontos são reamostrados.
+func Sparkline(values []float64, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	if width > 0 {
+		values = resample(values, width)
+	}
+
+	low, high := Bounds(values)
+	var b strings.Builder
+	for _, v := range values {
+		level := len(sparkBlocks) / 2
+		if high > low {
+			level = int(math.Round((v - low) / (high - low) * float64(len(sparkBlocks)-1)))
+		}
+		b.WriteRune(sparkBlocks[level])
+	}
+	return b.String()
+}
+
+// Replicas converte um histórico de réplicas para float64 (Sparkline/Chart)
+func Replicas(values []int32) []float64 {
+	result := make([]float64, len(values))
+	for i, v := range values {
+		result[i] = float64(v)
+	}
+	return result
+}
+
+// Options dimensões e parâmetros do gráfico de um HPA
+type Options struct {
+	Width  int // Largura total, incluindo eixos (mínimo 30)
+	Height int // Linhas da área do gráfico, sem eixo de tempo e legenda (mínimo 4)
+
+	// BandPercent desvio relativo ao target sombreado em volta dele
+	// (thresholds.target_deviation_percent; 0 = só a linha do target)
+	BandPercent float64
+
+	Step time.Duration // Intervalo entre pontos (0 = DefaultStep)
+}
+
+// HPA renderiza a CPU vs target (com a banda de desvio sombreada) e as réplicas sobrepostas
+// com linhas guia de min/max réplicas. Retorna nil se o snapshot não tem histórico de CPU
+// nem de réplicas.
+//
+// Eixo esquerdo: CPU em % do request. Eixo direito: réplicas. As linhas seguem o
+// tempo da esquerda (mais antigo) para a direita (agora).
+func HPA(s *models.HPASnapshot, opts Options) []string {
+	cpu := s.CPUHistory
+	replicas := Replicas(s.ReplicaHistory)
+	if len(cpu) == 0 && len(replicas) == 0 {
+		return nil
+	}
+
+	if opts.Width < 30 {
+		opts.Width = 30
+	}
+	if opts.Height < 4 {
+		opts.Height = 4
+	}
+	if opts.Step <= 0 {
+		opts.Step = DefaultStep
+	}
+
+	const leftAxis, rightAxis = 6, 5 // "100% ┤" e "├ 10 "
+	width, height := opts.Width-leftAxis-rightAxis, opts.Height
+
+	// Escala da CPU: de 0 até o maior entre pico, target e banda (arredondado para 10)
+	target := float64(s.CPUTarget)
+	bandLow, bandHigh := target, target
+	if target > 0 && opts.BandPercent > 0 {
+		bandLow = target * (1 - opts.BandPercent/100)
+		bandHigh = target * (1 + opts.BandPercent/100)
+	}
+	_, cpuPeak := Bounds(cpu)
+	cpuTop := math.Ceil(math.Max(math.Max(cpuPeak, bandHigh), 10)/10) * 10
+
+	// Escala das réplicas: de 0 até o maior entre maxReplicas e o pico do histórico
+	_, replicaPeak := Bounds(replicas)
+	replicaTop := math.Max(math.Max(replicaPeak, float64(s.MaxReplicas)), 1)
+
+	rowOf := func(v, top float64) int {
+		row := int(math.Round(v / top * float64(height-1)))
+		if row < 0 {
+			row = 0
+		}
+		if row > height-1 {
+			row = height - 1
+		}
+		return row
+	}
+
+	grid := make([][]rune, height) // grid[0] = linha de baixo
+	for row := range grid {
+		grid[row] = []rune(strings.Repeat(" ", width))
+	}
+	fill := func(row int, mark rune) {
+		for col := range grid[row] {
+			grid[row][col] = mark
+		}
+	}
+
+	// Banda e target, depois as guias de réplicas por cima
+	if target > 0 {
+		for row := rowOf(bandLow, cpuTop); row <= rowOf(bandHigh, cpuTop); row++ {
+			fill(row, markBand)
+		}
+		fill(rowOf(target, cpuTop), markTarget)
+	}
+	if s.MaxReplicas > 0 {
+		fill(rowOf(float64(s.MinReplicas), replicaTop), markGuide)
+		fill(rowOf(float64(s.MaxReplicas), replicaTop), markGuide)
+	}
+
+	// Séries: réplicas e CPU (sobreposição marcada)
+	replicaRows := make([]int, width)
+	for col := range replicaRows {
+		replicaRows[col] = -1
+	}
+	for col, v := range resample(replicas, width) {
+		replicaRows[col] = rowOf(v, replicaTop)
+		grid[replicaRows[col]][col] = markReplica
+	}
+	for col, v := range resample(cpu, width) {
+		row := rowOf(v, cpuTop)
+		if replicaRows[col] == row {
+			grid[row][col] = markOverlap
+		} else {
+			grid[row][col] = markCPU
+		}
+	}
+
+	// Rótulos dos eixos: CPU no topo, no target e em 0; réplicas em max e min
+	leftLabels := map[int]string{height - 1: fmt.Sprintf("%.0f%%", cpuTop), 0: "0%"}
+	if target > 0 {
+		leftLabels[rowOf(target, cpuTop)] = fmt.Sprintf("%.0f%%", target)
+	}
+	rightLabels := map[int]string{}
+	if s.MaxReplicas > 0 {
+		rightLabels[rowOf(float64(s.MinReplicas), replicaTop)] = fmt.Sprintf("%d", s.MinReplicas)
+		rightLabels[rowOf(float64(s.MaxReplicas), replicaTop)] = fmt.Sprintf("%d", s.MaxReplicas)
+	} else {
+		rightLabels[height-1] = fmt.Sprintf("%.0f", replicaTop)
+	}
+
+	lines := make([]string, 0, height+2)
+	for row := height - 1; row >= 0; row-- {
+		left := fmt.Sprintf("%4s ┤", leftLabels[row])
+		if leftLabels[row] == "" {
+			left = "     │"
+		}
+		right := "│"
+		if label := rightLabels[row]; label != "" {
+			right = "├ " + label
+		}
+		lines = append(lines, left+string(grid[row])+fmt.Sprintf("%-*s", rightAxis, right))
+	}
+
+	// Eixo de tempo
+	points := len(cpu)
+	if len(replicas) > points {
+		points = len(replicas)
+	}
+	start := fmt.Sprintf("-%s", formatSpan(time.Duration(points)*opts.Step))
+	axis := []rune(strings.Repeat(" ", width))
+	copy(axis, []rune(start))
+	copy(axis[width-len("agora"):], []rune("agora"))
+	lines = append(lines, "     └"+strings.Repeat("─", width)+"┘", "      "+string(axis))
+
+	// Legenda
+	legend := fmt.Sprintf("%c CPU  %c réplicas  %c ambos", markCPU, markReplica, markOverlap)
+	if target > 0 {
+		legend += fmt.Sprintf("  %c target %d%%", markTarget, s.CPUTarget)
+		if opts.BandPercent > 0 {
+			legend += fmt.Sprintf("  %c ±%.0f%%", markBand, opts.BandPercent)
+		}
+	}
+	if s.MaxReplicas > 0 {
+		legend += fmt.Sprintf("  %c min/max %d/%d", markGuide, s.MinReplicas, s.MaxReplicas)
+	}
+	lines = append(lines, "      "+legend)
+
+	return lines
+}
+
+// resample ajusta a série para n pontos: estica repetindo pontos ou comprime pela média
+func resample(values []float64, n int) []float64 {
+	if len(values) == 0 || n <= 0 {
+		return nil
+	}
+
+	result := make([]float64, n)
+	if len(values) <= n {
+		for i := range result {
+			result[i] = values[i*len(values)/n]
+		}
+		return result
+	}
+
+	for i := range result {
+		from, to := i*len(values)/n, (i+1)*len(values)/n
+		sum := 0.0
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		result[i] = sum / float64(to-from)
+	}
+	return result
+}
+
+// Bounds menor e maior valor da série (0, 0 se vazia)
+func Bounds(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	low, high := values[0], values[0]
+	for _, v := range values[1:] {
+		low = math.Min(low, v)
+		high = math.Max(high, v)
+	}
+	return low, high
+}
+
+// formatSpan duração compacta do eixo de tempo: 5m, 90s, 2h
+func formatSpan(d time.Duration) string {
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d >= time.Minute && d%time.Minute == 0:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
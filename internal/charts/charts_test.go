@@ -0,0 +1,99 @@
+package charts
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"empty", nil, 10, ""},
+		{"one per point", []float64{0, 10, 20, 30, 40, 50, 60, 70}, 0, "▁▂▃▄▅▆▇█"},
+		{"flat", []float64{5, 5, 5}, 0, "▅▅▅"},
+		{"stretched", []float64{0, 100}, 4, "▁▁██"},
+		{"compressed by average", []float64{0, 0, 100, 100}, 2, "▁█"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("Sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+// row linha do gráfico pelo rótulo do eixo direito (réplicas)
+func row(lines []string, label string) string {
+	for _, line := range lines {
+		if strings.HasSuffix(strings.TrimRight(line, " "), "├ "+label) {
+			return line
+		}
+	}
+	return ""
+}
+
+func TestHPAChart(t *testing.T) {
+	s := &models.HPASnapshot{
+		CPUTarget:      70,
+		MinReplicas:    2,
+		MaxReplicas:    10,
+		CPUHistory:     []float64{40, 60, 80, 100, 100, 100, 100, 100, 100, 100},
+		ReplicaHistory: []int32{2, 2, 4, 8, 10, 10, 10, 10, 10, 10},
+	}
+
+	lines := HPA(s, Options{Width: 51, Height: 10, BandPercent: 30, Step: time.Minute})
+	if len(lines) != 13 {
+		t.Fatalf("Expected 10 rows + axis + time + legend, got %d:\n%s", len(lines), strings.Join(lines, "\n"))
+	}
+	chart := strings.Join(lines, "\n")
+
+	// Eixo de tempo cobre 10 pontos de 1m
+	if !strings.Contains(lines[11], "-10m") || !strings.HasSuffix(lines[11], "agora") {
+		t.Errorf("Expected time axis -10m..agora, got %q", lines[11])
+	}
+	if !strings.Contains(lines[12], "target 70%") || !strings.Contains(lines[12], "±30%") || !strings.Contains(lines[12], "min/max 2/10") {
+		t.Errorf("Expected legend with target, band and replica guides, got %q", lines[12])
+	}
+
+	// Maxed out: réplicas na guia de max junto com a CPU em 100% (mesma linha do topo)
+	top := row(lines, "10")
+	if !strings.HasPrefix(top, "100% ┤") || strings.Count(top, string(markOverlap)) != 24 {
+		t.Errorf("Expected CPU and replicas overlapping on max guide, got %q", top)
+	}
+	if bottom := row(lines, "2"); !strings.Contains(bottom, string(markReplica)) || !strings.Contains(bottom, string(markGuide)) {
+		t.Errorf("Expected min guide with replicas, got %q", bottom)
+	}
+
+	// Banda sombreada em volta do target (70% ±30% = 49%..91%)
+	if !strings.Contains(chart, " 70% ┤") || !strings.Contains(chart, string(markBand)) {
+		t.Errorf("Expected target line and shaded band:\n%s", chart)
+	}
+
+	for i, line := range lines[:10] {
+		if utf8.RuneCountInString(line) != 51 {
+			t.Errorf("Row %d has width %d: %q", i, utf8.RuneCountInString(line), line)
+		}
+	}
+}
+
+func TestHPAChartWithoutHistory(t *testing.T) {
+	if lines := HPA(&models.HPASnapshot{CPUTarget: 70}, Options{}); lines != nil {
+		t.Errorf("Expected nil chart without history, got %v", lines)
+	}
+
+	// Só réplicas, sem target nem maxReplicas: escala pelo pico
+	lines := HPA(&models.HPASnapshot{ReplicaHistory: []int32{1, 3, 1, 3}}, Options{Width: 40, Height: 4})
+	if len(lines) != 7 || !strings.HasSuffix(strings.TrimRight(lines[0], " "), "├ 3") || strings.Contains(lines[6], "target") {
+		t.Errorf("Expected replica-only chart, got:\n%s", strings.Join(lines, "\n"))
+	}
+}
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// ThresholdField threshold editável: chave em thresholds: no YAML, valor atual e setter validado
+type ThresholdField struct {
+	Key   string // Ex: "cpu_warning_percent"
+	Label string // Nome exibido na TUI
+	Unit  string // "%", "min", "ms" ou ""
+	Bool  bool   // Liga/desliga (ToggleConfigChangeAlert, ToggleResourceChangeAlert)
+
+	get    func(t *models.Thresholds) string
+	set    func(tm *ThresholdManager, value string) error
+	assign func(t *models.Thresholds, value string) error // Sem validação (overrides)
+}
+
+// Value valor atual formatado (mesmo formato escrito no YAML)
+func (f ThresholdField) Value(t models.Thresholds) string {
+	return f.get(&t)
+}
+
+// Set converte o valor e aplica pelo setter do ThresholdManager (com a validação dele)
+func (f ThresholdField) Set(tm *ThresholdManager, value string) error {
+	return f.set(tm, strings.TrimSpace(value))
+}
+
+// thresholdField campo pela chave do YAML
+func thresholdField(key string) (ThresholdField, bool) {
+	for _, field := range ThresholdFields() {
+		if field.Key == key {
+			return field, true
+		}
+	}
+	return ThresholdField{}, false
+}
+
+// ThresholdFields todos os thresholds editáveis, na ordem de configs/watchdog.yaml
+func ThresholdFields() []ThresholdField {
+	return []ThresholdField{
+		floatField("replica_delta_percent", "Variação de réplicas", "%",
+			func(t *models.Thresholds) *float64 { return &t.ReplicaDeltaPercent }, (*ThresholdManager).UpdateReplicaDeltaPercent),
+		int32Field("replica_delta_absolute", "Variação absoluta de réplicas", "",
+			func(t *models.Thresholds) *int32 { return &t.ReplicaDeltaAbsolute }, (*ThresholdManager).UpdateReplicaDeltaAbsolute),
+		intField("replica_delta_window_minutes", "Janela da variação", "min",
+			func(t *models.Thresholds) *int { return &t.ReplicaDeltaWindowMinutes }, (*ThresholdManager).UpdateReplicaDeltaWindow),
+		intField("oscillation_max_changes", "Máximo de mudanças (oscilação)", "",
+			func(t *models.Thresholds) *int { return &t.OscillationMaxChanges }, (*ThresholdManager).UpdateOscillationMaxChanges),
+
+		int32Field("cpu_warning_percent", "CPU warning", "%",
+			func(t *models.Thresholds) *int32 { return &t.CPUWarningPercent }, (*ThresholdManager).UpdateCPUWarning),
+		int32Field("cpu_critical_percent", "CPU crítico", "%",
+			func(t *models.Thresholds) *int32 { return &t.CPUCriticalPercent }, (*ThresholdManager).UpdateCPUCritical),
+		int32Field("memory_warning_percent", "Memória warning", "%",
+			func(t *models.Thresholds) *int32 { return &t.MemoryWarningPercent }, (*ThresholdManager).UpdateMemoryWarning),
+		int32Field("memory_critical_percent", "Memória crítico", "%",
+			func(t *models.Thresholds) *int32 { return &t.MemoryCriticalPercent }, (*ThresholdManager).UpdateMemoryCritical),
+
+		floatField("target_deviation_percent", "Desvio do target", "%",
+			func(t *models.Thresholds) *float64 { return &t.TargetDeviationPercent }, (*ThresholdManager).UpdateTargetDeviation),
+		floatField("target_clear_deviation_percent", "Desvio para resolver", "%",
+			func(t *models.Thresholds) *float64 { return &t.TargetClearDeviationPercent }, (*ThresholdManager).UpdateTargetClearDeviation),
+		intField("target_miss_minutes", "Tempo fora do target", "min",
+			func(t *models.Thresholds) *int { return &t.TargetMissMinutes }, (*ThresholdManager).UpdateTargetMissMinutes),
+
+		intField("scaling_stuck_minutes", "Scaling travado", "min",
+			func(t *models.Thresholds) *int { return &t.ScalingStuckMinutes }, (*ThresholdManager).UpdateScalingStuckMinutes),
+
+		boolField("alert_on_config_change", "Alertar mudança de config",
+			func(t *models.Thresholds) *bool { return &t.AlertOnConfigChange }, (*ThresholdManager).ToggleConfigChangeAlert),
+		boolField("alert_on_resource_change", "Alertar mudança de resources",
+			func(t *models.Thresholds) *bool { return &t.AlertOnResourceChange }, (*ThresholdManager).ToggleResourceChangeAlert),
+
+		floatField("request_rate_spike_percent", "Pico de request rate", "%",
+			func(t *models.Thresholds) *float64 { return &t.RequestRateSpikePercent }, (*ThresholdManager).UpdateRequestRateSpike),
+		floatField("error_rate_critical_percent", "Taxa de erros crítica", "%",
+			func(t *models.Thresholds) *float64 { return &t.ErrorRateCriticalPercent }, (*ThresholdManager).UpdateErrorRateCritical),
+		floatField("p95_latency_critical_ms", "Latência P95 crítica", "ms",
+			func(t *models.Thresholds) *float64 { return &t.P95LatencyCriticalMs }, (*ThresholdManager).UpdateP95LatencyCritical),
+	}
+}
+
+func intField(key, label, unit string, ptr func(*models.Thresholds) *int, set func(*ThresholdManager, int) error) ThresholdField {
+	parse := func(value string) (int, error) {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, fmt.Errorf("%s must be an integer", key)
+		}
+		return v, nil
+	}
+
+	return ThresholdField{
+		Key: key, Label: label, Unit: unit,
+		get: func(t *models.Thresholds) string { return strconv.Itoa(*ptr(t)) },
+		set: func(tm *ThresholdManager, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			return set(tm, v)
+		},
+		assign: func(t *models.Thresholds, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			*ptr(t) = v
+			return nil
+		},
+	}
+}
+
+func int32Field(key, label, unit string, ptr func(*models.Thresholds) *int32, set func(*ThresholdManager, int32) error) ThresholdField {
+	parse := func(value string) (int32, error) {
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("%s must be an integer", key)
+		}
+		return int32(v), nil
+	}
+
+	return ThresholdField{
+		Key: key, Label: label, Unit: unit,
+		get: func(t *models.Thresholds) string { return strconv.Itoa(int(*ptr(t))) },
+		set: func(tm *ThresholdManager, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			return set(tm, v)
+		},
+		assign: func(t *models.Thresholds, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			*ptr(t) = v
+			return nil
+		},
+	}
+}
+
+func floatField(key, label, unit string, ptr func(*models.Thresholds) *float64, set func(*ThresholdManager, float64) error) ThresholdField {
+	parse := func(value string) (float64, error) {
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%s must be a number", key)
+		}
+		return v, nil
+	}
+
+	return ThresholdField{
+		Key: key, Label: label, Unit: unit,
+		get: func(t *models.Thresholds) string { return strconv.FormatFloat(*ptr(t), 'f', -1, 64) },
+		set: func(tm *ThresholdManager, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			return set(tm, v)
+		},
+		assign: func(t *models.Thresholds, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			*ptr(t) = v
+			return nil
+		},
+	}
+}
+
+func boolField(key, label string, ptr func(*models.Thresholds) *bool, set func(*ThresholdManager, bool)) ThresholdField {
+	parse := func(value string) (bool, error) {
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, fmt.Errorf("%s must be true or false", key)
+		}
+		return v, nil
+	}
+
+	return ThresholdField{
+		Key: key, Label: label, Bool: true,
+		get: func(t *models.Thresholds) string { return strconv.FormatBool(*ptr(t)) },
+		set: func(tm *ThresholdManager, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			set(tm, v)
+			return nil
+		},
+		assign: func(t *models.Thresholds, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			*ptr(t) = v
+			return nil
+		},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 
 	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
 	"github.com/rs/zerolog/log"
@@ -21,12 +22,24 @@ func Load(configPath string) (*models.WatchdogConfig, error) {
 		configPath = filepath.Join(home, configPath[2:])
 	}
 
-	// Configura Viper
-	viper.SetConfigFile(configPath)
-	viper.SetConfigType("yaml")
+	// Instância própria do Viper: um reload inválido não afeta a configuração em uso
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	v.SetConfigType("yaml")
+
+	// Thresholds criados depois do watchdog.yaml original: configs antigas não têm as chaves e
+	// 0 mudaria o comportamento (ex: TargetMiss instantâneo, sem histerese)
+	defaults := DefaultThresholds()
+	v.SetDefault("thresholds.replica_delta_window_minutes", defaults.ReplicaDeltaWindowMinutes)
+	v.SetDefault("thresholds.oscillation_max_changes", defaults.OscillationMaxChanges)
+	v.SetDefault("thresholds.target_clear_deviation_percent", defaults.TargetClearDeviationPercent)
+	v.SetDefault("thresholds.target_miss_minutes", defaults.TargetMissMinutes)
+
+	// Mesmo padrão de monitor.DefaultCollectOptions: sem a chave, usa snapshots parciais
+	v.SetDefault("monitoring.collection.partial_results", true)
 
 	// Lê o arquivo
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
@@ -36,67 +49,100 @@ func Load(configPath string) (*models.WatchdogConfig, error) {
 	cfg := &models.WatchdogConfig{}
 
 	// Monitoring
-	cfg.ScanIntervalSeconds = viper.GetInt("monitoring.scan_interval_seconds")
-	cfg.HistoryRetentionMinutes = viper.GetInt("monitoring.history_retention_minutes")
+	cfg.ScanIntervalSeconds = v.GetInt("monitoring.scan_interval_seconds")
+	cfg.HistoryRetentionMinutes = v.GetInt("monitoring.history_retention_minutes")
+
+	// Collection
+	cfg.MaxConcurrentClusters = v.GetInt("monitoring.collection.max_concurrent_clusters")
+	cfg.MaxConcurrentNamespaces = v.GetInt("monitoring.collection.max_concurrent_namespaces")
+	cfg.ClusterTimeoutSeconds = v.GetInt("monitoring.collection.cluster_timeout_seconds")
+	cfg.PartialResults = v.GetBool("monitoring.collection.partial_results")
 
 	// Prometheus
-	cfg.PrometheusEnabled = viper.GetBool("monitoring.prometheus.enabled")
-	cfg.PrometheusAutoDiscover = viper.GetBool("monitoring.prometheus.auto_discover")
-	cfg.PrometheusFallback = viper.GetBool("monitoring.prometheus.fallback_to_metrics_server")
-	cfg.PrometheusEndpoints = viper.GetStringMapString("monitoring.prometheus.endpoints")
-	cfg.PrometheusDiscoveryPatterns = viper.GetStringSlice("monitoring.prometheus.discovery_patterns")
+	cfg.PrometheusEnabled = v.GetBool("monitoring.prometheus.enabled")
+	cfg.PrometheusAutoDiscover = v.GetBool("monitoring.prometheus.auto_discover")
+	cfg.PrometheusFallback = v.GetBool("monitoring.prometheus.fallback_to_metrics_server")
+	cfg.PrometheusEndpoints = v.GetStringMapString("monitoring.prometheus.endpoints")
+	cfg.PrometheusDiscoveryPatterns = v.GetStringSlice("monitoring.prometheus.discovery_patterns")
 
 	// Alertmanager
-	cfg.AlertmanagerEnabled = viper.GetBool("monitoring.alertmanager.enabled")
-	cfg.AlertmanagerAutoDiscover = viper.GetBool("monitoring.alertmanager.auto_discover")
-	cfg.AlertmanagerSyncInterval = viper.GetInt("monitoring.alertmanager.sync_interval_seconds")
-	cfg.AlertmanagerEndpoints = viper.GetStringMapString("monitoring.alertmanager.endpoints")
-	cfg.AlertmanagerDiscoveryPatterns = viper.GetStringSlice("monitoring.alertmanager.discovery_patterns")
+	cfg.AlertmanagerEnabled = v.GetBool("monitoring.alertmanager.enabled")
+	cfg.AlertmanagerAutoDiscover = v.GetBool("monitoring.alertmanager.auto_discover")
+	cfg.AlertmanagerSyncInterval = v.GetInt("monitoring.alertmanager.sync_interval_seconds")
+	cfg.AlertmanagerEndpoints = v.GetStringMapString("monitoring.alertmanager.endpoints")
+	cfg.AlertmanagerDiscoveryPatterns = v.GetStringSlice("monitoring.alertmanager.discovery_patterns")
+	cfg.AlertmanagerSilenceOnAck = v.GetBool("monitoring.alertmanager.silences.on_ack")
+	cfg.AlertmanagerSilenceMinutes = v.GetInt("monitoring.alertmanager.silences.duration_minutes")
+	cfg.AlertmanagerPushEnabled = v.GetBool("monitoring.alertmanager.push.enabled")
+	cfg.AlertmanagerPushResendSeconds = v.GetInt("monitoring.alertmanager.push.resend_interval_seconds")
+
+	// Alertmanager filters
+	cfg.AlertmanagerOnlyHPARelated = v.GetBool("monitoring.alertmanager.filters.only_hpa_related")
+	cfg.AlertmanagerExcludeSilenced = v.GetBool("monitoring.alertmanager.filters.exclude_silenced")
+	if minSeverity := v.GetString("monitoring.alertmanager.filters.min_severity"); minSeverity != "" {
+		severity, err := models.ParseAlertSeverity(minSeverity)
+		if err != nil {
+			return nil, fmt.Errorf("invalid configuration: alertmanager filters.min_severity: %w", err)
+		}
+		cfg.AlertmanagerMinSeverity = severity
+	}
+	if err := v.UnmarshalKey("monitoring.alertmanager.filters.hpa_matchers", &cfg.AlertmanagerHPAMatchers); err != nil {
+		return nil, fmt.Errorf("invalid configuration: alertmanager filters.hpa_matchers: %w", err)
+	}
 
 	// Clusters
-	cfg.ClustersConfigPath = viper.GetString("clusters.config_path")
-	cfg.AutoDiscoverClusters = viper.GetBool("clusters.auto_discover")
-	cfg.ExcludeClusters = viper.GetStringSlice("clusters.exclude")
+	cfg.ClustersConfigPath = v.GetString("clusters.config_path")
+	cfg.AutoDiscoverClusters = v.GetBool("clusters.auto_discover")
+	cfg.ExcludeClusters = v.GetStringSlice("clusters.exclude")
 
 	// Storage
-	cfg.EnablePersistence = viper.GetBool("storage.enable_persistence")
-	cfg.PersistencePath = viper.GetString("storage.persistence_path")
+	cfg.EnablePersistence = v.GetBool("storage.enable_persistence")
+	cfg.PersistencePath = v.GetString("storage.persistence_path")
+	cfg.PersistenceRetentionDays = v.GetInt("storage.retention_days")
 
 	// Alerts
-	cfg.MaxActiveAlerts = viper.GetInt("alerts.max_active_alerts")
-	cfg.AutoAckResolvedAlerts = viper.GetBool("alerts.auto_ack_resolved")
-	cfg.SourcePriority = viper.GetStringSlice("alerts.source_priority")
-	cfg.Deduplicate = viper.GetBool("alerts.deduplicate")
-	cfg.DedupeWindowMinutes = viper.GetInt("alerts.dedupe_window_minutes")
-	cfg.AutoCorrelate = viper.GetBool("alerts.auto_correlate")
-	cfg.CorrelationWindowMinutes = viper.GetInt("alerts.correlation_window_minutes")
+	cfg.MaxActiveAlerts = v.GetInt("alerts.max_active_alerts")
+	cfg.AutoAckResolvedAlerts = v.GetBool("alerts.auto_ack_resolved")
+	cfg.SourcePriority = v.GetStringSlice("alerts.source_priority")
+	cfg.Deduplicate = v.GetBool("alerts.deduplicate")
+	cfg.DedupeWindowMinutes = v.GetInt("alerts.dedupe_window_minutes")
+	cfg.AutoCorrelate = v.GetBool("alerts.auto_correlate")
+	cfg.CorrelationWindowMinutes = v.GetInt("alerts.correlation_window_minutes")
+	cfg.AckUser = v.GetString("alerts.ack_user")
 
 	// Thresholds
-	cfg.Thresholds.ReplicaDeltaPercent = viper.GetFloat64("thresholds.replica_delta_percent")
-	cfg.Thresholds.ReplicaDeltaAbsolute = int32(viper.GetInt("thresholds.replica_delta_absolute"))
-	cfg.Thresholds.CPUWarningPercent = int32(viper.GetInt("thresholds.cpu_warning_percent"))
-	cfg.Thresholds.CPUCriticalPercent = int32(viper.GetInt("thresholds.cpu_critical_percent"))
-	cfg.Thresholds.MemoryWarningPercent = int32(viper.GetInt("thresholds.memory_warning_percent"))
-	cfg.Thresholds.MemoryCriticalPercent = int32(viper.GetInt("thresholds.memory_critical_percent"))
-	cfg.Thresholds.TargetDeviationPercent = viper.GetFloat64("thresholds.target_deviation_percent")
-	cfg.Thresholds.ScalingStuckMinutes = viper.GetInt("thresholds.scaling_stuck_minutes")
-	cfg.Thresholds.AlertOnConfigChange = viper.GetBool("thresholds.alert_on_config_change")
-	cfg.Thresholds.AlertOnResourceChange = viper.GetBool("thresholds.alert_on_resource_change")
-	cfg.Thresholds.RequestRateSpikePercent = viper.GetFloat64("thresholds.request_rate_spike_percent")
-	cfg.Thresholds.ErrorRateCriticalPercent = viper.GetFloat64("thresholds.error_rate_critical_percent")
-	cfg.Thresholds.P95LatencyCriticalMs = viper.GetFloat64("thresholds.p95_latency_critical_ms")
+	cfg.Thresholds.ReplicaDeltaPercent = v.GetFloat64("thresholds.replica_delta_percent")
+	cfg.Thresholds.ReplicaDeltaAbsolute = int32(v.GetInt("thresholds.replica_delta_absolute"))
+	cfg.Thresholds.ReplicaDeltaWindowMinutes = v.GetInt("thresholds.replica_delta_window_minutes")
+	cfg.Thresholds.OscillationMaxChanges = v.GetInt("thresholds.oscillation_max_changes")
+	cfg.Thresholds.CPUWarningPercent = int32(v.GetInt("thresholds.cpu_warning_percent"))
+	cfg.Thresholds.CPUCriticalPercent = int32(v.GetInt("thresholds.cpu_critical_percent"))
+	cfg.Thresholds.MemoryWarningPercent = int32(v.GetInt("thresholds.memory_warning_percent"))
+	cfg.Thresholds.MemoryCriticalPercent = int32(v.GetInt("thresholds.memory_critical_percent"))
+	cfg.Thresholds.TargetDeviationPercent = v.GetFloat64("thresholds.target_deviation_percent")
+	cfg.Thresholds.TargetClearDeviationPercent = v.GetFloat64("thresholds.target_clear_deviation_percent")
+	cfg.Thresholds.TargetMissMinutes = v.GetInt("thresholds.target_miss_minutes")
+	cfg.Thresholds.ScalingStuckMinutes = v.GetInt("thresholds.scaling_stuck_minutes")
+	cfg.Thresholds.AlertOnConfigChange = v.GetBool("thresholds.alert_on_config_change")
+	cfg.Thresholds.AlertOnResourceChange = v.GetBool("thresholds.alert_on_resource_change")
+	cfg.Thresholds.RequestRateSpikePercent = v.GetFloat64("thresholds.request_rate_spike_percent")
+	cfg.Thresholds.ErrorRateCriticalPercent = v.GetFloat64("thresholds.error_rate_critical_percent")
+	cfg.Thresholds.P95LatencyCriticalMs = v.GetFloat64("thresholds.p95_latency_critical_ms")
+	if err := v.UnmarshalKey("threshold_overrides", &cfg.ThresholdOverrides); err != nil {
+		return nil, fmt.Errorf("invalid configuration: threshold_overrides: %w", err)
+	}
 
 	// UI
-	cfg.RefreshIntervalMs = viper.GetInt("ui.refresh_interval_ms")
-	cfg.Theme = viper.GetString("ui.theme")
-	cfg.EnableSounds = viper.GetBool("ui.enable_sounds")
+	cfg.RefreshIntervalMs = v.GetInt("ui.refresh_interval_ms")
+	cfg.Theme = v.GetString("ui.theme")
+	cfg.EnableSounds = v.GetBool("ui.enable_sounds")
 
 	// Logging
-	cfg.LogLevel = viper.GetString("logging.level")
-	cfg.LogOutput = viper.GetString("logging.output")
-	cfg.LogMaxSizeMB = viper.GetInt("logging.max_size_mb")
-	cfg.LogMaxBackups = viper.GetInt("logging.max_backups")
-	cfg.LogCompress = viper.GetBool("logging.compress")
+	cfg.LogLevel = v.GetString("logging.level")
+	cfg.LogOutput = v.GetString("logging.output")
+	cfg.LogMaxSizeMB = v.GetInt("logging.max_size_mb")
+	cfg.LogMaxBackups = v.GetInt("logging.max_backups")
+	cfg.LogCompress = v.GetBool("logging.compress")
 
 	// Validação básica
 	if err := validate(cfg); err != nil {
@@ -122,6 +168,44 @@ func validate(cfg *models.WatchdogConfig) error {
 		return fmt.Errorf("history_retention_minutes must be >= 1")
 	}
 
+	if cfg.MaxConcurrentClusters < 0 || cfg.MaxConcurrentNamespaces < 0 {
+		return fmt.Errorf("collection concurrency must be >= 0 (0 = default)")
+	}
+
+	if cfg.ClusterTimeoutSeconds < 0 {
+		return fmt.Errorf("cluster_timeout_seconds must be >= 0 (0 = default)")
+	}
+
+	if cfg.PersistenceRetentionDays < 0 {
+		return fmt.Errorf("storage.retention_days must be >= 0 (0 = default)")
+	}
+
+	if cfg.AlertmanagerSilenceMinutes < 0 {
+		return fmt.Errorf("alertmanager silences.duration_minutes must be >= 0 (0 = default)")
+	}
+
+	if cfg.AlertmanagerPushResendSeconds < 0 {
+		return fmt.Errorf("alertmanager push.resend_interval_seconds must be >= 0 (0 = default)")
+	}
+
+	for i, matcher := range cfg.AlertmanagerHPAMatchers {
+		if err := validateAlertMatcher(matcher); err != nil {
+			return fmt.Errorf("alertmanager filters.hpa_matchers[%d]: %w", i, err)
+		}
+	}
+
+	seen := make(map[models.AlertSource]bool)
+	for _, name := range cfg.SourcePriority {
+		source, err := models.ParseAlertSource(name)
+		if err != nil {
+			return fmt.Errorf("source_priority: %w", err)
+		}
+		if seen[source] {
+			return fmt.Errorf("source_priority: duplicate source %q", name)
+		}
+		seen[source] = true
+	}
+
 	if cfg.MaxActiveAlerts < 1 {
 		return fmt.Errorf("max_active_alerts must be >= 1")
 	}
@@ -151,6 +235,31 @@ func validate(cfg *models.WatchdogConfig) error {
 		return fmt.Errorf("memory_critical_percent must be > memory_warning_percent")
 	}
 
+	if cfg.Thresholds.TargetClearDeviationPercent > cfg.Thresholds.TargetDeviationPercent {
+		return fmt.Errorf("target_clear_deviation_percent must be <= target_deviation_percent")
+	}
+
+	return validateOverrides(cfg.Thresholds, cfg.ThresholdOverrides)
+}
+
+// validateAlertMatcher valida que o matcher tem alertname ou label e que as regex compilam
+func validateAlertMatcher(matcher models.AlertMatcher) error {
+	if matcher.AlertName == "" && matcher.Label == "" {
+		return fmt.Errorf("alertname or label is required")
+	}
+
+	if matcher.Value != "" && matcher.Label == "" {
+		return fmt.Errorf("value requires label")
+	}
+
+	if _, err := regexp.Compile(matcher.AlertName); err != nil {
+		return fmt.Errorf("invalid alertname regex: %w", err)
+	}
+
+	if _, err := regexp.Compile(matcher.Value); err != nil {
+		return fmt.Errorf("invalid value regex: %w", err)
+	}
+
 	return nil
 }
 
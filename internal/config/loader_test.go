@@ -3,7 +3,10 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
 )
 
 func TestExpandPath(t *testing.T) {
@@ -61,6 +64,11 @@ func TestLoad(t *testing.T) {
 monitoring:
   scan_interval_seconds: 30
   history_retention_minutes: 5
+  collection:
+    max_concurrent_clusters: 4
+    max_concurrent_namespaces: 8
+    cluster_timeout_seconds: 20
+    partial_results: true
   prometheus:
     enabled: true
     auto_discover: true
@@ -69,6 +77,20 @@ monitoring:
     enabled: true
     auto_discover: true
     sync_interval_seconds: 30
+    silences:
+      on_ack: true
+      duration_minutes: 60
+    push:
+      enabled: true
+      resend_interval_seconds: 45
+    filters:
+      only_hpa_related: true
+      exclude_silenced: true
+      min_severity: "Warning"
+      hpa_matchers:
+        - alertname: "^KubeHpa"
+        - label: "namespace"
+          value: "^prod"
 
 clusters:
   auto_discover: true
@@ -77,6 +99,7 @@ clusters:
 
 storage:
   enable_persistence: false
+  retention_days: 3
 
 alerts:
   max_active_alerts: 100
@@ -88,10 +111,12 @@ alerts:
   dedupe_window_minutes: 5
   auto_correlate: true
   correlation_window_minutes: 10
+  ack_user: oncall
 
 thresholds:
   replica_delta_percent: 50.0
   replica_delta_absolute: 5
+  replica_delta_window_minutes: 3
   cpu_warning_percent: 85
   cpu_critical_percent: 90
   memory_warning_percent: 85
@@ -132,6 +157,18 @@ logging:
 		t.Errorf("ScanIntervalSeconds = %d, want 30", cfg.ScanIntervalSeconds)
 	}
 
+	if cfg.ClusterTimeoutSeconds != 20 {
+		t.Errorf("ClusterTimeoutSeconds = %d, want 20", cfg.ClusterTimeoutSeconds)
+	}
+
+	if cfg.MaxConcurrentClusters != 4 || !cfg.PartialResults {
+		t.Errorf("Collection config = %d/%v, want 4/true", cfg.MaxConcurrentClusters, cfg.PartialResults)
+	}
+
+	if cfg.Thresholds.ReplicaDeltaWindowMinutes != 3 {
+		t.Errorf("ReplicaDeltaWindowMinutes = %d, want 3", cfg.Thresholds.ReplicaDeltaWindowMinutes)
+	}
+
 	if cfg.Thresholds.CPUWarningPercent != 85 {
 		t.Errorf("CPUWarningPercent = %d, want 85", cfg.Thresholds.CPUWarningPercent)
 	}
@@ -144,11 +181,119 @@ logging:
 		t.Error("PrometheusEnabled = false, want true")
 	}
 
+	if !cfg.AlertmanagerSilenceOnAck || cfg.AlertmanagerSilenceMinutes != 60 {
+		t.Errorf("Alertmanager silences = %v/%d, want true/60", cfg.AlertmanagerSilenceOnAck, cfg.AlertmanagerSilenceMinutes)
+	}
+
+	if !cfg.AlertmanagerPushEnabled || cfg.AlertmanagerPushResendSeconds != 45 {
+		t.Errorf("Alertmanager push = %v/%d, want true/45", cfg.AlertmanagerPushEnabled, cfg.AlertmanagerPushResendSeconds)
+	}
+
+	if !cfg.AlertmanagerOnlyHPARelated || !cfg.AlertmanagerExcludeSilenced || cfg.AlertmanagerMinSeverity != models.SeverityWarning {
+		t.Errorf("Alertmanager filters = %v/%v/%s, want true/true/Warning",
+			cfg.AlertmanagerOnlyHPARelated, cfg.AlertmanagerExcludeSilenced, cfg.AlertmanagerMinSeverity)
+	}
+
+	matchers := cfg.AlertmanagerHPAMatchers
+	if len(matchers) != 2 || matchers[0].AlertName != "^KubeHpa" || matchers[1].Label != "namespace" || matchers[1].Value != "^prod" {
+		t.Errorf("AlertmanagerHPAMatchers = %+v", matchers)
+	}
+
+	if cfg.AckUser != "oncall" {
+		t.Errorf("AckUser = %q, want oncall", cfg.AckUser)
+	}
+
+	if cfg.PersistenceRetentionDays != 3 {
+		t.Errorf("PersistenceRetentionDays = %d, want 3", cfg.PersistenceRetentionDays)
+	}
+
 	if !cfg.AutoDiscoverClusters {
 		t.Error("AutoDiscoverClusters = false, want true")
 	}
 }
 
+func TestLoadMissingNewThresholds(t *testing.T) {
+	original, err := os.ReadFile("../../configs/watchdog.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// watchdog.yaml anterior aos thresholds de janela, oscilação e TargetMiss
+	var kept []string
+	for _, line := range strings.Split(string(original), "\n") {
+		key := strings.TrimSpace(line)
+		if strings.HasPrefix(key, "replica_delta_window_minutes:") || strings.HasPrefix(key, "oscillation_max_changes:") ||
+			strings.HasPrefix(key, "target_clear_deviation_percent:") || strings.HasPrefix(key, "target_miss_minutes:") {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	path := filepath.Join(t.TempDir(), "watchdog.yaml")
+	if err := os.WriteFile(path, []byte(strings.Join(kept, "\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	defaults := DefaultThresholds()
+	got := cfg.Thresholds
+	if got.TargetMissMinutes != defaults.TargetMissMinutes || got.TargetClearDeviationPercent != defaults.TargetClearDeviationPercent ||
+		got.ReplicaDeltaWindowMinutes != defaults.ReplicaDeltaWindowMinutes || got.OscillationMaxChanges != defaults.OscillationMaxChanges {
+		t.Errorf("Expected missing thresholds to use defaults, got %+v", got)
+	}
+
+	// 0 explícito continua valendo (TargetMiss imediato)
+	if err := os.WriteFile(path, []byte(strings.Replace(string(original), "target_miss_minutes: 3", "target_miss_minutes: 0", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if cfg, err := Load(path); err != nil || cfg.Thresholds.TargetMissMinutes != 0 {
+		t.Errorf("Expected explicit target_miss_minutes 0 kept, got %v", err)
+	}
+}
+
+func TestLoadMissingPartialResults(t *testing.T) {
+	original, err := os.ReadFile("../../configs/watchdog.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "watchdog.yaml")
+	for _, tt := range []struct {
+		name    string
+		replace string
+		want    bool
+	}{
+		{"missing", "", true},
+		{"explicit false", "partial_results: false", false},
+	} {
+		var lines []string
+		for _, line := range strings.Split(string(original), "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "partial_results:") {
+				if tt.replace == "" {
+					continue
+				}
+				line = strings.Replace(line, strings.TrimSpace(line), tt.replace, 1)
+			}
+			lines = append(lines, line)
+		}
+		if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		cfg, err := Load(path)
+		if err != nil {
+			t.Fatalf("%s: Load() error = %v", tt.name, err)
+		}
+		if cfg.PartialResults != tt.want {
+			t.Errorf("%s: PartialResults = %v, want %v", tt.name, cfg.PartialResults, tt.want)
+		}
+	}
+}
+
 func TestValidate(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -191,6 +336,132 @@ thresholds:
   memory_critical_percent: 90
 alerts:
   max_active_alerts: 100
+`
+				os.WriteFile(configPath, []byte(content), 0644)
+				return configPath
+			},
+			wantErr: true,
+		},
+		{
+			name: "target_clear_deviation > target_deviation",
+			configure: func(t *testing.T) string {
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "watchdog.yaml")
+				content := `
+monitoring:
+  scan_interval_seconds: 30
+  history_retention_minutes: 5
+thresholds:
+  cpu_warning_percent: 85
+  cpu_critical_percent: 90
+  memory_warning_percent: 85
+  memory_critical_percent: 90
+  target_deviation_percent: 20.0
+  target_clear_deviation_percent: 30.0
+alerts:
+  max_active_alerts: 100
+`
+				os.WriteFile(configPath, []byte(content), 0644)
+				return configPath
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid alertmanager min_severity",
+			configure: func(t *testing.T) string {
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "watchdog.yaml")
+				content := `
+monitoring:
+  scan_interval_seconds: 30
+  history_retention_minutes: 5
+  alertmanager:
+    filters:
+      min_severity: "urgent"
+thresholds:
+  cpu_warning_percent: 85
+  cpu_critical_percent: 90
+  memory_warning_percent: 85
+  memory_critical_percent: 90
+alerts:
+  max_active_alerts: 100
+`
+				os.WriteFile(configPath, []byte(content), 0644)
+				return configPath
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid alertmanager hpa_matchers regex",
+			configure: func(t *testing.T) string {
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "watchdog.yaml")
+				content := `
+monitoring:
+  scan_interval_seconds: 30
+  history_retention_minutes: 5
+  alertmanager:
+    filters:
+      hpa_matchers:
+        - alertname: "^KubeHpa("
+thresholds:
+  cpu_warning_percent: 85
+  cpu_critical_percent: 90
+  memory_warning_percent: 85
+  memory_critical_percent: 90
+alerts:
+  max_active_alerts: 100
+`
+				os.WriteFile(configPath, []byte(content), 0644)
+				return configPath
+			},
+			wantErr: true,
+		},
+		{
+			name: "alertmanager hpa_matcher without alertname or label",
+			configure: func(t *testing.T) string {
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "watchdog.yaml")
+				content := `
+monitoring:
+  scan_interval_seconds: 30
+  history_retention_minutes: 5
+  alertmanager:
+    filters:
+      hpa_matchers:
+        - value: "api"
+thresholds:
+  cpu_warning_percent: 85
+  cpu_critical_percent: 90
+  memory_warning_percent: 85
+  memory_critical_percent: 90
+alerts:
+  max_active_alerts: 100
+`
+				os.WriteFile(configPath, []byte(content), 0644)
+				return configPath
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid source_priority",
+			configure: func(t *testing.T) string {
+				tmpDir := t.TempDir()
+				configPath := filepath.Join(tmpDir, "watchdog.yaml")
+				content := `
+monitoring:
+  scan_interval_seconds: 30
+  history_retention_minutes: 5
+thresholds:
+  cpu_warning_percent: 85
+  cpu_critical_percent: 90
+  memory_warning_percent: 85
+  memory_critical_percent: 90
+alerts:
+  max_active_alerts: 100
+  source_priority:
+    - alertmanager
+    - pagerduty
 `
 				os.WriteFile(configPath, []byte(content), 0644)
 				return configPath
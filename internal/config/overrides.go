@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// ThresholdLayer camada de onde vem um threshold efetivo (da menos para a mais específica)
+type ThresholdLayer int
+
+const (
+	LayerGlobal ThresholdLayer = iota
+	LayerCluster
+	LayerNamespace
+	LayerHPA
+)
+
+func (l ThresholdLayer) String() string {
+	switch l {
+	case LayerCluster:
+		return "cluster"
+	case LayerNamespace:
+		return "namespace"
+	case LayerHPA:
+		return "hpa"
+	default:
+		return "global"
+	}
+}
+
+// ThresholdSource valor efetivo de um threshold e a camada que o definiu (explain)
+type ThresholdSource struct {
+	Key      string
+	Value    string
+	Unit     string
+	Layer    ThresholdLayer
+	Override int    // Índice em threshold_overrides (-1 = thresholds globais)
+	Scope    string // Seletores do override (ex: "namespace=batch-*"); vazio na camada global
+}
+
+// OverrideLayer camada do override: a do seletor mais específico preenchido
+func OverrideLayer(o models.ThresholdOverride) ThresholdLayer {
+	switch {
+	case o.HPA != "":
+		return LayerHPA
+	case o.Namespace != "":
+		return LayerNamespace
+	default:
+		return LayerCluster
+	}
+}
+
+// OverrideScope seletores preenchidos do override (ex: "cluster=prod-* namespace=batch-*")
+func OverrideScope(o models.ThresholdOverride) string {
+	var parts []string
+	if o.Cluster != "" {
+		parts = append(parts, "cluster="+o.Cluster)
+	}
+	if o.Namespace != "" {
+		parts = append(parts, "namespace="+o.Namespace)
+	}
+	if o.HPA != "" {
+		parts = append(parts, "hpa="+o.HPA)
+	}
+	return strings.Join(parts, " ")
+}
+
+// overrideMatches o override casa com o HPA (seletor vazio casa com qualquer valor)
+func overrideMatches(o models.ThresholdOverride, cluster, namespace, name string) bool {
+	return globMatch(o.Cluster, cluster) && globMatch(o.Namespace, namespace) && globMatch(o.HPA, name)
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, value)
+	return ok
+}
+
+// matchingOverrides índices dos overrides que casam com o HPA, na ordem de aplicação:
+// cluster → namespace → HPA e, na mesma camada, a ordem do arquivo (o último vence)
+func matchingOverrides(overrides []models.ThresholdOverride, cluster, namespace, name string) []int {
+	var matched []int
+	for i, o := range overrides {
+		if overrideMatches(o, cluster, namespace, name) {
+			matched = append(matched, i)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		return OverrideLayer(overrides[matched[i]]) < OverrideLayer(overrides[matched[j]])
+	})
+	return matched
+}
+
+// ResolveThresholds aplica sobre os globais os overrides que casam com o HPA e retorna os
+// thresholds efetivos com a origem de cada um (na ordem de ThresholdFields)
+func ResolveThresholds(global models.Thresholds, overrides []models.ThresholdOverride, cluster, namespace, name string) (models.Thresholds, []ThresholdSource) {
+	fields := ThresholdFields()
+	byKey := make(map[string]ThresholdField, len(fields))
+	for _, field := range fields {
+		byKey[field.Key] = field
+	}
+
+	resolved := global
+	origin := make(map[string]int) // chave -> override que definiu o valor
+	for _, i := range matchingOverrides(overrides, cluster, namespace, name) {
+		for key, value := range overrides[i].Thresholds {
+			field, ok := byKey[key]
+			if !ok {
+				continue
+			}
+			// Overrides são validados ao carregar; valor inválido aqui é ignorado
+			if err := field.assign(&resolved, strings.TrimSpace(value)); err != nil {
+				continue
+			}
+			origin[key] = i
+		}
+	}
+
+	sources := make([]ThresholdSource, 0, len(fields))
+	for _, field := range fields {
+		source := ThresholdSource{
+			Key:      field.Key,
+			Value:    field.Value(resolved),
+			Unit:     field.Unit,
+			Layer:    LayerGlobal,
+			Override: -1,
+		}
+		if i, ok := origin[field.Key]; ok {
+			source.Layer = OverrideLayer(overrides[i])
+			source.Override = i
+			source.Scope = OverrideScope(overrides[i])
+		}
+		sources = append(sources, source)
+	}
+
+	return resolved, sources
+}
+
+// validateOverrides valida seletores e valores; cada override precisa ser válido sobre os globais
+// (ex: subir cpu_warning_percent acima do cpu_critical_percent global exige sobrescrever os dois)
+func validateOverrides(global models.Thresholds, overrides []models.ThresholdOverride) error {
+	for i, o := range overrides {
+		if o.Cluster == "" && o.Namespace == "" && o.HPA == "" {
+			return fmt.Errorf("threshold_overrides[%d]: cluster, namespace or hpa is required", i)
+		}
+
+		for _, selector := range []struct{ name, pattern string }{
+			{"cluster", o.Cluster}, {"namespace", o.Namespace}, {"hpa", o.HPA},
+		} {
+			if _, err := path.Match(selector.pattern, ""); err != nil {
+				return fmt.Errorf("threshold_overrides[%d]: invalid %s glob %q: %w", i, selector.name, selector.pattern, err)
+			}
+		}
+
+		if len(o.Thresholds) == 0 {
+			return fmt.Errorf("threshold_overrides[%d]: thresholds is required", i)
+		}
+
+		keys := make([]string, 0, len(o.Thresholds))
+		for key := range o.Thresholds {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		t := global
+		for _, key := range keys {
+			field, ok := thresholdField(key)
+			if !ok {
+				return fmt.Errorf("threshold_overrides[%d]: unknown threshold %q", i, key)
+			}
+			if err := field.assign(&t, strings.TrimSpace(o.Thresholds[key])); err != nil {
+				return fmt.Errorf("threshold_overrides[%d]: %w", i, err)
+			}
+		}
+
+		if err := validateThresholds(&t); err != nil {
+			return fmt.Errorf("threshold_overrides[%d] (%s): %w", i, OverrideScope(o), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// sourceOf origem de um threshold no resultado do explain
+func sourceOf(t *testing.T, sources []ThresholdSource, key string) ThresholdSource {
+	t.Helper()
+	for _, source := range sources {
+		if source.Key == key {
+			return source
+		}
+	}
+	t.Fatalf("Threshold %s not found", key)
+	return ThresholdSource{}
+}
+
+func TestResolveThresholds(t *testing.T) {
+	overrides := []models.ThresholdOverride{
+		// HPA declarado antes: a camada vale mais que a ordem do arquivo
+		{Namespace: "batch-*", HPA: "report-*", Thresholds: map[string]string{"cpu_critical_percent": "99"}},
+		{Namespace: "batch-*", Thresholds: map[string]string{"cpu_warning_percent": "95", "cpu_critical_percent": "98"}},
+		{Cluster: "prod-*", Thresholds: map[string]string{"cpu_warning_percent": "80", "memory_warning_percent": "80"}},
+		// Mesma camada: o último vence
+		{Cluster: "prod-eu", Thresholds: map[string]string{"memory_warning_percent": "75"}},
+		{Cluster: "staging", Thresholds: map[string]string{"memory_warning_percent": "60"}},
+	}
+
+	resolved, sources := ResolveThresholds(DefaultThresholds(), overrides, "prod-eu", "batch-jobs", "report-worker")
+
+	if resolved.CPUWarningPercent != 95 || resolved.CPUCriticalPercent != 99 || resolved.MemoryWarningPercent != 75 || resolved.MemoryCriticalPercent != 90 {
+		t.Errorf("Unexpected resolved thresholds: %+v", resolved)
+	}
+	if len(sources) != len(ThresholdFields()) {
+		t.Errorf("Expected one source per threshold, got %d", len(sources))
+	}
+
+	tests := []struct {
+		key      string
+		value    string
+		layer    ThresholdLayer
+		override int
+	}{
+		{"cpu_warning_percent", "95", LayerNamespace, 1},
+		{"cpu_critical_percent", "99", LayerHPA, 0},
+		{"memory_warning_percent", "75", LayerCluster, 3},
+		{"memory_critical_percent", "90", LayerGlobal, -1},
+	}
+	for _, tt := range tests {
+		source := sourceOf(t, sources, tt.key)
+		if source.Value != tt.value || source.Layer != tt.layer || source.Override != tt.override {
+			t.Errorf("%s: expected %s from %s[%d], got %+v", tt.key, tt.value, tt.layer, tt.override, source)
+		}
+	}
+	if scope := sourceOf(t, sources, "cpu_critical_percent").Scope; scope != "namespace=batch-* hpa=report-*" {
+		t.Errorf("Unexpected scope %q", scope)
+	}
+
+	// HPA fora dos globs: só a camada de cluster se aplica
+	resolved, _ = ResolveThresholds(DefaultThresholds(), overrides, "prod-us", "api", "checkout")
+	if resolved.CPUWarningPercent != 80 || resolved.CPUCriticalPercent != 90 || resolved.MemoryWarningPercent != 80 {
+		t.Errorf("Expected only the prod-* override, got %+v", resolved)
+	}
+}
+
+func TestThresholdManagerOverrides(t *testing.T) {
+	tm := NewThresholdManager(DefaultThresholds())
+
+	tests := []struct {
+		name     string
+		override models.ThresholdOverride
+		wantErr  string
+	}{
+		{"no selector", models.ThresholdOverride{Thresholds: map[string]string{"cpu_warning_percent": "80"}}, "cluster, namespace or hpa is required"},
+		{"bad glob", models.ThresholdOverride{Namespace: "batch-[", Thresholds: map[string]string{"cpu_warning_percent": "80"}}, "invalid namespace glob"},
+		{"empty", models.ThresholdOverride{Cluster: "prod"}, "thresholds is required"},
+		{"unknown key", models.ThresholdOverride{Cluster: "prod", Thresholds: map[string]string{"cpu_warn": "80"}}, `unknown threshold "cpu_warn"`},
+		{"not a number", models.ThresholdOverride{Cluster: "prod", Thresholds: map[string]string{"target_deviation_percent": "alto"}}, "must be a number"},
+		// Sobre os globais (critical 90) um warning de 95 não é válido sozinho
+		{"invalid over global", models.ThresholdOverride{Namespace: "batch-*", Thresholds: map[string]string{"cpu_warning_percent": "95"}}, "cpu_critical_percent must be > cpu_warning_percent"},
+	}
+
+	for _, tt := range tests {
+		err := tm.SetOverrides([]models.ThresholdOverride{tt.override})
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.wantErr, err)
+		}
+	}
+	if len(tm.Overrides()) != 0 {
+		t.Error("Expected invalid overrides rejected")
+	}
+
+	if err := tm.SetOverrides([]models.ThresholdOverride{{Namespace: "batch-*", Thresholds: map[string]string{"cpu_warning_percent": "95", "cpu_critical_percent": "98"}}}); err != nil {
+		t.Fatalf("SetOverrides() error = %v", err)
+	}
+	if got := tm.For("prod", "batch-etl", "loader").CPUWarningPercent; got != 95 {
+		t.Errorf("Expected batch override 95, got %d", got)
+	}
+	if got := tm.For("prod", "api", "checkout").CPUWarningPercent; got != 85 {
+		t.Errorf("Expected global 85 outside batch, got %d", got)
+	}
+
+	// Global editado (TUI/reload) continua valendo onde não há override
+	if err := tm.UpdateCPUWarning(70); err != nil {
+		t.Fatal(err)
+	}
+	if got := sourceOf(t, tm.Explain("prod", "api", "checkout"), "cpu_warning_percent"); got.Value != "70" || got.Layer != LayerGlobal {
+		t.Errorf("Expected global 70, got %+v", got)
+	}
+}
+
+func TestThresholdManagerSettersKeepOverridesValid(t *testing.T) {
+	tm := NewThresholdManager(DefaultThresholds())
+	if err := tm.SetOverrides([]models.ThresholdOverride{{Namespace: "batch-*", Thresholds: map[string]string{"cpu_critical_percent": "88"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Global 89 é válido sozinho (critical 90), mas batch-* resolveria warning 89 > critical 88
+	err := tm.UpdateCPUWarning(89)
+	if err == nil || !strings.Contains(err.Error(), "threshold_overrides[0] (namespace=batch-*)") {
+		t.Fatalf("Expected global edit rejected by override, got %v", err)
+	}
+	if got := tm.Get().CPUWarningPercent; got != 85 {
+		t.Errorf("Expected global warning unchanged, got %d", got)
+	}
+
+	updated := DefaultThresholds()
+	updated.CPUWarningPercent = 89
+	if err := tm.UpdateAll(updated); err == nil {
+		t.Error("Expected UpdateAll rejected by override")
+	}
+
+	// Abaixo do critical do override continua valendo
+	if err := tm.UpdateCPUWarning(87); err != nil {
+		t.Fatalf("UpdateCPUWarning(87) error = %v", err)
+	}
+	if got := tm.For("prod", "batch-etl", "loader"); got.CPUWarningPercent != 87 || got.CPUCriticalPercent != 88 {
+		t.Errorf("Expected batch 87/88, got %d/%d", got.CPUWarningPercent, got.CPUCriticalPercent)
+	}
+}
+
+func TestThresholdManagerUpdateAllWithOverrides(t *testing.T) {
+	tm := NewThresholdManager(DefaultThresholds())
+	batch := []models.ThresholdOverride{{Namespace: "batch-*", Thresholds: map[string]string{"cpu_warning_percent": "88"}}}
+	if err := tm.SetOverrides(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	// Override válido sobre os globais atuais, inválido sobre os novos (critical 87 < 88): nada muda
+	updated := DefaultThresholds()
+	updated.CPUWarningPercent = 80
+	updated.CPUCriticalPercent = 87
+	err := tm.UpdateAllWithOverrides(updated, batch)
+	if err == nil || !strings.Contains(err.Error(), "cpu_critical_percent must be > cpu_warning_percent") {
+		t.Fatalf("Expected overrides validated against new globals, got %v", err)
+	}
+	if got := tm.Get(); got.CPUWarningPercent != 85 || got.CPUCriticalPercent != 90 {
+		t.Errorf("Expected globals unchanged after rejected update, got %d/%d", got.CPUWarningPercent, got.CPUCriticalPercent)
+	}
+
+	// Globais inválidos também não trocam os overrides
+	invalid := DefaultThresholds()
+	invalid.ScalingStuckMinutes = 0
+	if err := tm.UpdateAllWithOverrides(invalid, nil); err == nil {
+		t.Fatal("Expected invalid globals rejected")
+	}
+	if len(tm.Overrides()) != 1 {
+		t.Error("Expected overrides unchanged after rejected update")
+	}
+
+	// Válidos juntos: troca os dois
+	if err := tm.UpdateAllWithOverrides(updated, []models.ThresholdOverride{{Namespace: "batch-*", Thresholds: map[string]string{"cpu_warning_percent": "75"}}}); err != nil {
+		t.Fatalf("UpdateAllWithOverrides() error = %v", err)
+	}
+	if got := tm.For("prod", "batch-etl", "loader"); got.CPUWarningPercent != 75 || got.CPUCriticalPercent != 87 {
+		t.Errorf("Expected batch 75/87, got %d/%d", got.CPUWarningPercent, got.CPUCriticalPercent)
+	}
+	if got := tm.For("prod", "api", "checkout").CPUWarningPercent; got != 80 {
+		t.Errorf("Expected global 80 outside batch, got %d", got)
+	}
+}
+
+func TestLoadThresholdOverrides(t *testing.T) {
+	original, err := os.ReadFile("../../configs/watchdog.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "watchdog.yaml")
+	content := strings.Replace(string(original), "threshold_overrides: []", `threshold_overrides:
+  - namespace: "batch-*"
+    thresholds:
+      cpu_warning_percent: 95
+      cpu_critical_percent: 98
+  - cluster: prod
+    hpa: checkout
+    thresholds:
+      target_deviation_percent: 15.5
+      target_clear_deviation_percent: 10
+      alert_on_config_change: false`, 1)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.ThresholdOverrides) != 2 || cfg.ThresholdOverrides[1].HPA != "checkout" {
+		t.Fatalf("Expected 2 overrides, got %+v", cfg.ThresholdOverrides)
+	}
+
+	resolved, _ := ResolveThresholds(cfg.Thresholds, cfg.ThresholdOverrides, "prod", "shop", "checkout")
+	if resolved.TargetDeviationPercent != 15.5 || resolved.AlertOnConfigChange {
+		t.Errorf("Expected HPA override values, got %+v", resolved)
+	}
+
+	// Override inválido invalida a config inteira
+	invalid := strings.Replace(content, "\n      cpu_critical_percent: 98", "\n      cpu_critical_percent: 94", 1)
+	if err := os.WriteFile(path, []byte(invalid), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "threshold_overrides[0] (namespace=batch-*)") {
+		t.Errorf("Expected threshold_overrides error, got %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package config
+
+import "os"
+
+// preserveOwner sem uid/gid fora de unix: nada a preservar
+func preserveOwner(tmp *os.File, original os.FileInfo) error {
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package config
+
+import (
+	"os"
+	"syscall"
+)
+
+// preserveOwner aplica ao temporário o dono (uid/gid) do arquivo original
+func preserveOwner(tmp *os.File, original os.FileInfo) error {
+	stat, ok := original.Sys().(*syscall.Stat_t)
+	if !ok {
+		return nil
+	}
+
+	current, err := tmp.Stat()
+	if err != nil {
+		return err
+	}
+	if cur, ok := current.Sys().(*syscall.Stat_t); ok && cur.Uid == stat.Uid && cur.Gid == stat.Gid {
+		return nil
+	}
+
+	return tmp.Chown(int(stat.Uid), int(stat.Gid))
+}
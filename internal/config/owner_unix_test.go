@@ -0,0 +1,40 @@
+//go:build unix
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestWriteAtomicPreservesOwner(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("Changing file ownership requires root")
+	}
+
+	path := filepath.Join(t.TempDir(), "watchdog.yaml")
+	if err := os.WriteFile(path, []byte("thresholds:\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chown(path, 1234, 5678); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeAtomic(path, []byte("thresholds:\n  cpu_warning_percent: 80\n")); err != nil {
+		t.Fatalf("writeAtomic failed: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat := info.Sys().(*syscall.Stat_t)
+	if stat.Uid != 1234 || stat.Gid != 5678 {
+		t.Errorf("Expected owner 1234:5678 preserved, got %d:%d", stat.Uid, stat.Gid)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected permissions preserved, got %v", info.Mode().Perm())
+	}
+}
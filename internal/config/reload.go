@@ -0,0 +1,206 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/fsnotify/fsnotify"
+	"github.com/rs/zerolog/log"
+)
+
+// reloadDebounce agrupa os vários eventos que um editor gera ao salvar (write, chmod, rename)
+const reloadDebounce = 500 * time.Millisecond
+
+// reloadableFields campos aplicados sem reiniciar; mudanças nos demais só valem após restart
+var reloadableFields = map[string]bool{
+	"ScanIntervalSeconds":   true,
+	"ExcludeClusters":       true,
+	"PrometheusEndpoints":   true,
+	"AlertmanagerEndpoints": true,
+	"Thresholds":            true,
+	"ThresholdOverrides":    true,
+}
+
+// ApplyFunc aplica uma configuração já validada. Deve preparar tudo o que pode falhar antes de
+// alterar o estado em uso: um erro rejeita o reload e a configuração anterior continua valendo.
+type ApplyFunc func(old, updated *models.WatchdogConfig) error
+
+// Reloader recarrega a configuração quando o arquivo muda ou o processo recebe SIGHUP.
+// Edições inválidas são rejeitadas (com log) e a configuração anterior é mantida.
+type Reloader struct {
+	path     string
+	apply    ApplyFunc
+	current  *models.WatchdogConfig
+	debounce time.Duration
+	mu       sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewReloader cria um reloader para o arquivo, partindo da configuração carregada no startup
+func NewReloader(configPath string, current *models.WatchdogConfig, apply ApplyFunc) (*Reloader, error) {
+	path, err := ExpandPath(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config path: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Reloader{
+		path:     path,
+		apply:    apply,
+		current:  current,
+		debounce: reloadDebounce,
+		ctx:      ctx,
+		cancel:   cancel,
+	}, nil
+}
+
+// Start observa o diretório do arquivo (editores costumam salvar via rename) e o SIGHUP
+func (r *Reloader) Start() error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	if err := watcher.Add(filepath.Dir(r.path)); err != nil {
+		watcher.Close()
+		return fmt.Errorf("failed to watch config directory: %w", err)
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	r.wg.Add(1)
+	go r.loop(watcher, hup)
+
+	log.Info().Str("config", r.path).Msg("Watching configuration for changes (file and SIGHUP)")
+	return nil
+}
+
+// Stop encerra a observação e aguarda um reload em andamento
+func (r *Reloader) Stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Current retorna a configuração em uso (após o último reload aceito)
+func (r *Reloader) Current() *models.WatchdogConfig {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.current
+}
+
+// loop dispara um reload por SIGHUP ou após o arquivo ficar estável por debounce
+func (r *Reloader) loop(watcher *fsnotify.Watcher, hup chan os.Signal) {
+	defer r.wg.Done()
+	defer watcher.Close()
+	defer signal.Stop(hup)
+
+	var pending <-chan time.Time
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) != r.path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			pending = time.After(r.debounce)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.Warn().Err(err).Msg("Config file watcher error")
+
+		case <-pending:
+			pending = nil
+			r.Reload("file changed")
+
+		case <-hup:
+			r.Reload("SIGHUP")
+		}
+	}
+}
+
+// Reload relê e valida o arquivo e aplica os campos recarregáveis de uma vez.
+// Retorna erro (e mantém a configuração anterior) se o arquivo é inválido ou a aplicação falhou.
+func (r *Reloader) Reload(reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated, err := Load(r.path)
+	if err == nil {
+		if err = validateThresholds(&updated.Thresholds); err != nil {
+			err = fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+	if err != nil {
+		log.Error().Err(err).Str("reason", reason).Msg("Config reload rejected, keeping previous configuration")
+		return err
+	}
+
+	// Campos que exigem restart continuam com o valor em uso
+	restart := diffFields(r.current, updated, false)
+	for _, name := range restart {
+		reflect.ValueOf(updated).Elem().FieldByName(name).Set(reflect.ValueOf(r.current).Elem().FieldByName(name))
+	}
+	if len(restart) > 0 {
+		log.Warn().Strs("fields", restart).Msg("Configuration changes require a restart to take effect")
+	}
+
+	changed := diffFields(r.current, updated, true)
+	if len(changed) == 0 {
+		log.Debug().Str("reason", reason).Msg("Config reload: no reloadable changes")
+		return nil
+	}
+
+	if err := r.apply(r.current, updated); err != nil {
+		log.Error().Err(err).Str("reason", reason).Msg("Config reload rejected, keeping previous configuration")
+		return fmt.Errorf("failed to apply configuration: %w", err)
+	}
+
+	r.current = updated
+
+	log.Info().Str("reason", reason).Strs("changed", changed).Msg("Configuration reloaded")
+	return nil
+}
+
+// diffFields nomes dos campos (recarregáveis ou não) com valores diferentes entre as configs
+func diffFields(a, b *models.WatchdogConfig, reloadable bool) []string {
+	av := reflect.ValueOf(a).Elem()
+	bv := reflect.ValueOf(b).Elem()
+
+	var changed []string
+	for i := 0; i < av.NumField(); i++ {
+		field := av.Type().Field(i)
+		if field.Anonymous || !field.IsExported() || reloadableFields[field.Name] != reloadable {
+			continue
+		}
+		if !reflect.DeepEqual(av.Field(i).Interface(), bv.Field(i).Interface()) {
+			changed = append(changed, field.Name)
+		}
+	}
+	return changed
+}
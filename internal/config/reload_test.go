@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// editConfig aplica as substituições na cópia do watchdog.yaml
+func editConfig(t *testing.T, path string, replacements ...string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := strings.NewReplacer(replacements...).Replace(string(data))
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloader(t *testing.T) {
+	original, err := os.ReadFile("../../configs/watchdog.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "watchdog.yaml")
+	if err := os.WriteFile(path, original, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var applied []*models.WatchdogConfig
+	var applyErr error
+	reloader, err := NewReloader(path, cfg, func(old, updated *models.WatchdogConfig) error {
+		if applyErr != nil {
+			return applyErr
+		}
+		applied = append(applied, updated)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Sem mudanças recarregáveis: nada aplicado
+	if err := reloader.Reload("test"); err != nil || len(applied) != 0 {
+		t.Fatalf("Expected no-op reload, got %v (%d applied)", err, len(applied))
+	}
+
+	// Campos recarregáveis aplicados; ui.theme exige restart e mantém o valor em uso
+	editConfig(t, path,
+		"scan_interval_seconds: 30", "scan_interval_seconds: 10",
+		"cpu_warning_percent: 85", "cpu_warning_percent: 80",
+		"    - minikube\n", "    - minikube\n    - staging\n",
+		`theme: "dark"`, `theme: "light"`,
+	)
+	if err := reloader.Reload("test"); err != nil {
+		t.Fatalf("Unexpected reload error: %v", err)
+	}
+	current := reloader.Current()
+	if len(applied) != 1 || current != applied[0] {
+		t.Fatalf("Expected one applied config, got %d", len(applied))
+	}
+	if current.ScanIntervalSeconds != 10 || current.Thresholds.CPUWarningPercent != 80 || strings.Join(current.ExcludeClusters, ",") != "kind-local,minikube,staging" {
+		t.Errorf("Expected reloadable fields updated, got %+v", current)
+	}
+	if current.Theme != "dark" {
+		t.Errorf("Expected theme kept until restart, got %q", current.Theme)
+	}
+
+	// Edição inválida: rejeitada e a config anterior continua em uso
+	editConfig(t, path, "cpu_warning_percent: 80", "cpu_warning_percent: 95")
+	if err := reloader.Reload("test"); err == nil || !strings.Contains(err.Error(), "cpu_critical_percent must be > cpu_warning_percent") {
+		t.Errorf("Expected validation error, got %v", err)
+	}
+	editConfig(t, path, "cpu_warning_percent: 95", "cpu_warning_percent: 70", "replica_delta_absolute: 5", "replica_delta_absolute: -1")
+	if err := reloader.Reload("test"); err == nil || !strings.Contains(err.Error(), "replica_delta_absolute") {
+		t.Errorf("Expected threshold validation error, got %v", err)
+	}
+	if len(applied) != 1 || reloader.Current() != current {
+		t.Error("Expected invalid edits rejected and previous config kept")
+	}
+
+	// Falha ao aplicar também mantém a anterior
+	editConfig(t, path, "replica_delta_absolute: -1", "replica_delta_absolute: 5")
+	applyErr = errors.New("endpoint inválido")
+	if err := reloader.Reload("test"); err == nil || reloader.Current() != current {
+		t.Errorf("Expected apply error to keep previous config, got %v", err)
+	}
+	applyErr = nil
+
+	// Mudança no arquivo dispara o reload sozinha
+	reloader.debounce = 10 * time.Millisecond
+	if err := reloader.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer reloader.Stop()
+
+	editConfig(t, path, "scan_interval_seconds: 10", "scan_interval_seconds: 15")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for reloader.Current().ScanIntervalSeconds != 15 {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected file change to trigger reload")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := reloader.Current().Thresholds.CPUWarningPercent; got != 70 {
+		t.Errorf("Expected cpu_warning_percent 70 after file reload, got %d", got)
+	}
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
 	"github.com/rs/zerolog/log"
@@ -10,6 +11,31 @@ import (
 // ThresholdManager gerencia thresholds de forma thread-safe
 type ThresholdManager struct {
 	thresholds *models.Thresholds
+	overrides  []models.ThresholdOverride // Camadas cluster → namespace → HPA (threshold_overrides)
+	mu         sync.RWMutex
+}
+
+// DefaultThresholds retorna os thresholds padrão (mesmos valores de configs/watchdog.yaml)
+func DefaultThresholds() models.Thresholds {
+	return models.Thresholds{
+		ReplicaDeltaPercent:         50.0,
+		ReplicaDeltaAbsolute:        5,
+		ReplicaDeltaWindowMinutes:   5,
+		OscillationMaxChanges:       3,
+		CPUWarningPercent:           85,
+		CPUCriticalPercent:          90,
+		MemoryWarningPercent:        85,
+		MemoryCriticalPercent:       90,
+		TargetDeviationPercent:      30.0,
+		TargetClearDeviationPercent: 20.0,
+		TargetMissMinutes:           3,
+		ScalingStuckMinutes:         10,
+		AlertOnConfigChange:         true,
+		AlertOnResourceChange:       true,
+		RequestRateSpikePercent:     100.0,
+		ErrorRateCriticalPercent:    5.0,
+		P95LatencyCriticalMs:        1000,
+	}
 }
 
 // NewThresholdManager cria um novo manager
@@ -21,27 +47,91 @@ func NewThresholdManager(thresholds models.Thresholds) *ThresholdManager {
 
 // Get retorna uma cópia dos thresholds atuais (thread-safe)
 func (tm *ThresholdManager) Get() models.Thresholds {
-	tm.thresholds.RLock()
-	defer tm.thresholds.RUnlock()
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
 
 	// Retorna cópia para evitar race conditions
 	return *tm.thresholds
 }
 
+// For retorna os thresholds efetivos de um HPA: globais + overrides que casam com ele
+func (tm *ThresholdManager) For(cluster, namespace, name string) models.Thresholds {
+	tm.mu.RLock()
+	global := *tm.thresholds
+	overrides := tm.overrides
+	tm.mu.RUnlock()
+
+	if len(overrides) == 0 {
+		return global
+	}
+
+	resolved, _ := ResolveThresholds(global, overrides, cluster, namespace, name)
+	return resolved
+}
+
+// Explain retorna cada threshold efetivo do HPA e a camada (global, cluster, namespace, hpa) que o definiu
+func (tm *ThresholdManager) Explain(cluster, namespace, name string) []ThresholdSource {
+	tm.mu.RLock()
+	global := *tm.thresholds
+	overrides := tm.overrides
+	tm.mu.RUnlock()
+
+	_, sources := ResolveThresholds(global, overrides, cluster, namespace, name)
+	return sources
+}
+
+// Overrides retorna os overrides por escopo em uso
+func (tm *ThresholdManager) Overrides() []models.ThresholdOverride {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	return tm.overrides
+}
+
+// SetOverrides valida e troca os overrides por escopo (threshold_overrides)
+func (tm *ThresholdManager) SetOverrides(overrides []models.ThresholdOverride) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	// Valida sob o lock: os globais não mudam entre a validação e a troca
+	if err := validateOverrides(*tm.thresholds, overrides); err != nil {
+		return err
+	}
+
+	tm.overrides = overrides
+	log.Info().Int("overrides", len(overrides)).Msg("Threshold overrides updated")
+	return nil
+}
+
+// setGlobalLocked troca os globais se os overrides continuam válidos sobre eles (tm.mu já travado):
+// um global editado não pode deixar um override inválido (ex: warning global acima do critical do override)
+func (tm *ThresholdManager) setGlobalLocked(candidate models.Thresholds) error {
+	if err := validateOverrides(candidate, tm.overrides); err != nil {
+		return err
+	}
+
+	*tm.thresholds = candidate
+	return nil
+}
+
 // UpdateCPUWarning atualiza CPU warning threshold
 func (tm *ThresholdManager) UpdateCPUWarning(value int32) error {
 	if value < 1 || value > 100 {
 		return fmt.Errorf("cpu_warning_percent must be between 1 and 100")
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
 	if value >= tm.thresholds.CPUCriticalPercent {
 		return fmt.Errorf("cpu_warning_percent must be < cpu_critical_percent (%d)", tm.thresholds.CPUCriticalPercent)
 	}
 
-	tm.thresholds.CPUWarningPercent = value
+	candidate := *tm.thresholds
+	candidate.CPUWarningPercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
 	log.Info().Int32("value", value).Msg("CPU warning threshold updated")
 	return nil
 }
@@ -52,14 +142,18 @@ func (tm *ThresholdManager) UpdateCPUCritical(value int32) error {
 		return fmt.Errorf("cpu_critical_percent must be between 1 and 100")
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
 	if value <= tm.thresholds.CPUWarningPercent {
 		return fmt.Errorf("cpu_critical_percent must be > cpu_warning_percent (%d)", tm.thresholds.CPUWarningPercent)
 	}
 
-	tm.thresholds.CPUCriticalPercent = value
+	candidate := *tm.thresholds
+	candidate.CPUCriticalPercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
 	log.Info().Int32("value", value).Msg("CPU critical threshold updated")
 	return nil
 }
@@ -70,14 +164,18 @@ func (tm *ThresholdManager) UpdateMemoryWarning(value int32) error {
 		return fmt.Errorf("memory_warning_percent must be between 1 and 100")
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
 	if value >= tm.thresholds.MemoryCriticalPercent {
 		return fmt.Errorf("memory_warning_percent must be < memory_critical_percent (%d)", tm.thresholds.MemoryCriticalPercent)
 	}
 
-	tm.thresholds.MemoryWarningPercent = value
+	candidate := *tm.thresholds
+	candidate.MemoryWarningPercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
 	log.Info().Int32("value", value).Msg("Memory warning threshold updated")
 	return nil
 }
@@ -88,14 +186,18 @@ func (tm *ThresholdManager) UpdateMemoryCritical(value int32) error {
 		return fmt.Errorf("memory_critical_percent must be between 1 and 100")
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
 	if value <= tm.thresholds.MemoryWarningPercent {
 		return fmt.Errorf("memory_critical_percent must be > memory_warning_percent (%d)", tm.thresholds.MemoryWarningPercent)
 	}
 
-	tm.thresholds.MemoryCriticalPercent = value
+	candidate := *tm.thresholds
+	candidate.MemoryCriticalPercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
 	log.Info().Int32("value", value).Msg("Memory critical threshold updated")
 	return nil
 }
@@ -106,10 +208,14 @@ func (tm *ThresholdManager) UpdateReplicaDeltaPercent(value float64) error {
 		return fmt.Errorf("replica_delta_percent must be >= 0")
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
-	tm.thresholds.ReplicaDeltaPercent = value
+	candidate := *tm.thresholds
+	candidate.ReplicaDeltaPercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
 	log.Info().Float64("value", value).Msg("Replica delta percent updated")
 	return nil
 }
@@ -120,46 +226,192 @@ func (tm *ThresholdManager) UpdateReplicaDeltaAbsolute(value int32) error {
 		return fmt.Errorf("replica_delta_absolute must be >= 0")
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
-	tm.thresholds.ReplicaDeltaAbsolute = value
+	candidate := *tm.thresholds
+	candidate.ReplicaDeltaAbsolute = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
 	log.Info().Int32("value", value).Msg("Replica delta absolute updated")
 	return nil
 }
 
+// UpdateReplicaDeltaWindow atualiza a janela (minutos) em que a variação de réplicas é medida
+func (tm *ThresholdManager) UpdateReplicaDeltaWindow(minutes int) error {
+	if minutes < 0 {
+		return fmt.Errorf("replica_delta_window_minutes must be >= 0")
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	candidate := *tm.thresholds
+	candidate.ReplicaDeltaWindowMinutes = minutes
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
+	log.Info().Int("minutes", minutes).Msg("Replica delta window updated")
+	return nil
+}
+
+// UpdateOscillationMaxChanges atualiza o máximo de mudanças de réplicas antes de alertar oscilação
+func (tm *ThresholdManager) UpdateOscillationMaxChanges(value int) error {
+	if value < 0 {
+		return fmt.Errorf("oscillation_max_changes must be >= 0")
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	candidate := *tm.thresholds
+	candidate.OscillationMaxChanges = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
+	log.Info().Int("value", value).Msg("Oscillation max changes updated")
+	return nil
+}
+
 // UpdateTargetDeviation atualiza target deviation percent
 func (tm *ThresholdManager) UpdateTargetDeviation(value float64) error {
 	if value < 0 {
 		return fmt.Errorf("target_deviation_percent must be >= 0")
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
-	tm.thresholds.TargetDeviationPercent = value
+	if value < tm.thresholds.TargetClearDeviationPercent {
+		return fmt.Errorf("target_deviation_percent must be >= target_clear_deviation_percent (%.0f)", tm.thresholds.TargetClearDeviationPercent)
+	}
+
+	candidate := *tm.thresholds
+	candidate.TargetDeviationPercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
 	log.Info().Float64("value", value).Msg("Target deviation updated")
 	return nil
 }
 
+// UpdateTargetClearDeviation atualiza o desvio abaixo do qual um TargetMiss ativo é resolvido
+func (tm *ThresholdManager) UpdateTargetClearDeviation(value float64) error {
+	if value < 0 {
+		return fmt.Errorf("target_clear_deviation_percent must be >= 0")
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if value > tm.thresholds.TargetDeviationPercent {
+		return fmt.Errorf("target_clear_deviation_percent must be <= target_deviation_percent")
+	}
+
+	candidate := *tm.thresholds
+	candidate.TargetClearDeviationPercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
+	log.Info().Float64("value", value).Msg("Target clear deviation updated")
+	return nil
+}
+
+// UpdateTargetMissMinutes atualiza por quanto tempo a utilização precisa ficar fora do target
+func (tm *ThresholdManager) UpdateTargetMissMinutes(value int) error {
+	if value < 0 {
+		return fmt.Errorf("target_miss_minutes must be >= 0")
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	candidate := *tm.thresholds
+	candidate.TargetMissMinutes = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
+	log.Info().Int("value", value).Msg("Target miss minutes updated")
+	return nil
+}
+
 // UpdateScalingStuckMinutes atualiza scaling stuck minutes
 func (tm *ThresholdManager) UpdateScalingStuckMinutes(value int) error {
 	if value < 1 {
 		return fmt.Errorf("scaling_stuck_minutes must be >= 1")
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
-	tm.thresholds.ScalingStuckMinutes = value
+	candidate := *tm.thresholds
+	candidate.ScalingStuckMinutes = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
 	log.Info().Int("value", value).Msg("Scaling stuck minutes updated")
 	return nil
 }
 
+// UpdateRequestRateSpike atualiza o aumento de request rate (%) que gera alerta
+func (tm *ThresholdManager) UpdateRequestRateSpike(value float64) error {
+	if value < 0 {
+		return fmt.Errorf("request_rate_spike_percent must be >= 0")
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	candidate := *tm.thresholds
+	candidate.RequestRateSpikePercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
+	log.Info().Float64("value", value).Msg("Request rate spike threshold updated")
+	return nil
+}
+
+// UpdateErrorRateCritical atualiza a taxa de erros (%) considerada crítica
+func (tm *ThresholdManager) UpdateErrorRateCritical(value float64) error {
+	if value < 0 || value > 100 {
+		return fmt.Errorf("error_rate_critical_percent must be between 0 and 100")
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	candidate := *tm.thresholds
+	candidate.ErrorRateCriticalPercent = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
+	log.Info().Float64("value", value).Msg("Error rate critical threshold updated")
+	return nil
+}
+
+// UpdateP95LatencyCritical atualiza a latência P95 (ms) considerada crítica
+func (tm *ThresholdManager) UpdateP95LatencyCritical(value float64) error {
+	if value < 0 {
+		return fmt.Errorf("p95_latency_critical_ms must be >= 0")
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	candidate := *tm.thresholds
+	candidate.P95LatencyCriticalMs = value
+	if err := tm.setGlobalLocked(candidate); err != nil {
+		return err
+	}
+	log.Info().Float64("value", value).Msg("P95 latency critical threshold updated")
+	return nil
+}
+
 // ToggleConfigChangeAlert toggle alert on config change
 func (tm *ThresholdManager) ToggleConfigChangeAlert(enabled bool) {
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
 	tm.thresholds.AlertOnConfigChange = enabled
 	log.Info().Bool("enabled", enabled).Msg("Alert on config change toggled")
@@ -167,8 +419,8 @@ func (tm *ThresholdManager) ToggleConfigChangeAlert(enabled bool) {
 
 // ToggleResourceChangeAlert toggle alert on resource change
 func (tm *ThresholdManager) ToggleResourceChangeAlert(enabled bool) {
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
 	tm.thresholds.AlertOnResourceChange = enabled
 	log.Info().Bool("enabled", enabled).Msg("Alert on resource change toggled")
@@ -181,14 +433,35 @@ func (tm *ThresholdManager) UpdateAll(newThresholds models.Thresholds) error {
 		return err
 	}
 
-	tm.thresholds.Lock()
-	defer tm.thresholds.Unlock()
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
 
-	*tm.thresholds = newThresholds
+	if err := tm.setGlobalLocked(newThresholds); err != nil {
+		return err
+	}
 	log.Info().Msg("All thresholds updated")
 	return nil
 }
 
+// UpdateAllWithOverrides valida thresholds e overrides juntos e troca os dois sob o mesmo lock
+// (reload da config): se qualquer um for inválido, nada muda
+func (tm *ThresholdManager) UpdateAllWithOverrides(newThresholds models.Thresholds, overrides []models.ThresholdOverride) error {
+	if err := validateThresholds(&newThresholds); err != nil {
+		return err
+	}
+	if err := validateOverrides(newThresholds, overrides); err != nil {
+		return err
+	}
+
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	*tm.thresholds = newThresholds
+	tm.overrides = overrides
+	log.Info().Int("overrides", len(overrides)).Msg("All thresholds and overrides updated")
+	return nil
+}
+
 // validateThresholds valida thresholds
 func validateThresholds(t *models.Thresholds) error {
 	if t.CPUWarningPercent < 1 || t.CPUWarningPercent > 100 {
@@ -223,10 +496,38 @@ func validateThresholds(t *models.Thresholds) error {
 		return fmt.Errorf("replica_delta_absolute must be >= 0")
 	}
 
+	if t.ReplicaDeltaWindowMinutes < 0 {
+		return fmt.Errorf("replica_delta_window_minutes must be >= 0")
+	}
+
+	if t.OscillationMaxChanges < 0 {
+		return fmt.Errorf("oscillation_max_changes must be >= 0")
+	}
+
+	if t.TargetClearDeviationPercent < 0 || t.TargetClearDeviationPercent > t.TargetDeviationPercent {
+		return fmt.Errorf("target_clear_deviation_percent must be between 0 and target_deviation_percent")
+	}
+
+	if t.TargetMissMinutes < 0 {
+		return fmt.Errorf("target_miss_minutes must be >= 0")
+	}
+
 	if t.ScalingStuckMinutes < 1 {
 		return fmt.Errorf("scaling_stuck_minutes must be >= 1")
 	}
 
+	if t.RequestRateSpikePercent < 0 {
+		return fmt.Errorf("request_rate_spike_percent must be >= 0")
+	}
+
+	if t.ErrorRateCriticalPercent < 0 || t.ErrorRateCriticalPercent > 100 {
+		return fmt.Errorf("error_rate_critical_percent must be between 0 and 100")
+	}
+
+	if t.P95LatencyCriticalMs < 0 {
+		return fmt.Errorf("p95_latency_critical_ms must be >= 0")
+	}
+
 	return nil
 }
 
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// sectionPattern início de uma seção de primeiro nível (ex: "thresholds:  # comentário")
+	sectionPattern = regexp.MustCompile(`^([A-Za-z0-9_]+):\s*(#.*)?$`)
+
+	// keyPattern "  chave: valor   # comentário" (indentação, chave, espaços, valor, espaços antes do comentário, resto)
+	keyPattern = regexp.MustCompile(`^(\s+)([A-Za-z0-9_]+):(\s*)([^\s#]*)(\s*)(#.*)?$`)
+)
+
+// WriteThresholds grava os thresholds na seção thresholds: do arquivo de configuração
+// alterando só os valores que mudaram: comentários, ordem e alinhamento são preservados.
+// Chaves ausentes são adicionadas no fim da seção (ou a seção no fim do arquivo).
+// A escrita é atômica (arquivo temporário + rename no destino real do symlink, com dono e permissões preservados).
+func WriteThresholds(configPath string, t models.Thresholds) error {
+	path, err := ExpandPath(configPath)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	updated := updateThresholds(string(content), t)
+	if updated == string(content) {
+		return nil
+	}
+
+	if err := writeAtomic(path, []byte(updated)); err != nil {
+		return err
+	}
+
+	log.Info().Str("config", path).Msg("Thresholds written to configuration file")
+	return nil
+}
+
+// updateThresholds aplica os valores de t às linhas da seção thresholds:
+func updateThresholds(content string, t models.Thresholds) string {
+	values := make(map[string]string)
+	var order []string
+	for _, field := range ThresholdFields() {
+		values[field.Key] = field.Value(t)
+		order = append(order, field.Key)
+	}
+
+	lines := strings.Split(content, "\n")
+
+	// Limites da seção: do "thresholds:" até a próxima seção de primeiro nível
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		if start < 0 {
+			if m := sectionPattern.FindStringSubmatch(line); m != nil && m[1] == "thresholds" {
+				start = i
+			}
+			continue
+		}
+		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "\t") && !strings.HasPrefix(line, "#") {
+			end = i
+			break
+		}
+	}
+
+	if start < 0 {
+		section := []string{"thresholds:"}
+		for _, key := range order {
+			section = append(section, "  "+key+": "+values[key])
+		}
+		if !strings.HasSuffix(content, "\n") && content != "" {
+			content += "\n"
+		}
+		return content + "\n" + strings.Join(section, "\n") + "\n"
+	}
+
+	// Só chaves diretas da seção (indentação da primeira chave); subchaves são ignoradas
+	indent, last := "", start
+	written := make(map[string]bool)
+	for i := start + 1; i < end; i++ {
+		if trimmed := strings.TrimSpace(lines[i]); trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			last = i // Chaves novas entram depois do último conteúdo da seção
+		}
+
+		m := keyPattern.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		if indent == "" {
+			indent = m[1]
+		}
+		if m[1] != indent {
+			continue
+		}
+
+		key, old := m[2], m[4]
+		value, known := values[key]
+		if !known {
+			continue
+		}
+		written[key] = true
+		if sameValue(old, value) {
+			continue
+		}
+
+		// Mantém a coluna do comentário quando possível
+		padding := m[5]
+		if m[6] != "" {
+			width := len(padding) - (len(value) - len(old))
+			if width < 1 {
+				width = 1
+			}
+			padding = strings.Repeat(" ", width)
+		}
+		lines[i] = m[1] + key + ":" + m[3] + value + padding + m[6]
+	}
+
+	if indent == "" {
+		indent = "  "
+	}
+	var missing []string
+	for _, key := range order {
+		if !written[key] {
+			missing = append(missing, indent+key+": "+values[key])
+		}
+	}
+	if len(missing) > 0 {
+		lines = append(lines[:last+1], append(missing, lines[last+1:]...)...)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// sameValue compara o valor do arquivo com o novo (50.0 == 50, "true" == true)
+func sameValue(old, value string) bool {
+	old = strings.Trim(old, `"'`)
+	if old == value {
+		return true
+	}
+	if a, err := strconv.ParseFloat(old, 64); err == nil {
+		if b, err := strconv.ParseFloat(value, 64); err == nil {
+			return a == b
+		}
+	}
+	if a, err := strconv.ParseBool(old); err == nil {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return a == b
+		}
+	}
+	return false
+}
+
+// writeAtomic grava em um temporário no mesmo diretório e renomeia por cima do arquivo.
+// Symlinks são resolvidos (o link continua apontando para o arquivo real) e permissões e
+// dono do arquivo original são preservados.
+func writeAtomic(path string, data []byte) error {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve config file: %w", err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return fmt.Errorf("failed to stat config file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to set permissions: %w", err)
+	}
+	if err := preserveOwner(tmp, info); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to preserve ownership: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write temp file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+func TestThresholdFields(t *testing.T) {
+	tm := NewThresholdManager(DefaultThresholds())
+
+	fields := make(map[string]ThresholdField)
+	for _, field := range ThresholdFields() {
+		fields[field.Key] = field
+	}
+	if len(fields) != 17 {
+		t.Errorf("Expected 17 editable thresholds, got %d", len(fields))
+	}
+
+	tests := []struct {
+		key     string
+		value   string
+		wantErr string
+	}{
+		{"cpu_warning_percent", "80", ""},
+		{"cpu_warning_percent", "95", "must be < cpu_critical_percent"},
+		{"cpu_warning_percent", "abc", "must be an integer"},
+		{"target_deviation_percent", " 35.5 ", ""},
+		{"target_clear_deviation_percent", "40", "must be <= target_deviation_percent"},
+		{"scaling_stuck_minutes", "0", "must be >= 1"},
+		{"alert_on_config_change", "false", ""},
+		{"alert_on_config_change", "talvez", "must be true or false"},
+		{"p95_latency_critical_ms", "750", ""},
+	}
+
+	for _, tt := range tests {
+		err := fields[tt.key].Set(tm, tt.value)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("Set(%s, %q) unexpected error: %v", tt.key, tt.value, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Set(%s, %q) = %v, want error containing %q", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+
+	got := tm.Get()
+	if got.CPUWarningPercent != 80 || got.TargetDeviationPercent != 35.5 || got.AlertOnConfigChange || got.P95LatencyCriticalMs != 750 {
+		t.Errorf("Expected valid values applied, got %+v", got)
+	}
+	if v := fields["target_deviation_percent"].Value(got); v != "35.5" {
+		t.Errorf("Expected formatted value 35.5, got %s", v)
+	}
+}
+
+func TestWriteThresholds(t *testing.T) {
+	original, err := os.ReadFile("../../configs/watchdog.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read example config: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "watchdog.yaml")
+	if err := os.WriteFile(path, original, 0600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+
+	// Sem mudanças: arquivo intacto (50.0 continua 50.0)
+	if err := WriteThresholds(path, cfg.Thresholds); err != nil {
+		t.Fatalf("WriteThresholds failed: %v", err)
+	}
+	if content, _ := os.ReadFile(path); string(content) != string(original) {
+		t.Error("Expected unchanged file when thresholds are unchanged")
+	}
+
+	thresholds := cfg.Thresholds
+	thresholds.CPUWarningPercent = 80
+	thresholds.ReplicaDeltaPercent = 62.5
+	thresholds.AlertOnResourceChange = false
+	if err := WriteThresholds(path, thresholds); err != nil {
+		t.Fatalf("WriteThresholds failed: %v", err)
+	}
+
+	content, _ := os.ReadFile(path)
+	before, after := strings.Split(string(original), "\n"), strings.Split(string(content), "\n")
+	if len(before) != len(after) {
+		t.Fatalf("Expected same number of lines, got %d -> %d", len(before), len(after))
+	}
+
+	var changed []string
+	for i := range before {
+		if before[i] != after[i] {
+			changed = append(changed, after[i])
+		}
+	}
+	want := []string{
+		"  replica_delta_percent: 62.5     # Alerta se réplicas mudam >50%",
+		"  cpu_warning_percent: 80",
+		"  alert_on_resource_change: false # Alertar mudanças em deployment resources",
+	}
+	if strings.Join(changed, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected only threshold values changed (comments aligned), got:\n%s", strings.Join(changed, "\n"))
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected permissions preserved, got %v", info.Mode().Perm())
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Failed to reload config: %v", err)
+	}
+	if reloaded.Thresholds != thresholds {
+		t.Errorf("Expected reloaded thresholds %+v, got %+v", thresholds, reloaded.Thresholds)
+	}
+}
+
+func TestUpdateThresholdsMissingKeys(t *testing.T) {
+	thresholds := DefaultThresholds()
+
+	// Seção sem algumas chaves e com subchaves: novas chaves entram no fim da seção
+	content := "thresholds:\n    cpu_warning_percent: 85 # warning\n    extra:\n        cpu_warning_percent: 70\n\nui:\n  theme: dark\n"
+	updated := updateThresholds(content, thresholds)
+
+	lines := strings.Split(updated, "\n")
+	if lines[1] != "    cpu_warning_percent: 85 # warning" || lines[3] != "        cpu_warning_percent: 70" {
+		t.Errorf("Expected existing and nested keys untouched, got:\n%s", updated)
+	}
+	if lines[4] != "    replica_delta_percent: 50" || !strings.Contains(updated, "    p95_latency_critical_ms: 1000\n\nui:\n  theme: dark\n") {
+		t.Errorf("Expected missing keys appended to the section, got:\n%s", updated)
+	}
+
+	// Sem seção: adicionada no fim do arquivo
+	updated = updateThresholds("ui:\n  theme: dark\n", models.Thresholds{CPUWarningPercent: 70})
+	if !strings.HasPrefix(updated, "ui:\n  theme: dark\n\nthresholds:\n  replica_delta_percent: 0\n") || !strings.Contains(updated, "  cpu_warning_percent: 70\n") {
+		t.Errorf("Expected thresholds section appended, got:\n%s", updated)
+	}
+}
+
+func TestWriteThresholdsSymlink(t *testing.T) {
+	original, err := os.ReadFile("../../configs/watchdog.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read example config: %v", err)
+	}
+
+	// watchdog.yaml como symlink para o arquivo target (ex: dotfiles, ConfigMap montado)
+	dir := t.TempDir()
+	target := filepath.Join(dir, "shared", "watchdog.yaml")
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, original, 0640); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "watchdog.yaml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("Symlinks not supported: %v", err)
+	}
+
+	thresholds := DefaultThresholds()
+	thresholds.CPUWarningPercent = 80
+	if err := WriteThresholds(link, thresholds); err != nil {
+		t.Fatalf("WriteThresholds failed: %v", err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("Expected symlink kept, got %v (err %v)", info, err)
+	}
+	content, _ := os.ReadFile(target)
+	if !strings.Contains(string(content), "  cpu_warning_percent: 80") {
+		t.Error("Expected thresholds written to the symlink target")
+	}
+	if info, _ := os.Stat(target); info.Mode().Perm() != 0640 {
+		t.Errorf("Expected target permissions preserved, got %v", info.Mode().Perm())
+	}
+
+	// Temporário criado ao lado do arquivo target, sem sobras
+	for _, d := range []string{dir, filepath.Dir(target)} {
+		entries, _ := os.ReadDir(d)
+		for _, entry := range entries {
+			if strings.HasPrefix(entry.Name(), ".watchdog.yaml.") {
+				t.Errorf("Unexpected temp file left in %s: %s", d, entry.Name())
+			}
+		}
+	}
+}
@@ -0,0 +1,317 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// Format formato de saída do export
+type Format string
+
+const (
+	FormatJSON   Format = "json"   // Array JSON
+	FormatCSV    Format = "csv"    // CSV com cabeçalho
+	FormatNDJSON Format = "ndjson" // Um objeto JSON por linha
+)
+
+// ParseFormat converte "json", "csv" ou "ndjson" (case-insensitive) para Format
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(s))); format {
+	case FormatJSON, FormatCSV, FormatNDJSON:
+		return format, nil
+	default:
+		return "", fmt.Errorf("invalid format %q (expected json, csv or ndjson)", s)
+	}
+}
+
+// Extension extensão de arquivo do formato
+func (f Format) Extension() string {
+	return string(f)
+}
+
+// AlertColumns colunas do export de alertas, na ordem do CSV e das chaves JSON.
+// Colunas só podem ser adicionadas ao final; nunca renomeadas ou removidas.
+var AlertColumns = []string{
+	"id", "source", "severity", "type", "cluster", "namespace", "hpa", "status",
+	"started_at", "last_seen", "resolved_at", "duration_seconds", "occurrences",
+	"alert_name", "summary", "acknowledged", "acked_by", "acked_at", "incident_id",
+}
+
+// SnapshotColumns colunas do export de snapshots, na ordem do CSV e das chaves JSON.
+// Colunas só podem ser adicionadas ao final; nunca renomeadas ou removidas.
+var SnapshotColumns = []string{
+	"timestamp", "cluster", "namespace", "hpa",
+	"min_replicas", "max_replicas", "current_replicas", "desired_replicas",
+	"cpu_target", "memory_target", "cpu_current", "memory_current",
+	"cpu_request", "cpu_limit", "memory_request", "memory_limit",
+	"request_rate", "error_rate", "p95_latency_ms", "ready", "scaling_active", "data_source",
+}
+
+// AlertRow linha do export de alertas (campos na ordem de AlertColumns)
+type AlertRow struct {
+	ID           string `json:"id"`
+	Source       string `json:"source"`
+	Severity     string `json:"severity"`
+	Type         string `json:"type"`
+	Cluster      string `json:"cluster"`
+	Namespace    string `json:"namespace"`
+	HPA          string `json:"hpa"`
+	Status       string `json:"status"`
+	StartedAt    string `json:"started_at"`
+	LastSeen     string `json:"last_seen"`
+	ResolvedAt   string `json:"resolved_at"`
+	Duration     int64  `json:"duration_seconds"`
+	Occurrences  int    `json:"occurrences"`
+	AlertName    string `json:"alert_name"`
+	Summary      string `json:"summary"`
+	Acknowledged bool   `json:"acknowledged"`
+	AckedBy      string `json:"acked_by"`
+	AckedAt      string `json:"acked_at"`
+	IncidentID   string `json:"incident_id"`
+}
+
+// SnapshotRow linha do export de snapshots (campos na ordem de SnapshotColumns)
+type SnapshotRow struct {
+	Timestamp       string  `json:"timestamp"`
+	Cluster         string  `json:"cluster"`
+	Namespace       string  `json:"namespace"`
+	HPA             string  `json:"hpa"`
+	MinReplicas     int32   `json:"min_replicas"`
+	MaxReplicas     int32   `json:"max_replicas"`
+	CurrentReplicas int32   `json:"current_replicas"`
+	DesiredReplicas int32   `json:"desired_replicas"`
+	CPUTarget       int32   `json:"cpu_target"`
+	MemoryTarget    int32   `json:"memory_target"`
+	CPUCurrent      float64 `json:"cpu_current"`
+	MemoryCurrent   float64 `json:"memory_current"`
+	CPURequest      string  `json:"cpu_request"`
+	CPULimit        string  `json:"cpu_limit"`
+	MemoryRequest   string  `json:"memory_request"`
+	MemoryLimit     string  `json:"memory_limit"`
+	RequestRate     float64 `json:"request_rate"`
+	ErrorRate       float64 `json:"error_rate"`
+	P95Latency      float64 `json:"p95_latency_ms"`
+	Ready           bool    `json:"ready"`
+	ScalingActive   bool    `json:"scaling_active"`
+	DataSource      string  `json:"data_source"`
+}
+
+// NewAlertRow converte um alerta para a linha do export
+func NewAlertRow(a *models.UnifiedAlert) AlertRow {
+	lastSeen := a.LastSeen
+	if lastSeen.IsZero() {
+		lastSeen = a.Timestamp
+	}
+
+	end := lastSeen
+	if a.ResolvedAt != nil {
+		end = *a.ResolvedAt
+	}
+
+	var duration int64
+	if !a.Timestamp.IsZero() && end.After(a.Timestamp) {
+		duration = int64(end.Sub(a.Timestamp).Seconds())
+	}
+
+	return AlertRow{
+		ID:           a.ID,
+		Source:       strings.ToLower(a.Source.String()),
+		Severity:     strings.ToLower(a.Severity.String()),
+		Type:         a.Type.String(),
+		Cluster:      a.Cluster,
+		Namespace:    a.Namespace,
+		HPA:          a.HPAName,
+		Status:       alertStatus(a),
+		StartedAt:    formatTime(a.Timestamp),
+		LastSeen:     formatTime(lastSeen),
+		ResolvedAt:   formatTimePtr(a.ResolvedAt),
+		Duration:     duration,
+		Occurrences:  a.Occurrences,
+		AlertName:    a.AlertName,
+		Summary:      a.Summary,
+		Acknowledged: a.Acknowledged,
+		AckedBy:      a.AckedBy,
+		AckedAt:      formatTimePtr(a.AckedAt),
+		IncidentID:   a.IncidentID,
+	}
+}
+
+// NewSnapshotRow converte um snapshot para a linha do export
+func NewSnapshotRow(s *models.HPASnapshot) SnapshotRow {
+	return SnapshotRow{
+		Timestamp:       formatTime(s.Timestamp),
+		Cluster:         s.Cluster,
+		Namespace:       s.Namespace,
+		HPA:             s.Name,
+		MinReplicas:     s.MinReplicas,
+		MaxReplicas:     s.MaxReplicas,
+		CurrentReplicas: s.CurrentReplicas,
+		DesiredReplicas: s.DesiredReplicas,
+		CPUTarget:       s.CPUTarget,
+		MemoryTarget:    s.MemoryTarget,
+		CPUCurrent:      s.CPUCurrent,
+		MemoryCurrent:   s.MemoryCurrent,
+		CPURequest:      s.CPURequest,
+		CPULimit:        s.CPULimit,
+		MemoryRequest:   s.MemoryRequest,
+		MemoryLimit:     s.MemoryLimit,
+		RequestRate:     s.RequestRate,
+		ErrorRate:       s.ErrorRate,
+		P95Latency:      s.P95Latency,
+		Ready:           s.Ready,
+		ScalingActive:   s.ScalingActive,
+		DataSource:      s.DataSource.String(),
+	}
+}
+
+// record valores da linha na ordem de AlertColumns
+func (r AlertRow) record() []string {
+	return []string{
+		r.ID, r.Source, r.Severity, r.Type, r.Cluster, r.Namespace, r.HPA, r.Status,
+		r.StartedAt, r.LastSeen, r.ResolvedAt, strconv.FormatInt(r.Duration, 10), strconv.Itoa(r.Occurrences),
+		r.AlertName, r.Summary, strconv.FormatBool(r.Acknowledged), r.AckedBy, r.AckedAt, r.IncidentID,
+	}
+}
+
+// record valores da linha na ordem de SnapshotColumns
+func (r SnapshotRow) record() []string {
+	return []string{
+		r.Timestamp, r.Cluster, r.Namespace, r.HPA,
+		formatInt(r.MinReplicas), formatInt(r.MaxReplicas), formatInt(r.CurrentReplicas), formatInt(r.DesiredReplicas),
+		formatInt(r.CPUTarget), formatInt(r.MemoryTarget), formatFloat(r.CPUCurrent), formatFloat(r.MemoryCurrent),
+		r.CPURequest, r.CPULimit, r.MemoryRequest, r.MemoryLimit,
+		formatFloat(r.RequestRate), formatFloat(r.ErrorRate), formatFloat(r.P95Latency),
+		strconv.FormatBool(r.Ready), strconv.FormatBool(r.ScalingActive), r.DataSource,
+	}
+}
+
+// WriteAlerts escreve os alertas em w no formato indicado
+func WriteAlerts(w io.Writer, format Format, alerts []models.UnifiedAlert) error {
+	rows := make([]interface{}, len(alerts))
+	records := make([][]string, len(alerts))
+	for i := range alerts {
+		row := NewAlertRow(&alerts[i])
+		rows[i] = row
+		records[i] = row.record()
+	}
+
+	return write(w, format, rows, AlertColumns, records)
+}
+
+// WriteSnapshots escreve os snapshots em w no formato indicado
+func WriteSnapshots(w io.Writer, format Format, snapshots []models.HPASnapshot) error {
+	rows := make([]interface{}, len(snapshots))
+	records := make([][]string, len(snapshots))
+	for i := range snapshots {
+		row := NewSnapshotRow(&snapshots[i])
+		rows[i] = row
+		records[i] = row.record()
+	}
+
+	return write(w, format, rows, SnapshotColumns, records)
+}
+
+// write serializa rows (JSON/NDJSON) ou header + records (CSV)
+func write(w io.Writer, format Format, rows []interface{}, header []string, records [][]string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(rows)
+
+	case FormatNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, row := range rows {
+			if err := encoder.Encode(row); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(records); err != nil {
+			return err
+		}
+		return writer.Error()
+
+	default:
+		return fmt.Errorf("invalid format %q", format)
+	}
+}
+
+// ParseTime interpreta --since/--until: RFC3339, "2006-01-02T15:04", "2006-01-02" (horário local)
+// ou uma duração relativa a now ("30m", "24h", "7d" = 7 dias atrás)
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	for _, layout := range []string{"2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q (expected RFC3339, YYYY-MM-DD or a duration like 24h/7d)", value)
+}
+
+// alertStatus status do alerta; alertas do watchdog não preenchem Status
+func alertStatus(a *models.UnifiedAlert) string {
+	switch {
+	case a.ResolvedAt != nil:
+		return models.AlertStatusResolved
+	case a.Status != "":
+		return a.Status
+	default:
+		return models.AlertStatusActive
+	}
+}
+
+// formatTime RFC3339 em UTC (vazio para tempo zero)
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTime(*t)
+}
+
+func formatInt(v int32) string {
+	return strconv.FormatInt(int64(v), 10)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,171 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+func testAlerts() []models.UnifiedAlert {
+	start := time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC)
+	resolvedAt := start.Add(90 * time.Second)
+
+	return []models.UnifiedAlert{
+		{
+			ID: "prod/production/api/MaxedOut", Source: models.AlertSourceWatchdog, Severity: models.SeverityCritical,
+			Type: models.AnomalyMaxedOut, Cluster: "prod", Namespace: "production", HPAName: "api",
+			Timestamp: start, LastSeen: start.Add(time.Minute), ResolvedAt: &resolvedAt, Occurrences: 2,
+			Summary: "HPA no limite, \"api\"",
+		},
+		{
+			ID: "prod/alertmanager/abc", Source: models.AlertSourceAlertmanager, Severity: models.SeverityWarning,
+			Type: models.AnomalyExternal, Cluster: "prod", AlertName: "KubeHpaMaxedOut",
+			Timestamp: start, Status: models.AlertStatusSuppressed,
+		},
+	}
+}
+
+func TestWriteAlertsCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteAlerts(&buf, FormatCSV, testAlerts()); err != nil {
+		t.Fatalf("WriteAlerts failed: %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(records) != 3 || !reflect.DeepEqual(records[0], AlertColumns) {
+		t.Fatalf("Expected header + 2 rows, got %v", records)
+	}
+
+	row := map[string]string{}
+	for i, column := range AlertColumns {
+		row[column] = records[1][i]
+	}
+
+	want := map[string]string{
+		"source":           "watchdog",
+		"severity":         "critical",
+		"type":             "MaxedOut",
+		"status":           "resolved",
+		"started_at":       "2025-01-10T22:00:00Z",
+		"resolved_at":      "2025-01-10T22:01:30Z",
+		"duration_seconds": "90",
+		"occurrences":      "2",
+		"summary":          "HPA no limite, \"api\"",
+	}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("Column %s = %q, want %q", column, row[column], value)
+		}
+	}
+
+	if records[2][7] != "suppressed" || records[2][9] != "2025-01-10T22:00:00Z" {
+		t.Errorf("Expected Alertmanager status and last_seen defaulting to start, got %v", records[2])
+	}
+}
+
+func TestWriteAlertsJSONKeys(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteAlerts(&buf, FormatNDJSON, testAlerts()); err != nil {
+		t.Fatalf("WriteAlerts failed: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 NDJSON lines, got %d", len(lines))
+	}
+
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &object); err != nil {
+		t.Fatalf("Invalid NDJSON line: %v", err)
+	}
+	if len(object) != len(AlertColumns) {
+		t.Errorf("Expected %d keys, got %d", len(AlertColumns), len(object))
+	}
+	for _, column := range AlertColumns {
+		if _, ok := object[column]; !ok {
+			t.Errorf("Missing key %s", column)
+		}
+	}
+
+	buf.Reset()
+	WriteAlerts(&buf, FormatJSON, nil)
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Expected empty JSON array, got %q", buf.String())
+	}
+}
+
+func TestWriteSnapshots(t *testing.T) {
+	snapshots := []models.HPASnapshot{{
+		Timestamp: time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC), Cluster: "prod", Namespace: "production", Name: "api",
+		MinReplicas: 2, MaxReplicas: 10, CurrentReplicas: 4, CPUCurrent: 72.5, CPURequest: "500m", Ready: true,
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteSnapshots(&buf, FormatCSV, snapshots); err != nil {
+		t.Fatalf("WriteSnapshots failed: %v", err)
+	}
+
+	records, _ := csv.NewReader(&buf).ReadAll()
+	if len(records) != 2 || len(records[1]) != len(SnapshotColumns) {
+		t.Fatalf("Expected header + 1 row with all columns, got %v", records)
+	}
+	if records[1][3] != "api" || records[1][6] != "4" || records[1][10] != "72.5" || records[1][12] != "500m" {
+		t.Errorf("Unexpected snapshot row %v", records[1])
+	}
+
+	buf.Reset()
+	WriteSnapshots(&buf, FormatJSON, snapshots)
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil || len(rows) != 1 || len(rows[0]) != len(SnapshotColumns) {
+		t.Errorf("Expected JSON array with %d keys, got %s (%v)", len(SnapshotColumns), buf.String(), err)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"json": FormatJSON, "CSV": FormatCSV, " ndjson ": FormatNDJSON} {
+		if got, err := ParseFormat(input); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected error for xml")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"", time.Time{}},
+		{"24h", now.Add(-24 * time.Hour)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2025-01-01T10:00:00Z", time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{"2025-01-01", time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)},
+		{"2025-01-01T10:30", time.Date(2025, 1, 1, 10, 30, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTime(tt.input, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseTime(%q) = %s, %v; want %s", tt.input, got, err, tt.want)
+		}
+	}
+
+	for _, input := range []string{"yesterday", "-5h", "xd"} {
+		if _, err := ParseTime(input, now); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -20,8 +22,13 @@ type HPASnapshot struct {
 	DesiredReplicas int32
 
 	// Targets
-	CPUTarget    int32 // % (ex: 70)
-	MemoryTarget int32 // % (ex: 80)
+	CPUTarget    int32    // % (ex: 70)
+	MemoryTarget int32    // % (ex: 80)
+	MetricSpecs  []string // spec.metrics normalizado (ex: "resource:cpu/Utilization=70")
+
+	// Último manager (managedFields) que alterou o spec do HPA
+	SpecManager   string     // Ex: "kubectl-edit", "argocd-controller"
+	SpecManagedAt *time.Time // Quando o manager aplicou a alteração
 
 	// Deployment Resources (K8s API)
 	CPURequest    string // Ex: "500m"
@@ -29,10 +36,13 @@ type HPASnapshot struct {
 	MemoryRequest string // Ex: "512Mi"
 	MemoryLimit   string // Ex: "1Gi"
 
+	ResourcesUnknown bool // Falha ao obter o workload alvo: requests/limits vazios não são confiáveis
+
 	// Status
 	Ready         bool
 	ScalingActive bool
 	LastScaleTime *time.Time
+	Conditions    []HPACondition // status.conditions completos (com reasons)
 
 	// === Prometheus Metrics (Real-time & Historical) ===
 	// Current Metrics (Prometheus)
@@ -77,6 +87,47 @@ func (d DataSource) String() string {
 	}
 }
 
+// Key retorna a chave única do HPA no formato "cluster/namespace/name"
+func (s *HPASnapshot) Key() string {
+	return HPAKey(s.Cluster, s.Namespace, s.Name)
+}
+
+// HPAKey monta a chave única de um HPA
+func HPAKey(cluster, namespace, name string) string {
+	return cluster + "/" + namespace + "/" + name
+}
+
+// Tipos de condição reportados pelo HPA (status.conditions)
+const (
+	ConditionAbleToScale    = "AbleToScale"
+	ConditionScalingActive  = "ScalingActive"
+	ConditionScalingLimited = "ScalingLimited"
+)
+
+// HPACondition condição reportada pelo HPA
+type HPACondition struct {
+	Type               string // AbleToScale, ScalingActive, ScalingLimited
+	Status             string // True, False, Unknown
+	Reason             string // Ex: TooManyReplicas, FailedGetResourceMetric
+	Message            string
+	LastTransitionTime time.Time
+}
+
+// IsTrue indica se a condição está com status True
+func (c *HPACondition) IsTrue() bool {
+	return c.Status == "True"
+}
+
+// Condition retorna a condição do tipo informado (nil se o HPA não reporta)
+func (s *HPASnapshot) Condition(conditionType string) *HPACondition {
+	for i := range s.Conditions {
+		if s.Conditions[i].Type == conditionType {
+			return &s.Conditions[i]
+		}
+	}
+	return nil
+}
+
 // TimeSeriesData armazena histórico de 5 minutos
 type TimeSeriesData struct {
 	HPAKey      string // "cluster/namespace/name"
@@ -143,15 +194,24 @@ type UnifiedAlert struct {
 	Description string
 
 	// Alertmanager specific
+	AlertName    string            // Label alertname
+	Labels       map[string]string // Labels completos do alerta
 	Fingerprint  string
 	GeneratorURL string
-	Status       string // "active", "suppressed"
+	Status       string // AlertStatusActive, AlertStatusSuppressed, AlertStatusResolved
 	SilencedBy   []string
 
+	// Lifecycle (AlertStore)
+	LastSeen    time.Time  // Última vez que a fonte reportou o alerta (Timestamp = primeira)
+	ResolvedAt  *time.Time // Quando a fonte parou de reportar
+	Occurrences int        // Quantas vezes disparou (duplicatas mescladas na janela de dedupe)
+
 	// Enrichment (from Prometheus + Watchdog)
 	Snapshot    *HPASnapshot  // Estado atual do HPA
 	Context     *AlertContext // Contexto adicional
 	Correlation []string      // IDs de alertas correlacionados
+	IncidentID  string        // ID do alerta raiz do incidente (vazio = sem correlação)
+	MergedFrom  []string      // IDs dos alertas de outras fontes mesclados neste (source_priority)
 
 	// Actions
 	Acknowledged bool
@@ -159,6 +219,28 @@ type UnifiedAlert struct {
 	AckedBy      string
 }
 
+// Incident grupo de alertas correlacionados (mesmo HPA, ou mesmo sintoma no namespace/cluster, dentro da janela)
+type Incident struct {
+	ID       string        // ID do alerta raiz (causa provável)
+	Alerts   []string      // IDs dos alertas, em ordem cronológica
+	Chain    []string      // Anomalias em ordem cronológica (ex: CPUSpike, ReplicaSpike, MaxedOut)
+	Rule     string        // Regra causal reconhecida (vazio = nenhuma)
+	Severity AlertSeverity // Maior severidade entre os alertas
+	Start    time.Time     // Início do primeiro alerta
+}
+
+// ChainString formata a cadeia do incidente (ex: "CPUSpike → ReplicaSpike → MaxedOut")
+func (i *Incident) ChainString() string {
+	return strings.Join(i.Chain, " → ")
+}
+
+// Status de um UnifiedAlert
+const (
+	AlertStatusActive     = "active"
+	AlertStatusSuppressed = "suppressed"
+	AlertStatusResolved   = "resolved"
+)
+
 // AlertSource indica a origem do alerta
 type AlertSource int
 
@@ -178,19 +260,35 @@ func (a AlertSource) String() string {
 	}
 }
 
+// ParseAlertSource converte "alertmanager" ou "watchdog" (case-insensitive) para AlertSource
+func ParseAlertSource(s string) (AlertSource, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "alertmanager":
+		return AlertSourceAlertmanager, nil
+	case "watchdog":
+		return AlertSourceWatchdog, nil
+	default:
+		return AlertSourceAlertmanager, fmt.Errorf("invalid alert source %q (expected alertmanager or watchdog)", s)
+	}
+}
+
 // AnomalyType define tipos de anomalias
 type AnomalyType int
 
 const (
-	AnomalyReplicaSpike     AnomalyType = iota // Aumento abrupto de réplicas
-	AnomalyReplicaDrop                         // Queda abrupta de réplicas
-	AnomalyCPUSpike                            // CPU current > threshold
-	AnomalyMemorySpike                         // Memory current > threshold
-	AnomalyResourceChange                      // Mudança em requests/limits
-	AnomalyHPAConfigChange                     // Mudança em min/max replicas
-	AnomalyScalingStuck                        // HPA não consegue escalar
-	AnomalyTargetMiss                          // Current muito acima/abaixo do target
-	AnomalyReplicaOscillation                  // Réplicas mudando rapidamente
+	AnomalyReplicaSpike       AnomalyType = iota // Aumento abrupto de réplicas
+	AnomalyReplicaDrop                           // Queda abrupta de réplicas
+	AnomalyCPUSpike                              // CPU current > threshold
+	AnomalyMemorySpike                           // Memory current > threshold
+	AnomalyResourceChange                        // Mudança em requests/limits
+	AnomalyHPAConfigChange                       // Mudança em min/max replicas
+	AnomalyScalingStuck                          // HPA não consegue escalar
+	AnomalyTargetMiss                            // Current muito acima/abaixo do target
+	AnomalyReplicaOscillation                    // Réplicas mudando rapidamente
+	AnomalyMaxedOut                              // No maxReplicas com utilização acima do target
+	AnomalyHighErrorRate                         // Taxa de erros 5xx acima do crítico
+	AnomalyHighLatency                           // Latência P95 acima do crítico
+	AnomalyExternal                              // Alerta externo (Alertmanager) sem anomalia equivalente
 )
 
 func (a AnomalyType) String() string {
@@ -213,16 +311,35 @@ func (a AnomalyType) String() string {
 		return "TargetMiss"
 	case AnomalyReplicaOscillation:
 		return "ReplicaOscillation"
+	case AnomalyMaxedOut:
+		return "MaxedOut"
+	case AnomalyHighErrorRate:
+		return "HighErrorRate"
+	case AnomalyHighLatency:
+		return "HighLatency"
+	case AnomalyExternal:
+		return "External"
 	default:
 		return "Unknown"
 	}
 }
 
+// ParseAnomalyType converte o nome da anomalia (ex: "MaxedOut", case-insensitive) para AnomalyType
+func ParseAnomalyType(s string) (AnomalyType, error) {
+	name := strings.TrimSpace(s)
+	for a := AnomalyReplicaSpike; a <= AnomalyExternal; a++ {
+		if strings.EqualFold(name, a.String()) {
+			return a, nil
+		}
+	}
+	return AnomalyExternal, fmt.Errorf("invalid anomaly type %q", s)
+}
+
 // AlertSeverity define níveis de severidade
 type AlertSeverity int
 
 const (
-	SeverityInfo     AlertSeverity = iota
+	SeverityInfo AlertSeverity = iota
 	SeverityWarning
 	SeverityCritical
 )
@@ -240,6 +357,28 @@ func (s AlertSeverity) String() string {
 	}
 }
 
+// ParseAlertSeverity converte "info", "warning" ou "critical" (case-insensitive) para AlertSeverity
+func ParseAlertSeverity(s string) (AlertSeverity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "info":
+		return SeverityInfo, nil
+	case "warning":
+		return SeverityWarning, nil
+	case "critical":
+		return SeverityCritical, nil
+	default:
+		return SeverityInfo, fmt.Errorf("invalid severity %q (expected info, warning or critical)", s)
+	}
+}
+
+// AlertMatcher regra que identifica alertas externos relacionados a HPA.
+// Os campos preenchidos precisam casar todos; um alerta é relacionado se casar com qualquer matcher.
+type AlertMatcher struct {
+	AlertName string `mapstructure:"alertname"` // Regex aplicada ao label alertname
+	Label     string `mapstructure:"label"`     // Label que precisa estar presente
+	Value     string `mapstructure:"value"`     // Regex aplicada ao valor de Label (opcional)
+}
+
 // AlertContext fornece contexto adicional para alertas
 type AlertContext struct {
 	// Métricas adicionais
@@ -251,26 +390,58 @@ type AlertContext struct {
 	Trend          string // "increasing", "decreasing", "stable"
 	PredictedState string // "will_max_out", "will_stabilize"
 
+	// Mudanças (config/resource change)
+	Changes   []FieldChange // Valores antes/depois
+	ChangedBy string        // Manager do managedFields que fez a mudança (se conhecido)
+
+	// Mudança de réplicas (replica spike/drop)
+	ReplicaChange *ReplicaChange
+
 	// Links
 	PrometheusURL  string
 	GrafanaURL     string
 	KubectlCommand string
 }
 
+// FieldChange mudança de um campo entre dois snapshots consecutivos
+type FieldChange struct {
+	Field  string    // Ex: "maxReplicas", "cpu request"
+	Before string    // Valor anterior
+	After  string    // Valor novo
+	At     time.Time // Timestamp do snapshot onde a mudança apareceu
+
+	// Requests: fator aplicado à utilização vista pelo HPA (antes/depois).
+	// Ex: request de CPU cortado pela metade = 2.0 (utilização dobra). 0 = não se aplica.
+	UtilizationFactor float64
+}
+
+// ReplicaChange variação de réplicas dentro da janela de detecção
+type ReplicaChange struct {
+	Window   time.Duration // Janela avaliada (replica_delta_window_minutes)
+	From     int32         // Réplicas no início da variação
+	To       int32         // Réplicas no fim (atual)
+	FromAt   time.Time     // Quando o HPA estava em From
+	ScaledAt time.Time     // Quando o scale aconteceu (LastScaleTime do HPA, se disponível)
+}
+
 // Thresholds define limites configuráveis
 type Thresholds struct {
 	// Replica changes
-	ReplicaDeltaPercent  float64 // Ex: 50% = alerta se réplicas mudam >50%
-	ReplicaDeltaAbsolute int32   // Ex: 5 = alerta se réplicas mudam ±5
+	ReplicaDeltaPercent       float64 // Ex: 50% = alerta se réplicas mudam >50%
+	ReplicaDeltaAbsolute      int32   // Ex: 5 = alerta se réplicas mudam ±5
+	ReplicaDeltaWindowMinutes int     // Ex: 5 = compara réplicas dentro dos últimos 5 min
+	OscillationMaxChanges     int     // Ex: 3 = alerta se réplicas mudam >3 vezes no histórico
 
 	// CPU/Memory
-	CPUWarningPercent    int32 // Ex: 85% = warning
-	CPUCriticalPercent   int32 // Ex: 95% = critical
-	MemoryWarningPercent int32 // Ex: 85%
+	CPUWarningPercent     int32 // Ex: 85% = warning
+	CPUCriticalPercent    int32 // Ex: 95% = critical
+	MemoryWarningPercent  int32 // Ex: 85%
 	MemoryCriticalPercent int32 // Ex: 90%
 
 	// Target deviation
-	TargetDeviationPercent float64 // Ex: 30% = alerta se current está 30% acima/abaixo do target
+	TargetDeviationPercent      float64 // Ex: 30% = alerta se current está 30% acima/abaixo do target
+	TargetClearDeviationPercent float64 // Ex: 20% = alerta só resolve quando volta a ±20% (0 = sem histerese)
+	TargetMissMinutes           int     // Ex: 3 min fora do target antes de alertar (0 = instantâneo)
 
 	// Scaling behavior
 	ScalingStuckMinutes int // Ex: 10 min sem escalar quando deveria
@@ -283,8 +454,15 @@ type Thresholds struct {
 	RequestRateSpikePercent  float64 // Ex: 100% = alerta se request rate dobrar
 	ErrorRateCriticalPercent float64 // Ex: 5% = alerta se >5% errors
 	P95LatencyCriticalMs     float64 // Ex: 1000ms = alerta se P95 >1s
+}
 
-	sync.RWMutex
+// ThresholdOverride sobrescreve thresholds globais para os HPAs que casam com os seletores.
+// Seletores vazios casam com qualquer valor; o mais específico preenchido define a camada.
+type ThresholdOverride struct {
+	Cluster    string            `mapstructure:"cluster"`    // Glob do cluster (ex: "prod-*")
+	Namespace  string            `mapstructure:"namespace"`  // Glob do namespace (ex: "batch-*")
+	HPA        string            `mapstructure:"hpa"`        // Glob do nome do HPA
+	Thresholds map[string]string `mapstructure:"thresholds"` // Chave de thresholds: -> valor
 }
 
 // WatchdogConfig configuração geral
@@ -293,19 +471,35 @@ type WatchdogConfig struct {
 	ScanIntervalSeconds     int // Ex: 30s entre scans
 	HistoryRetentionMinutes int // Ex: 5 min de histórico
 
+	// Collection (coleta paralela multi-cluster)
+	MaxConcurrentClusters   int  // Clusters coletados em paralelo (ex: 4)
+	MaxConcurrentNamespaces int  // Namespaces em paralelo por cluster (ex: 8)
+	ClusterTimeoutSeconds   int  // Deadline da coleta de cada cluster (ex: 30s)
+	PartialResults          bool // Aceita snapshots coletados antes do deadline estourar
+
 	// Prometheus
-	PrometheusEnabled         bool
-	PrometheusAutoDiscover    bool
-	PrometheusEndpoints       map[string]string // cluster -> endpoint
-	PrometheusFallback        bool
+	PrometheusEnabled           bool
+	PrometheusAutoDiscover      bool
+	PrometheusEndpoints         map[string]string // cluster -> endpoint
+	PrometheusFallback          bool
 	PrometheusDiscoveryPatterns []string
 
 	// Alertmanager
-	AlertmanagerEnabled         bool
-	AlertmanagerAutoDiscover    bool
-	AlertmanagerEndpoints       map[string]string // cluster -> endpoint
-	AlertmanagerSyncInterval    int
+	AlertmanagerEnabled           bool
+	AlertmanagerAutoDiscover      bool
+	AlertmanagerEndpoints         map[string]string // cluster -> endpoint
+	AlertmanagerSyncInterval      int
 	AlertmanagerDiscoveryPatterns []string
+	AlertmanagerSilenceOnAck      bool // Cria silence ao reconhecer alerta do Alertmanager
+	AlertmanagerSilenceMinutes    int  // Duração dos silences criados pelo watchdog
+	AlertmanagerPushEnabled       bool // Envia alertas do watchdog ao Alertmanager
+	AlertmanagerPushResendSeconds int  // Intervalo de reenvio dos alertas ativos
+
+	// Filtros de ingestão do Alertmanager
+	AlertmanagerOnlyHPARelated  bool           // Ingere só alertas que casam com AlertmanagerHPAMatchers
+	AlertmanagerExcludeSilenced bool           // Ignora alertas silenciados
+	AlertmanagerMinSeverity     AlertSeverity  // Severidade mínima ingerida
+	AlertmanagerHPAMatchers     []AlertMatcher // Define "relacionado a HPA" (vazio = padrão)
 
 	// Clusters
 	ClustersConfigPath   string   // Path para clusters-config.json
@@ -313,20 +507,23 @@ type WatchdogConfig struct {
 	ExcludeClusters      []string // Clusters para ignorar
 
 	// Storage
-	EnablePersistence bool   // Salvar histórico em SQLite
-	PersistencePath   string // Ex: ~/.hpa-watchdog/history.db
+	EnablePersistence        bool   // Salvar histórico em SQLite
+	PersistencePath          string // Ex: ~/.hpa-watchdog/history.db
+	PersistenceRetentionDays int    // Dias mantidos no banco (0 = padrão)
 
 	// Alerts
-	MaxActiveAlerts       int      // Máximo de alertas ativos (ex: 100)
-	AutoAckResolvedAlerts bool     // Auto-acknowledge alertas resolvidos
-	SourcePriority        []string // ["alertmanager", "watchdog"]
-	Deduplicate           bool
-	DedupeWindowMinutes   int
-	AutoCorrelate         bool
+	MaxActiveAlerts          int      // Máximo de alertas ativos (ex: 100)
+	AutoAckResolvedAlerts    bool     // Auto-acknowledge alertas resolvidos
+	SourcePriority           []string // ["alertmanager", "watchdog"]
+	Deduplicate              bool
+	DedupeWindowMinutes      int
+	AutoCorrelate            bool
 	CorrelationWindowMinutes int
+	AckUser                  string // Nome registrado em AckedBy (vazio = usuário do SO)
 
 	// Thresholds
-	Thresholds Thresholds
+	Thresholds         Thresholds
+	ThresholdOverrides []ThresholdOverride // Camadas cluster → namespace → HPA sobre Thresholds
 
 	// UI
 	RefreshIntervalMs int    // Ex: 500ms para refresh da TUI
@@ -363,6 +560,7 @@ const (
 	ClusterStatusOnline ClusterStatus = iota
 	ClusterStatusOffline
 	ClusterStatusError
+	ClusterStatusDegraded // Coleta não terminou dentro do deadline do cluster
 )
 
 func (c ClusterStatus) String() string {
@@ -373,6 +571,8 @@ func (c ClusterStatus) String() string {
 		return "Offline"
 	case ClusterStatusError:
 		return "Error"
+	case ClusterStatusDegraded:
+		return "Degraded"
 	default:
 		return "Unknown"
 	}
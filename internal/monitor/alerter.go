@@ -0,0 +1,585 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/analyzer"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultMaxActiveAlerts usado quando a config não define max_active_alerts
+	DefaultMaxActiveAlerts = 100
+
+	// DefaultDedupeWindow usado quando a config não define dedupe_window_minutes
+	DefaultDedupeWindow = 5 * time.Minute
+
+	// AutoAckUser AckedBy de alertas resolvidos reconhecidos automaticamente (auto_ack_resolved)
+	AutoAckUser = "auto"
+)
+
+// AlertStoreOptions controla dedupe, limite e ciclo de vida dos alertas
+type AlertStoreOptions struct {
+	MaxActive       int                  // Acima disso, os alertas menos severos são descartados
+	Deduplicate     bool                 // Mescla um alerta que volta a disparar dentro de DedupeWindow
+	DedupeWindow    time.Duration        // Também é o tempo que alertas resolvidos ficam no store
+	AutoAckResolved bool                 // Reconhece alertas automaticamente ao resolver
+	SourcePriority  []models.AlertSource // Fonte preferida ao mesclar alertas da mesma condição
+}
+
+// DefaultAlertStoreOptions retorna as opções padrão do store
+func DefaultAlertStoreOptions() AlertStoreOptions {
+	return AlertStoreOptions{
+		MaxActive:       DefaultMaxActiveAlerts,
+		Deduplicate:     true,
+		DedupeWindow:    DefaultDedupeWindow,
+		AutoAckResolved: true,
+		SourcePriority:  DefaultSourcePriority,
+	}
+}
+
+// AlertStoreOptionsFromConfig monta as opções do store a partir da config (0 = padrão)
+func AlertStoreOptionsFromConfig(cfg *models.WatchdogConfig) AlertStoreOptions {
+	opts := AlertStoreOptions{
+		MaxActive:       cfg.MaxActiveAlerts,
+		Deduplicate:     cfg.Deduplicate,
+		DedupeWindow:    time.Duration(cfg.DedupeWindowMinutes) * time.Minute,
+		AutoAckResolved: cfg.AutoAckResolvedAlerts,
+		SourcePriority:  sourcePriorityFromConfig(cfg.SourcePriority),
+	}
+	return opts.normalize()
+}
+
+// normalize substitui valores não configurados pelos padrões
+func (o AlertStoreOptions) normalize() AlertStoreOptions {
+	if o.MaxActive < 1 {
+		o.MaxActive = DefaultMaxActiveAlerts
+	}
+	if o.DedupeWindow <= 0 {
+		o.DedupeWindow = DefaultDedupeWindow
+	}
+	if len(o.SourcePriority) == 0 {
+		o.SourcePriority = DefaultSourcePriority
+	}
+	return o
+}
+
+// AlertStore armazena os alertas de todas as fontes com ID estável.
+// Cada fonte sincroniza os alertas que estão disparando em um escopo (ex: um HPA);
+// o que parou de disparar é resolvido.
+type AlertStore struct {
+	opts       AlertStoreOptions
+	alerts     map[string]*models.UnifiedAlert // ID -> alerta (ativo ou resolvido)
+	scopes     map[string]map[string]bool      // escopo -> IDs disparando no último Sync
+	correlator *Correlator                     // nil = sem correlação (auto_correlate: false)
+	incidents  []models.Incident
+	stale      bool                            // Alertas mudaram desde a última correlação
+	restored   map[string]models.UnifiedAlert  // ID -> alerta persistido aguardando voltar a disparar
+	evicted    map[string]models.AlertSeverity // ID -> severidade ao ser descartado por max_active_alerts
+	mu         sync.RWMutex
+}
+
+// NewAlertStore cria um store vazio
+func NewAlertStore(opts AlertStoreOptions) *AlertStore {
+	return &AlertStore{
+		opts:     opts.normalize(),
+		alerts:   make(map[string]*models.UnifiedAlert),
+		scopes:   make(map[string]map[string]bool),
+		restored: make(map[string]models.UnifiedAlert),
+		evicted:  make(map[string]models.AlertSeverity),
+	}
+}
+
+// SetCorrelator liga a correlação automática: incidentes recalculados a cada Correlate/Prune
+// (uma vez por scan ou por lote de Syncs, não a cada Sync de HPA)
+func (s *AlertStore) SetCorrelator(c *Correlator) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.correlator = c
+	s.correlate()
+}
+
+// AlertID ID estável de um alerta: um por HPA e tipo de anomalia (watchdog) ou por fingerprint (Alertmanager)
+func AlertID(alert *models.UnifiedAlert) string {
+	if alert.Source == models.AlertSourceAlertmanager && alert.Fingerprint != "" {
+		return alert.Cluster + "/alertmanager/" + alert.Fingerprint
+	}
+	return analyzer.AlertID(alert.Cluster, alert.Namespace, alert.HPAName, alert.Type)
+}
+
+// Sync registra os alertas disparando em um escopo e resolve os alertas do escopo que pararam.
+// Retorna os alertas abertos agora (não mesclados com um alerta existente, nem com um alerta
+// ativo de outra fonte para a mesma condição).
+func (s *AlertStore) Sync(scope string, firing []models.UnifiedAlert, now time.Time) []models.UnifiedAlert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make(map[string]bool, len(firing))
+	var opened []string
+	active := s.activeCount()
+
+	for i := range firing {
+		alert := firing[i]
+		if alert.ID == "" {
+			alert.ID = AlertID(&alert)
+		}
+		ids[alert.ID] = true
+
+		// Descartado pelo limite: continua suprimido enquanto dispara e o limite está cheio,
+		// a não ser que escale; volta quando há espaço abaixo de max_active_alerts
+		if severity, evicted := s.evicted[alert.ID]; evicted {
+			if alert.Severity <= severity && active >= s.opts.MaxActive {
+				continue
+			}
+			delete(s.evicted, alert.ID)
+			active++
+		}
+
+		if !s.merge(&alert, now) {
+			s.open(&alert, now)
+			opened = append(opened, alert.ID)
+		}
+	}
+
+	for id := range s.scopes[scope] {
+		if !ids[id] {
+			delete(s.evicted, id)
+			s.resolve(id, now)
+		}
+	}
+
+	if len(ids) == 0 {
+		delete(s.scopes, scope)
+	} else {
+		s.scopes[scope] = ids
+	}
+
+	s.evict()
+	s.stale = true
+
+	// Alertas abertos agora podem ter sido descartados pelo limite ou já reportados por outra fonte
+	result := make([]models.UnifiedAlert, 0, len(opened))
+	for _, id := range opened {
+		alert, exists := s.alerts[id]
+		if !exists {
+			continue
+		}
+
+		if duplicates := s.duplicatesOf(alert); len(duplicates) > 0 {
+			log.Debug().
+				Str("alert", id).
+				Strs("duplicates", duplicates).
+				Msg("Alert already reported by another source, merged")
+			continue
+		}
+
+		result = append(result, *alert)
+	}
+	return result
+}
+
+// merge atualiza um alerta existente com o mesmo ID. Alertas resolvidos só são reabertos
+// dentro da janela de dedupe; fora dela (ou sem dedupe) o alerta é aberto de novo.
+func (s *AlertStore) merge(alert *models.UnifiedAlert, now time.Time) bool {
+	existing, exists := s.alerts[alert.ID]
+	if !exists {
+		return false
+	}
+
+	if existing.Status == models.AlertStatusResolved {
+		if !s.opts.Deduplicate || existing.ResolvedAt == nil || now.Sub(*existing.ResolvedAt) > s.opts.DedupeWindow {
+			return false
+		}
+
+		existing.Occurrences++
+		existing.ResolvedAt = nil
+
+		// Reconhecimento automático valia para a ocorrência resolvida
+		if existing.AckedBy == AutoAckUser {
+			existing.Acknowledged = false
+			existing.AckedAt = nil
+			existing.AckedBy = ""
+		}
+	}
+
+	existing.Severity = alert.Severity
+	existing.Summary = alert.Summary
+	existing.Description = alert.Description
+	existing.Status = statusOrActive(alert.Status)
+	existing.Snapshot = alert.Snapshot
+	existing.Context = alert.Context
+	existing.LastSeen = now
+	if alert.Fingerprint != "" {
+		existing.Fingerprint = alert.Fingerprint
+	}
+	if alert.GeneratorURL != "" {
+		existing.GeneratorURL = alert.GeneratorURL
+	}
+	// O Alertmanager é a fonte dos silences: silence expirado some de SilencedBy
+	if alert.Source == models.AlertSourceAlertmanager || len(alert.SilencedBy) > 0 {
+		existing.SilencedBy = alert.SilencedBy
+	}
+
+	return true
+}
+
+// open adiciona um alerta novo (substituindo uma ocorrência resolvida com o mesmo ID)
+func (s *AlertStore) open(alert *models.UnifiedAlert, now time.Time) {
+	alert.Status = statusOrActive(alert.Status)
+	alert.LastSeen = now
+	alert.ResolvedAt = nil
+	alert.Occurrences = 1
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = now
+	}
+
+	s.resume(alert, now)
+	s.alerts[alert.ID] = alert
+}
+
+// Restore registra alertas persistidos que estavam ativos ao encerrar. Se um deles voltar a
+// disparar dentro da janela de dedupe, é tratado como a mesma ocorrência: mantém início,
+// ocorrências e reconhecimento. Só a ocorrência mais recente de cada ID é considerada.
+// Retorna quantos alertas foram registrados.
+func (s *AlertStore) Restore(alerts []models.UnifiedAlert, now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	latest := make(map[string]models.UnifiedAlert)
+	var ids []string
+	for _, alert := range alerts {
+		previous, seen := latest[alert.ID]
+		if !seen {
+			ids = append(ids, alert.ID)
+		}
+		if !seen || alert.Timestamp.After(previous.Timestamp) {
+			latest[alert.ID] = alert
+		}
+	}
+
+	count := 0
+	for _, id := range ids {
+		alert := latest[id]
+		if alert.ID == "" || alert.ResolvedAt != nil || alert.Status == models.AlertStatusResolved {
+			continue
+		}
+		if now.Sub(alert.LastSeen) > s.opts.DedupeWindow {
+			continue
+		}
+		if _, exists := s.alerts[alert.ID]; exists {
+			continue
+		}
+
+		s.restored[alert.ID] = alert
+		count++
+	}
+	return count
+}
+
+// resume aplica o estado persistido (Restore) a um alerta que voltou a disparar
+func (s *AlertStore) resume(alert *models.UnifiedAlert, now time.Time) {
+	restored, exists := s.restored[alert.ID]
+	if !exists {
+		return
+	}
+	delete(s.restored, alert.ID)
+
+	if now.Sub(restored.LastSeen) > s.opts.DedupeWindow {
+		return
+	}
+
+	alert.Timestamp = restored.Timestamp
+	alert.Occurrences = restored.Occurrences
+	if alert.Occurrences < 1 {
+		alert.Occurrences = 1
+	}
+
+	if restored.Acknowledged && restored.AckedBy != AutoAckUser {
+		alert.Acknowledged = true
+		alert.AckedAt = restored.AckedAt
+		alert.AckedBy = restored.AckedBy
+	}
+}
+
+// resolve marca um alerta como resolvido (e reconhecido, se auto_ack_resolved)
+func (s *AlertStore) resolve(id string, now time.Time) {
+	alert, exists := s.alerts[id]
+	if !exists || alert.Status == models.AlertStatusResolved {
+		return
+	}
+
+	alert.Status = models.AlertStatusResolved
+	alert.ResolvedAt = &now
+
+	if s.opts.AutoAckResolved && !alert.Acknowledged {
+		alert.Acknowledged = true
+		alert.AckedAt = &now
+		alert.AckedBy = AutoAckUser
+	}
+
+	log.Info().
+		Str("cluster", alert.Cluster).
+		Str("namespace", alert.Namespace).
+		Str("hpa", alert.HPAName).
+		Str("type", alert.Type.String()).
+		Dur("duration", now.Sub(alert.Timestamp)).
+		Msg("Alert resolved")
+}
+
+// evict descarta os alertas ativos menos severos (e, entre eles, os mais antigos) acima de MaxActive.
+// O ID continua no escopo e fica em evicted: só reabre quando houver espaço abaixo de MaxActive.
+func (s *AlertStore) evict() {
+	var active []*models.UnifiedAlert
+	for _, alert := range s.alerts {
+		if alert.Status != models.AlertStatusResolved {
+			active = append(active, alert)
+		}
+	}
+
+	excess := len(active) - s.opts.MaxActive
+	if excess <= 0 {
+		return
+	}
+
+	sort.Slice(active, func(i, j int) bool {
+		if active[i].Severity != active[j].Severity {
+			return active[i].Severity < active[j].Severity
+		}
+		return active[i].LastSeen.Before(active[j].LastSeen)
+	})
+
+	for _, alert := range active[:excess] {
+		delete(s.alerts, alert.ID)
+		s.evicted[alert.ID] = alert.Severity
+
+		log.Warn().
+			Str("cluster", alert.Cluster).
+			Str("namespace", alert.Namespace).
+			Str("hpa", alert.HPAName).
+			Str("type", alert.Type.String()).
+			Str("severity", alert.Severity.String()).
+			Int("max_active_alerts", s.opts.MaxActive).
+			Msg("Active alert limit reached, evicting alert")
+	}
+}
+
+// activeCount número de alertas não resolvidos no store
+func (s *AlertStore) activeCount() int {
+	count := 0
+	for _, alert := range s.alerts {
+		if alert.Status != models.AlertStatusResolved {
+			count++
+		}
+	}
+	return count
+}
+
+// Prune remove alertas resolvidos há mais que a janela de dedupe e recalcula os incidentes se algo
+// mudou (chamado a cada scan). Retorna quantos foram removidos.
+func (s *AlertStore) Prune(now time.Time) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	removed := 0
+	for id, alert := range s.alerts {
+		if alert.ResolvedAt != nil && now.Sub(*alert.ResolvedAt) > s.opts.DedupeWindow {
+			delete(s.alerts, id)
+			removed++
+		}
+	}
+
+	if removed > 0 || s.stale {
+		s.correlate()
+	}
+	return removed
+}
+
+// Correlate recalcula os incidentes se os alertas mudaram desde a última correlação.
+// Chamado depois de um lote de Syncs de outra fonte (o scan do watcher correlaciona no Prune).
+func (s *AlertStore) Correlate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.stale {
+		s.correlate()
+	}
+}
+
+// correlate recalcula os incidentes e preenche Correlation/IncidentID dos alertas
+func (s *AlertStore) correlate() {
+	s.stale = false
+	for _, alert := range s.alerts {
+		alert.Correlation = nil
+		alert.IncidentID = ""
+	}
+	s.incidents = nil
+
+	if s.correlator == nil {
+		return
+	}
+
+	alerts := make([]models.UnifiedAlert, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		alerts = append(alerts, *alert)
+	}
+
+	s.incidents = s.correlator.Correlate(alerts)
+
+	for _, incident := range s.incidents {
+		for _, id := range incident.Alerts {
+			alert := s.alerts[id]
+			alert.IncidentID = incident.ID
+			for _, other := range incident.Alerts {
+				if other != id {
+					alert.Correlation = append(alert.Correlation, other)
+				}
+			}
+		}
+	}
+}
+
+// Incidents retorna os incidentes atuais, do mais severo ao menos severo
+func (s *AlertStore) Incidents() []models.Incident {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	incidents := make([]models.Incident, len(s.incidents))
+	copy(incidents, s.incidents)
+
+	sort.Slice(incidents, func(i, j int) bool {
+		if incidents[i].Severity != incidents[j].Severity {
+			return incidents[i].Severity > incidents[j].Severity
+		}
+		return incidents[i].Start.Before(incidents[j].Start)
+	})
+
+	return incidents
+}
+
+// Acknowledge reconhece um alerta e os alertas de outras fontes mesclados com ele
+func (s *AlertStore) Acknowledge(id, by string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, exists := s.alerts[id]
+	if !exists {
+		return fmt.Errorf("alert %s not found", id)
+	}
+
+	for _, dup := range append([]string{id}, s.duplicatesOf(alert)...) {
+		s.alerts[dup].Acknowledged = true
+		s.alerts[dup].AckedAt = &now
+		s.alerts[dup].AckedBy = by
+	}
+	return nil
+}
+
+// AddSilence registra um silence criado para o alerta em SilencedBy
+func (s *AlertStore) AddSilence(id, silenceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	alert, exists := s.alerts[id]
+	if !exists {
+		return fmt.Errorf("alert %s not found", id)
+	}
+
+	for _, existing := range alert.SilencedBy {
+		if existing == silenceID {
+			return nil
+		}
+	}
+	alert.SilencedBy = append(alert.SilencedBy, silenceID)
+	return nil
+}
+
+// Get retorna uma cópia do alerta
+func (s *AlertStore) Get(id string) (models.UnifiedAlert, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	alert, exists := s.alerts[id]
+	if !exists {
+		return models.UnifiedAlert{}, false
+	}
+	return *alert, true
+}
+
+// Active retorna os alertas ativos (incluindo suprimidos), do mais severo ao menos severo.
+// Alertas de fontes diferentes para a mesma condição são mesclados (source_priority).
+func (s *AlertStore) Active() []models.UnifiedAlert {
+	return s.list(func(a *models.UnifiedAlert) bool { return a.Status != models.AlertStatusResolved })
+}
+
+// Resolved retorna os alertas resolvidos ainda dentro da janela de dedupe
+func (s *AlertStore) Resolved() []models.UnifiedAlert {
+	return s.list(func(a *models.UnifiedAlert) bool { return a.Status == models.AlertStatusResolved })
+}
+
+// All retorna cópias de todos os alertas (ativos e resolvidos), sem mesclar fontes, ordenadas por ID
+func (s *AlertStore) All() []models.UnifiedAlert {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	alerts := make([]models.UnifiedAlert, 0, len(s.alerts))
+	for _, alert := range s.alerts {
+		alerts = append(alerts, *alert)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].ID < alerts[j].ID
+	})
+
+	return alerts
+}
+
+// ForHPA retorna cópias dos alertas (ativos e resolvidos, de todas as fontes) de um HPA
+func (s *AlertStore) ForHPA(key string) []models.UnifiedAlert {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var alerts []models.UnifiedAlert
+	for _, alert := range s.alerts {
+		if alert.HPAName != "" && models.HPAKey(alert.Cluster, alert.Namespace, alert.HPAName) == key {
+			alerts = append(alerts, *alert)
+		}
+	}
+	return alerts
+}
+
+// list retorna cópias dos alertas que satisfazem o filtro, mescladas por fonte e ordenadas por severidade e ID
+func (s *AlertStore) list(filter func(*models.UnifiedAlert) bool) []models.UnifiedAlert {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var alerts []models.UnifiedAlert
+	for _, alert := range s.alerts {
+		if filter(alert) {
+			alerts = append(alerts, *alert)
+		}
+	}
+
+	alerts = s.mergeSources(alerts)
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Severity != alerts[j].Severity {
+			return alerts[i].Severity > alerts[j].Severity
+		}
+		return alerts[i].ID < alerts[j].ID
+	})
+
+	return alerts
+}
+
+// statusOrActive status informado pela fonte (padrão: active)
+func statusOrActive(status string) string {
+	if status == "" || status == models.AlertStatusResolved {
+		return models.AlertStatusActive
+	}
+	return status
+}
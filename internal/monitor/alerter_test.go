@@ -0,0 +1,308 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// newTestAlert cria um alerta do watchdog sem ID (o store gera)
+func newTestAlert(hpa string, anomaly models.AnomalyType, severity models.AlertSeverity) models.UnifiedAlert {
+	return models.UnifiedAlert{
+		Source:    models.AlertSourceWatchdog,
+		Severity:  severity,
+		Type:      anomaly,
+		Cluster:   "test-cluster",
+		Namespace: "production",
+		HPAName:   hpa,
+		Summary:   anomaly.String(),
+	}
+}
+
+func TestAlertStoreLifecycle(t *testing.T) {
+	store := NewAlertStore(DefaultAlertStoreOptions())
+	start := time.Now()
+	scope := models.HPAKey("test-cluster", "production", "api")
+
+	firing := []models.UnifiedAlert{newTestAlert("api", models.AnomalyCPUSpike, models.SeverityWarning)}
+
+	opened := store.Sync(scope, firing, start)
+	if len(opened) != 1 || opened[0].ID != "test-cluster/production/api/CPUSpike" {
+		t.Fatalf("Expected 1 opened alert with stable ID, got %+v", opened)
+	}
+	if opened[0].Status != models.AlertStatusActive || opened[0].Occurrences != 1 || !opened[0].Timestamp.Equal(start) {
+		t.Errorf("Unexpected opened alert %+v", opened[0])
+	}
+
+	// Continua disparando: atualiza sem abrir de novo
+	firing[0].Severity = models.SeverityCritical
+	if opened := store.Sync(scope, firing, start.Add(30*time.Second)); len(opened) != 0 {
+		t.Errorf("Expected update, not a new alert, got %+v", opened)
+	}
+	alert, _ := store.Get("test-cluster/production/api/CPUSpike")
+	if alert.Severity != models.SeverityCritical || !alert.LastSeen.Equal(start.Add(30*time.Second)) {
+		t.Errorf("Expected severity/LastSeen updated, got %+v", alert)
+	}
+
+	// Parou de disparar: resolvido e reconhecido automaticamente
+	resolvedAt := start.Add(time.Minute)
+	store.Sync(scope, nil, resolvedAt)
+
+	if active := store.Active(); len(active) != 0 {
+		t.Errorf("Expected no active alerts, got %+v", active)
+	}
+	resolved := store.Resolved()
+	if len(resolved) != 1 {
+		t.Fatalf("Expected 1 resolved alert, got %+v", resolved)
+	}
+	if resolved[0].ResolvedAt == nil || !resolved[0].ResolvedAt.Equal(resolvedAt) {
+		t.Errorf("Expected ResolvedAt %s, got %v", resolvedAt, resolved[0].ResolvedAt)
+	}
+	if !resolved[0].Acknowledged || resolved[0].AckedBy != AutoAckUser {
+		t.Errorf("Expected auto-ack on resolve, got %+v", resolved[0])
+	}
+
+	// Removido após a janela de dedupe
+	if removed := store.Prune(resolvedAt.Add(DefaultDedupeWindow + time.Second)); removed != 1 {
+		t.Errorf("Expected 1 pruned alert, got %d", removed)
+	}
+}
+
+func TestAlertStoreDedupeWindow(t *testing.T) {
+	store := NewAlertStore(DefaultAlertStoreOptions())
+	start := time.Now()
+	scope := models.HPAKey("test-cluster", "production", "api")
+	firing := []models.UnifiedAlert{newTestAlert("api", models.AnomalyTargetMiss, models.SeverityWarning)}
+	id := "test-cluster/production/api/TargetMiss"
+
+	store.Sync(scope, firing, start)
+	store.Sync(scope, nil, start.Add(time.Minute))
+
+	// Volta a disparar dentro da janela: mesma ocorrência, reaberta
+	if opened := store.Sync(scope, firing, start.Add(2*time.Minute)); len(opened) != 0 {
+		t.Errorf("Expected duplicate to be merged, got %+v", opened)
+	}
+	alert, _ := store.Get(id)
+	if alert.Status != models.AlertStatusActive || alert.Occurrences != 2 || !alert.Timestamp.Equal(start) {
+		t.Errorf("Expected reopened alert with 2 occurrences, got %+v", alert)
+	}
+	if alert.Acknowledged || alert.ResolvedAt != nil {
+		t.Errorf("Expected auto-ack and ResolvedAt cleared on reopen, got %+v", alert)
+	}
+
+	// Fora da janela: nova ocorrência
+	store.Sync(scope, nil, start.Add(3*time.Minute))
+	later := start.Add(3*time.Minute + DefaultDedupeWindow + time.Second)
+	if opened := store.Sync(scope, firing, later); len(opened) != 1 || opened[0].Occurrences != 1 || !opened[0].Timestamp.Equal(later) {
+		t.Errorf("Expected a new alert after the dedupe window, got %+v", opened)
+	}
+}
+
+func TestAlertStoreWithoutDedupe(t *testing.T) {
+	opts := DefaultAlertStoreOptions()
+	opts.Deduplicate = false
+	opts.AutoAckResolved = false
+	store := NewAlertStore(opts)
+
+	start := time.Now()
+	scope := models.HPAKey("test-cluster", "production", "api")
+	firing := []models.UnifiedAlert{newTestAlert("api", models.AnomalyCPUSpike, models.SeverityWarning)}
+
+	store.Sync(scope, firing, start)
+	store.Sync(scope, nil, start.Add(time.Minute))
+
+	if resolved := store.Resolved(); len(resolved) != 1 || resolved[0].Acknowledged {
+		t.Errorf("Expected resolved alert without auto-ack, got %+v", resolved)
+	}
+
+	if opened := store.Sync(scope, firing, start.Add(2*time.Minute)); len(opened) != 1 {
+		t.Errorf("Expected a new alert without dedupe, got %+v", opened)
+	}
+}
+
+func TestAlertStoreEvictsLowestSeverity(t *testing.T) {
+	opts := DefaultAlertStoreOptions()
+	opts.MaxActive = 2
+	store := NewAlertStore(opts)
+	now := time.Now()
+
+	store.Sync("a", []models.UnifiedAlert{newTestAlert("a", models.AnomalyCPUSpike, models.SeverityCritical)}, now)
+	store.Sync("b", []models.UnifiedAlert{newTestAlert("b", models.AnomalyTargetMiss, models.SeverityInfo)}, now)
+	store.Sync("c", []models.UnifiedAlert{newTestAlert("c", models.AnomalyMaxedOut, models.SeverityWarning)}, now.Add(time.Second))
+
+	active := store.Active()
+	if len(active) != 2 {
+		t.Fatalf("Expected 2 active alerts, got %d", len(active))
+	}
+	if active[0].HPAName != "a" || active[1].HPAName != "c" {
+		t.Errorf("Expected Info alert to be evicted, got %s and %s", active[0].ID, active[1].ID)
+	}
+
+	// Novo alerta menos severo que todos: descartado e não reportado como aberto
+	if opened := store.Sync("d", []models.UnifiedAlert{newTestAlert("d", models.AnomalyTargetMiss, models.SeverityInfo)}, now.Add(2*time.Second)); len(opened) != 0 {
+		t.Errorf("Expected evicted alert not to be reported as opened, got %+v", opened)
+	}
+}
+
+func TestAlertStoreEvictedStaysSuppressed(t *testing.T) {
+	opts := DefaultAlertStoreOptions()
+	opts.MaxActive = 2
+	store := NewAlertStore(opts)
+	now := time.Now()
+
+	sync := func(at time.Time, severityB models.AlertSeverity) []models.UnifiedAlert {
+		var opened []models.UnifiedAlert
+		opened = append(opened, store.Sync("a", []models.UnifiedAlert{newTestAlert("a", models.AnomalyCPUSpike, models.SeverityCritical)}, at)...)
+		opened = append(opened, store.Sync("b", []models.UnifiedAlert{newTestAlert("b", models.AnomalyTargetMiss, severityB)}, at)...)
+		opened = append(opened, store.Sync("c", []models.UnifiedAlert{newTestAlert("c", models.AnomalyMaxedOut, models.SeverityWarning)}, at)...)
+		return opened
+	}
+
+	if opened := sync(now, models.SeverityInfo); len(opened) != 3 || len(store.Active()) != 2 {
+		t.Fatalf("Expected 3 opened alerts and 2 kept on first scan, got %+v", opened)
+	}
+	store.Acknowledge("test-cluster/production/c/MaxedOut", "alice", now)
+
+	// Vários scans acima do limite: o descartado não reabre nem derruba o ack dos outros
+	for i := 1; i <= 5; i++ {
+		if opened := sync(now.Add(time.Duration(i)*time.Minute), models.SeverityInfo); len(opened) != 0 {
+			t.Fatalf("Scan %d: expected evicted alert to stay suppressed, got %+v", i, opened)
+		}
+	}
+	alert, _ := store.Get("test-cluster/production/c/MaxedOut")
+	if len(store.Active()) != 2 || !alert.Acknowledged || alert.Occurrences != 1 {
+		t.Errorf("Expected active alerts untouched, got %+v", store.Active())
+	}
+	if _, exists := store.Get("test-cluster/production/b/TargetMiss"); exists {
+		t.Error("Expected evicted alert to stay out of the store")
+	}
+
+	// Escalou: entra no lugar do menos severo
+	if opened := sync(now.Add(6*time.Minute), models.SeverityCritical); len(opened) != 1 || opened[0].HPAName != "b" {
+		t.Errorf("Expected escalated alert reopened, got %+v", opened)
+	}
+	if _, exists := store.Get("test-cluster/production/c/MaxedOut"); exists {
+		t.Error("Expected Warning alert evicted for the escalated one")
+	}
+
+	// Ativos abaixo do limite: o descartado volta a aparecer mesmo sem escalar
+	store.Sync("b", nil, now.Add(7*time.Minute))
+	warning := []models.UnifiedAlert{newTestAlert("c", models.AnomalyMaxedOut, models.SeverityWarning)}
+	if opened := store.Sync("c", warning, now.Add(8*time.Minute)); len(opened) != 1 || opened[0].HPAName != "c" {
+		t.Errorf("Expected evicted alert re-admitted below the cap, got %+v", opened)
+	}
+	if len(store.Active()) != 2 {
+		t.Errorf("Expected 2 active alerts after re-admission, got %d", len(store.Active()))
+	}
+	if opened := store.Sync("c", warning, now.Add(9*time.Minute)); len(opened) != 0 {
+		t.Errorf("Expected re-admitted alert merged on the next scan, got %+v", opened)
+	}
+}
+
+func TestAlertStoreAcknowledge(t *testing.T) {
+	store := NewAlertStore(DefaultAlertStoreOptions())
+	now := time.Now()
+
+	store.Sync("a", []models.UnifiedAlert{newTestAlert("api", models.AnomalyCPUSpike, models.SeverityWarning)}, now)
+
+	if err := store.Acknowledge("test-cluster/production/api/CPUSpike", "alice", now); err != nil {
+		t.Fatalf("Acknowledge() error = %v", err)
+	}
+	alert, _ := store.Get("test-cluster/production/api/CPUSpike")
+	if !alert.Acknowledged || alert.AckedBy != "alice" {
+		t.Errorf("Expected alert acked by alice, got %+v", alert)
+	}
+
+	// Ack manual sobrevive ao resolve
+	store.Sync("a", nil, now.Add(time.Minute))
+	if alert, _ := store.Get(alert.ID); alert.AckedBy != "alice" {
+		t.Errorf("Expected manual ack to be kept on resolve, got %q", alert.AckedBy)
+	}
+
+	if err := store.Acknowledge("missing", "alice", now); err == nil {
+		t.Error("Expected error for unknown alert")
+	}
+}
+
+func TestAlertStoreRestore(t *testing.T) {
+	start := time.Now()
+	ackedAt := start.Add(-2 * time.Minute)
+
+	persisted := []models.UnifiedAlert{
+		{ID: "test-cluster/production/api/CPUSpike", Timestamp: start.Add(-time.Hour), LastSeen: start.Add(-time.Minute),
+			Occurrences: 3, Acknowledged: true, AckedAt: &ackedAt, AckedBy: "alice"},
+		{ID: "test-cluster/production/api/MaxedOut", Timestamp: start.Add(-time.Hour), LastSeen: start.Add(-time.Hour)},
+		{ID: "test-cluster/production/web/CPUSpike", Timestamp: start.Add(-time.Hour), LastSeen: start.Add(-time.Minute), ResolvedAt: &ackedAt},
+		// Ocorrência anterior do mesmo ID gravada como ativa: vale a mais recente (resolvida)
+		{ID: "test-cluster/production/web/CPUSpike", Timestamp: start.Add(-3 * time.Hour), LastSeen: start.Add(-time.Minute)},
+	}
+
+	store := NewAlertStore(DefaultAlertStoreOptions())
+	if restored := store.Restore(persisted, start); restored != 1 {
+		t.Fatalf("Expected only the recent unresolved alert restored, got %d", restored)
+	}
+
+	// Volta a disparar depois do restart: mesma ocorrência, reconhecimento mantido
+	scope := models.HPAKey("test-cluster", "production", "api")
+	store.Sync(scope, []models.UnifiedAlert{
+		newTestAlert("api", models.AnomalyCPUSpike, models.SeverityWarning),
+		newTestAlert("api", models.AnomalyMaxedOut, models.SeverityCritical),
+	}, start)
+
+	alert, _ := store.Get("test-cluster/production/api/CPUSpike")
+	if !alert.Acknowledged || alert.AckedBy != "alice" || !alert.Timestamp.Equal(start.Add(-time.Hour)) || alert.Occurrences != 3 {
+		t.Errorf("Expected persisted ack and start kept, got %+v", alert)
+	}
+
+	alert, _ = store.Get("test-cluster/production/api/MaxedOut")
+	if alert.Acknowledged || !alert.Timestamp.Equal(start) {
+		t.Errorf("Expected stale persisted alert ignored, got %+v", alert)
+	}
+}
+
+func TestAlertIDAlertmanager(t *testing.T) {
+	alert := models.UnifiedAlert{Source: models.AlertSourceAlertmanager, Cluster: "prod", Fingerprint: "abc123"}
+	if id := AlertID(&alert); id != "prod/alertmanager/abc123" {
+		t.Errorf("Unexpected Alertmanager alert ID %q", id)
+	}
+}
+
+func TestAlertStoreSilences(t *testing.T) {
+	store := NewAlertStore(DefaultAlertStoreOptions())
+	now := time.Now()
+
+	alert := models.UnifiedAlert{Source: models.AlertSourceAlertmanager, Cluster: "prod", Fingerprint: "abc123"}
+	store.Sync("alertmanager/prod", []models.UnifiedAlert{alert}, now)
+
+	if err := store.AddSilence("prod/alertmanager/abc123", "s1"); err != nil {
+		t.Fatalf("AddSilence failed: %v", err)
+	}
+	store.AddSilence("prod/alertmanager/abc123", "s1")
+
+	if got, _ := store.Get("prod/alertmanager/abc123"); len(got.SilencedBy) != 1 || got.SilencedBy[0] != "s1" {
+		t.Errorf("Expected SilencedBy [s1], got %v", got.SilencedBy)
+	}
+
+	// Silence expirado: o Alertmanager deixa de reportar e SilencedBy é limpo
+	store.Sync("alertmanager/prod", []models.UnifiedAlert{alert}, now.Add(time.Minute))
+	if got, _ := store.Get("prod/alertmanager/abc123"); len(got.SilencedBy) != 0 {
+		t.Errorf("Expected SilencedBy cleared from Alertmanager state, got %v", got.SilencedBy)
+	}
+
+	if err := store.AddSilence("missing", "s1"); err == nil {
+		t.Error("Expected error for unknown alert")
+	}
+}
+
+func TestAlertStoreOptionsFromConfig(t *testing.T) {
+	opts := AlertStoreOptionsFromConfig(&models.WatchdogConfig{Deduplicate: true})
+	if opts.MaxActive != DefaultMaxActiveAlerts || opts.DedupeWindow != DefaultDedupeWindow {
+		t.Errorf("Expected defaults for unset values, got %+v", opts)
+	}
+
+	opts = AlertStoreOptionsFromConfig(&models.WatchdogConfig{MaxActiveAlerts: 10, DedupeWindowMinutes: 2, AutoAckResolvedAlerts: true})
+	if opts.MaxActive != 10 || opts.DedupeWindow != 2*time.Minute || !opts.AutoAckResolved || opts.Deduplicate {
+		t.Errorf("Unexpected options %+v", opts)
+	}
+}
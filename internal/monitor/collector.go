@@ -0,0 +1,327 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultMaxConcurrentClusters clusters coletados em paralelo
+	DefaultMaxConcurrentClusters = 4
+
+	// DefaultMaxConcurrentNamespaces namespaces coletados em paralelo por cluster
+	DefaultMaxConcurrentNamespaces = 8
+
+	// DefaultClusterTimeout deadline padrão da coleta de um cluster
+	DefaultClusterTimeout = 30 * time.Second
+)
+
+// CollectOptions controla a coleta paralela entre clusters e namespaces
+type CollectOptions struct {
+	MaxConcurrentClusters   int
+	MaxConcurrentNamespaces int
+	ClusterTimeout          time.Duration
+	PartialResults          bool // Usa snapshots coletados antes do deadline estourar
+}
+
+// DefaultCollectOptions retorna as opções padrão de coleta
+func DefaultCollectOptions() CollectOptions {
+	return CollectOptions{
+		MaxConcurrentClusters:   DefaultMaxConcurrentClusters,
+		MaxConcurrentNamespaces: DefaultMaxConcurrentNamespaces,
+		ClusterTimeout:          DefaultClusterTimeout,
+		PartialResults:          true,
+	}
+}
+
+// CollectOptionsFromConfig monta as opções de coleta a partir da config (0 = padrão)
+func CollectOptionsFromConfig(cfg *models.WatchdogConfig) CollectOptions {
+	opts := CollectOptions{
+		MaxConcurrentClusters:   cfg.MaxConcurrentClusters,
+		MaxConcurrentNamespaces: cfg.MaxConcurrentNamespaces,
+		ClusterTimeout:          time.Duration(cfg.ClusterTimeoutSeconds) * time.Second,
+		PartialResults:          cfg.PartialResults,
+	}
+	return opts.normalize()
+}
+
+// normalize substitui valores não configurados pelos padrões
+func (o CollectOptions) normalize() CollectOptions {
+	if o.MaxConcurrentClusters < 1 {
+		o.MaxConcurrentClusters = DefaultMaxConcurrentClusters
+	}
+	if o.MaxConcurrentNamespaces < 1 {
+		o.MaxConcurrentNamespaces = DefaultMaxConcurrentNamespaces
+	}
+	if o.ClusterTimeout <= 0 {
+		o.ClusterTimeout = DefaultClusterTimeout
+	}
+	return o
+}
+
+// EnrichFunc enriquece um snapshot dentro do deadline da coleta do cluster
+type EnrichFunc func(ctx context.Context, snapshot *models.HPASnapshot)
+
+// ClusterCollectResult resultado da coleta de um cluster
+type ClusterCollectResult struct {
+	Cluster   string
+	Snapshots []*models.HPASnapshot
+	Duration  time.Duration
+	TimedOut  bool  // Deadline do cluster estourou
+	Err       error // Falha que impediu a coleta (ex: list namespaces)
+}
+
+// Status traduz o resultado da coleta para o status do cluster
+func (r ClusterCollectResult) Status() models.ClusterStatus {
+	switch {
+	case r.TimedOut:
+		return models.ClusterStatusDegraded
+	case r.Err != nil:
+		return models.ClusterStatusError
+	default:
+		return models.ClusterStatusOnline
+	}
+}
+
+// SetCollectOptions define as opções de coleta da sessão
+func (s *MonitoringSession) SetCollectOptions(opts CollectOptions) {
+	s.collectOpts = opts.normalize()
+}
+
+// CollectClusters coleta todos os clusters em paralelo (limitado por MaxConcurrentClusters),
+// cada um com seu próprio deadline. Um cluster lento não atrasa os demais.
+func (s *MonitoringSession) CollectClusters() []ClusterCollectResult {
+	return s.CollectAndEnrich(nil)
+}
+
+// CollectAndEnrich coleta como CollectClusters e enriquece os snapshots no worker do cluster,
+// sob o mesmo deadline da coleta (enrich nil = sem enriquecimento)
+func (s *MonitoringSession) CollectAndEnrich(enrich EnrichFunc) []ClusterCollectResult {
+	opts := s.collectOpts.normalize()
+
+	// Cópia: clusters podem ser adicionados/removidos (reload) durante a coleta
+	s.mu.RLock()
+	clients := make(map[string]*K8sClient, len(s.k8sClients))
+	for clusterName, client := range s.k8sClients {
+		clients[clusterName] = client
+	}
+	s.mu.RUnlock()
+
+	results := make([]ClusterCollectResult, 0, len(clients))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.MaxConcurrentClusters)
+
+	for clusterName, client := range clients {
+		wg.Add(1)
+		go func(clusterName string, client *K8sClient) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := s.collectCluster(clusterName, client, opts, enrich)
+
+			mu.Lock()
+			results = append(results, result)
+			mu.Unlock()
+		}(clusterName, client)
+	}
+
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Cluster < results[j].Cluster
+	})
+
+	return results
+}
+
+// CollectAllHPAs coleta snapshots de todos os HPAs de todos os clusters
+func (s *MonitoringSession) CollectAllHPAs() ([]*models.HPASnapshot, error) {
+	var allSnapshots []*models.HPASnapshot
+
+	for _, result := range s.CollectClusters() {
+		allSnapshots = append(allSnapshots, result.Snapshots...)
+	}
+
+	log.Info().
+		Int("total_snapshots", len(allSnapshots)).
+		Msg("HPA collection complete")
+
+	return allSnapshots, nil
+}
+
+// collectCluster coleta (e enriquece) um cluster respeitando o deadline configurado
+func (s *MonitoringSession) collectCluster(clusterName string, client *K8sClient, opts CollectOptions, enrich EnrichFunc) ClusterCollectResult {
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(s.ctx, opts.ClusterTimeout)
+	defer cancel()
+
+	log.Debug().
+		Str("cluster", clusterName).
+		Dur("timeout", opts.ClusterTimeout).
+		Msg("Collecting HPAs from cluster")
+
+	var snapshots []*models.HPASnapshot
+	var err error
+	s.mu.RLock()
+	ci, ok := s.informers[clusterName]
+	s.mu.RUnlock()
+
+	if ok {
+		snapshots, err = s.collectFromCache(ctx, client, ci)
+	} else {
+		snapshots, err = s.collectFromAPI(ctx, client, opts.MaxConcurrentNamespaces)
+	}
+
+	if enrich != nil {
+		for _, snapshot := range snapshots {
+			if ctx.Err() != nil {
+				break
+			}
+			enrich(ctx, snapshot)
+		}
+	}
+
+	result := ClusterCollectResult{
+		Cluster:   clusterName,
+		Snapshots: snapshots,
+		Duration:  time.Since(start),
+		Err:       err,
+		TimedOut:  errors.Is(ctx.Err(), context.DeadlineExceeded),
+	}
+
+	if result.TimedOut {
+		if !opts.PartialResults {
+			result.Snapshots = nil
+		}
+
+		log.Warn().
+			Str("cluster", clusterName).
+			Dur("timeout", opts.ClusterTimeout).
+			Int("partial_snapshots", len(result.Snapshots)).
+			Msg("Cluster collection exceeded deadline, marking degraded")
+	}
+
+	return result
+}
+
+// collectFromCache monta snapshots a partir do cache local dos informers (sem API calls)
+func (s *MonitoringSession) collectFromCache(ctx context.Context, client *K8sClient, ci *ClusterInformers) ([]*models.HPASnapshot, error) {
+	clusterName := client.GetClusterInfo().Name
+
+	hpas, err := ci.ListHPAs("")
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("cluster", clusterName).
+			Msg("Failed to list HPAs from cache")
+		return nil, err
+	}
+
+	var snapshots []*models.HPASnapshot
+	for _, hpa := range hpas {
+		if ctx.Err() != nil {
+			break
+		}
+
+		if isExcludedNamespace(hpa.Namespace) {
+			continue
+		}
+
+		snapshot, err := client.CollectHPASnapshot(ctx, hpa)
+		if err != nil {
+			log.Warn().
+				Err(err).
+				Str("cluster", clusterName).
+				Str("namespace", hpa.Namespace).
+				Str("hpa", hpa.Name).
+				Msg("Failed to collect HPA snapshot")
+			continue
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// collectFromAPI lista namespaces e HPAs via API (fallback quando não há informers),
+// com até maxNamespaces namespaces em paralelo
+func (s *MonitoringSession) collectFromAPI(ctx context.Context, client *K8sClient, maxNamespaces int) ([]*models.HPASnapshot, error) {
+	clusterName := client.GetClusterInfo().Name
+
+	// Lista namespaces
+	namespaces, err := client.ListNamespaces(ctx, []string{})
+	if err != nil {
+		log.Error().
+			Err(err).
+			Str("cluster", clusterName).
+			Msg("Failed to list namespaces")
+		return nil, err
+	}
+
+	var snapshots []*models.HPASnapshot
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxNamespaces)
+
+	// Para cada namespace, lista HPAs
+	for _, namespace := range namespaces {
+		wg.Add(1)
+		go func(namespace string) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				return
+			}
+
+			hpas, err := client.ListHPAs(ctx, namespace)
+			if err != nil {
+				log.Warn().
+					Err(err).
+					Str("cluster", clusterName).
+					Str("namespace", namespace).
+					Msg("Failed to list HPAs")
+				return
+			}
+
+			// Coleta snapshot de cada HPA
+			for i := range hpas {
+				if ctx.Err() != nil {
+					return
+				}
+
+				snapshot, err := client.CollectHPASnapshot(ctx, &hpas[i])
+				if err != nil {
+					log.Warn().
+						Err(err).
+						Str("cluster", clusterName).
+						Str("namespace", namespace).
+						Str("hpa", hpas[i].Name).
+						Msg("Failed to collect HPA snapshot")
+					continue
+				}
+
+				mu.Lock()
+				snapshots = append(snapshots, snapshot)
+				mu.Unlock()
+			}
+		}(namespace)
+	}
+
+	wg.Wait()
+
+	return snapshots, nil
+}
@@ -0,0 +1,258 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	autoscalingv2client "k8s.io/client-go/kubernetes/typed/autoscaling/v2"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newMultiClusterSession cria uma sessão com os clientsets informados (cluster -> clientset)
+func newMultiClusterSession(clientsets map[string]kubernetes.Interface) *MonitoringSession {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	session := &MonitoringSession{
+		k8sClients: make(map[string]*K8sClient),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+
+	for name, clientset := range clientsets {
+		session.k8sClients[name] = &K8sClient{
+			Clientset: clientset,
+			cluster:   &models.ClusterInfo{Name: name},
+		}
+	}
+
+	return session
+}
+
+// newClusterObjects cria namespaces com um HPA cada
+func newClusterObjects(namespaces ...string) []runtime.Object {
+	var objects []runtime.Object
+	for _, ns := range namespaces {
+		objects = append(objects,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: ns}},
+			newTestHPA(ns, "api"),
+		)
+	}
+	return objects
+}
+
+// slowClientset atrasa o list de HPAs por namespace ("" = todos), respeitando o ctx.
+// O atraso fica fora do fake (que serializa todas as chamadas com um lock).
+type slowClientset struct {
+	*fake.Clientset
+	delays map[string]time.Duration
+}
+
+func (c *slowClientset) AutoscalingV2() autoscalingv2client.AutoscalingV2Interface {
+	return &slowAutoscaling{AutoscalingV2Interface: c.Clientset.AutoscalingV2(), delays: c.delays}
+}
+
+type slowAutoscaling struct {
+	autoscalingv2client.AutoscalingV2Interface
+	delays map[string]time.Duration
+}
+
+func (a *slowAutoscaling) HorizontalPodAutoscalers(namespace string) autoscalingv2client.HorizontalPodAutoscalerInterface {
+	delay, ok := a.delays[namespace]
+	if !ok {
+		delay = a.delays[""]
+	}
+	return &slowHPAs{
+		HorizontalPodAutoscalerInterface: a.AutoscalingV2Interface.HorizontalPodAutoscalers(namespace),
+		delay:                            delay,
+	}
+}
+
+type slowHPAs struct {
+	autoscalingv2client.HorizontalPodAutoscalerInterface
+	delay time.Duration
+}
+
+func (h *slowHPAs) List(ctx context.Context, opts metav1.ListOptions) (*autoscalingv2.HorizontalPodAutoscalerList, error) {
+	select {
+	case <-time.After(h.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return h.HorizontalPodAutoscalerInterface.List(ctx, opts)
+}
+
+func TestCollectClustersParallel(t *testing.T) {
+	fast := fake.NewClientset(newClusterObjects("team-a", "team-b", "team-c")...)
+	slow := &slowClientset{
+		Clientset: fake.NewClientset(newClusterObjects("team-a")...),
+		delays:    map[string]time.Duration{"": 5 * time.Second},
+	}
+
+	session := newMultiClusterSession(map[string]kubernetes.Interface{
+		"fast-cluster": fast,
+		"slow-cluster": slow,
+	})
+	defer session.Shutdown()
+
+	session.SetCollectOptions(CollectOptions{
+		MaxConcurrentClusters:   2,
+		MaxConcurrentNamespaces: 2,
+		ClusterTimeout:          100 * time.Millisecond,
+		PartialResults:          false,
+	})
+
+	start := time.Now()
+	results := session.CollectClusters()
+	elapsed := time.Since(start)
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 cluster results, got %d", len(results))
+	}
+
+	// Ordenado por nome
+	fastResult, slowResult := results[0], results[1]
+
+	if fastResult.Status() != models.ClusterStatusOnline {
+		t.Errorf("Expected fast-cluster Online, got %s", fastResult.Status())
+	}
+	if len(fastResult.Snapshots) != 3 {
+		t.Errorf("Expected 3 snapshots from fast-cluster, got %d", len(fastResult.Snapshots))
+	}
+
+	if slowResult.Status() != models.ClusterStatusDegraded {
+		t.Errorf("Expected slow-cluster Degraded, got %s", slowResult.Status())
+	}
+	if len(slowResult.Snapshots) != 0 {
+		t.Errorf("Expected no snapshots from slow-cluster without partial results, got %d", len(slowResult.Snapshots))
+	}
+
+	// Clusters em paralelo: tempo total ~ cluster mais lento, não a soma
+	if elapsed > 2*time.Second {
+		t.Errorf("Collection took too long: %s", elapsed)
+	}
+}
+
+func TestCollectClustersPartialResults(t *testing.T) {
+	clientset := &slowClientset{
+		Clientset: fake.NewClientset(newClusterObjects("team-a", "team-b")...),
+		delays:    map[string]time.Duration{"team-b": 5 * time.Second},
+	}
+
+	session := newMultiClusterSession(map[string]kubernetes.Interface{"test-cluster": clientset})
+	defer session.Shutdown()
+
+	session.SetCollectOptions(CollectOptions{
+		MaxConcurrentNamespaces: 2,
+		ClusterTimeout:          200 * time.Millisecond,
+		PartialResults:          true,
+	})
+
+	results := session.CollectClusters()
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	result := results[0]
+	if !result.TimedOut {
+		t.Error("Expected cluster to time out")
+	}
+	if len(result.Snapshots) != 1 || result.Snapshots[0].Namespace != "team-a" {
+		t.Errorf("Expected partial snapshot from team-a, got %d snapshots", len(result.Snapshots))
+	}
+}
+
+func TestCollectAndEnrichDeadline(t *testing.T) {
+	session := newMultiClusterSession(map[string]kubernetes.Interface{
+		"slow-prometheus": fake.NewClientset(newClusterObjects("team-a", "team-b")...),
+		"fast-prometheus": fake.NewClientset(newClusterObjects("team-a")...),
+	})
+	defer session.Shutdown()
+
+	session.SetCollectOptions(CollectOptions{
+		MaxConcurrentClusters: 2,
+		ClusterTimeout:        200 * time.Millisecond,
+		PartialResults:        true,
+	})
+
+	// Prometheus lento só responde quando o deadline do cluster estoura
+	var mu sync.Mutex
+	enriched := make(map[string]int)
+	enrich := func(ctx context.Context, snapshot *models.HPASnapshot) {
+		if snapshot.Cluster == "slow-prometheus" {
+			<-ctx.Done()
+			return
+		}
+		mu.Lock()
+		enriched[snapshot.Cluster]++
+		mu.Unlock()
+	}
+
+	start := time.Now()
+	results := session.CollectAndEnrich(enrich)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Enrichment should respect the cluster deadline, took %s", elapsed)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Cluster != "fast-prometheus" || results[0].TimedOut || enriched["fast-prometheus"] != 1 {
+		t.Errorf("Expected fast cluster enriched within deadline, got %+v (enriched %d)", results[0], enriched["fast-prometheus"])
+	}
+	if results[1].Cluster != "slow-prometheus" || !results[1].TimedOut {
+		t.Errorf("Expected slow enrichment to mark cluster degraded, got %+v", results[1])
+	}
+}
+
+func TestCollectClustersError(t *testing.T) {
+	clientset := fake.NewClientset()
+	clientset.PrependReactor("list", "namespaces", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("connection refused")
+	})
+
+	session := newMultiClusterSession(map[string]kubernetes.Interface{"broken-cluster": clientset})
+	defer session.Shutdown()
+
+	results := session.CollectClusters()
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 result, got %d", len(results))
+	}
+
+	if results[0].Status() != models.ClusterStatusError {
+		t.Errorf("Expected Error status, got %s", results[0].Status())
+	}
+}
+
+func TestCollectOptionsFromConfig(t *testing.T) {
+	opts := CollectOptionsFromConfig(&models.WatchdogConfig{})
+
+	if opts.MaxConcurrentClusters != DefaultMaxConcurrentClusters {
+		t.Errorf("Expected default MaxConcurrentClusters, got %d", opts.MaxConcurrentClusters)
+	}
+	if opts.MaxConcurrentNamespaces != DefaultMaxConcurrentNamespaces {
+		t.Errorf("Expected default MaxConcurrentNamespaces, got %d", opts.MaxConcurrentNamespaces)
+	}
+	if opts.ClusterTimeout != DefaultClusterTimeout {
+		t.Errorf("Expected default ClusterTimeout, got %s", opts.ClusterTimeout)
+	}
+
+	opts = CollectOptionsFromConfig(&models.WatchdogConfig{
+		MaxConcurrentClusters: 2,
+		ClusterTimeoutSeconds: 5,
+		PartialResults:        true,
+	})
+
+	if opts.MaxConcurrentClusters != 2 || opts.ClusterTimeout != 5*time.Second || !opts.PartialResults {
+		t.Errorf("Unexpected options from config: %+v", opts)
+	}
+}
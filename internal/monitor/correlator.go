@@ -0,0 +1,243 @@
+package monitor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// DefaultCorrelationWindow usado quando a config não define correlation_window_minutes
+const DefaultCorrelationWindow = 10 * time.Minute
+
+// CausalRule sequência de anomalias em que cada uma tende a causar a seguinte
+type CausalRule struct {
+	Name     string
+	Sequence []models.AnomalyType
+}
+
+// DefaultCausalRules regras causais conhecidas de HPAs
+func DefaultCausalRules() []CausalRule {
+	return []CausalRule{
+		{Name: "cpu-saturation", Sequence: []models.AnomalyType{models.AnomalyCPUSpike, models.AnomalyReplicaSpike, models.AnomalyMaxedOut}},
+		{Name: "memory-saturation", Sequence: []models.AnomalyType{models.AnomalyMemorySpike, models.AnomalyReplicaSpike, models.AnomalyMaxedOut}},
+		{Name: "max-lowered", Sequence: []models.AnomalyType{models.AnomalyHPAConfigChange, models.AnomalyMaxedOut}},
+		{Name: "resource-change", Sequence: []models.AnomalyType{models.AnomalyResourceChange, models.AnomalyReplicaSpike, models.AnomalyMaxedOut}},
+		{Name: "capacity-exhausted", Sequence: []models.AnomalyType{models.AnomalyMaxedOut, models.AnomalyHighLatency, models.AnomalyHighErrorRate}},
+	}
+}
+
+// Correlator agrupa alertas relacionados em incidentes. Cada alerta entra em um só incidente,
+// pela regra mais específica que o liga a outro alerta ativo com menos de Window de distância:
+//  1. mesmo HPA (watchdog e Alertmanager do mesmo workload); regras causais definem a causa
+//  2. mesmo namespace e mesmo sintoma (tipo de anomalia ou alertname), em HPAs diferentes
+//  3. mesmo cluster e mesmo sintoma, em namespaces diferentes
+//
+// Alertas sem HPA (ex: Alertmanager de namespace ou de cluster) só se ligam pelo sintoma:
+// não agrupam todos os alertas do escopo em um único incidente.
+type Correlator struct {
+	Window time.Duration
+	Rules  []CausalRule
+}
+
+// NewCorrelator cria um correlator com as regras causais padrão
+func NewCorrelator(window time.Duration) *Correlator {
+	if window <= 0 {
+		window = DefaultCorrelationWindow
+	}
+	return &Correlator{Window: window, Rules: DefaultCausalRules()}
+}
+
+// CorrelatorFromConfig cria o correlator da config (nil se auto_correlate estiver desligado)
+func CorrelatorFromConfig(cfg *models.WatchdogConfig) *Correlator {
+	if !cfg.AutoCorrelate {
+		return nil
+	}
+	return NewCorrelator(time.Duration(cfg.CorrelationWindowMinutes) * time.Minute)
+}
+
+// Correlate agrupa os alertas em incidentes (grupos com pelo menos 2 alertas).
+// Custo O(n log n): uma ordenação e uma passada por regra de ligação.
+func (c *Correlator) Correlate(alerts []models.UnifiedAlert) []models.Incident {
+	sorted := make([]models.UnifiedAlert, len(alerts))
+	copy(sorted, alerts)
+	sort.Slice(sorted, func(i, j int) bool {
+		if !sorted[i].Timestamp.Equal(sorted[j].Timestamp) {
+			return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	assigned := make([]bool, len(sorted))
+	var incidents []models.Incident
+
+	for _, key := range []func(*models.UnifiedAlert) string{hpaLinkKey, namespaceLinkKey, clusterLinkKey} {
+		for _, group := range c.group(sorted, assigned, key) {
+			incidents = append(incidents, c.incident(group))
+		}
+	}
+
+	sort.SliceStable(incidents, func(i, j int) bool {
+		return incidents[i].Start.Before(incidents[j].Start)
+	})
+	return incidents
+}
+
+// linkGroup alertas com a mesma chave de ligação e o fim do período ativo mais tardio
+type linkGroup struct {
+	indexes []int
+	end     time.Time
+}
+
+// group agrupa (em ordem cronológica) os alertas ainda sem incidente que têm a mesma chave e
+// estão a menos de Window do grupo. Marca os alertas dos grupos com 2+ alertas como atribuídos.
+func (c *Correlator) group(sorted []models.UnifiedAlert, assigned []bool, key func(*models.UnifiedAlert) string) [][]*models.UnifiedAlert {
+	open := make(map[string]*linkGroup)
+	var keys []string
+	var groups [][]*models.UnifiedAlert
+
+	flush := func(g *linkGroup) {
+		if len(g.indexes) < 2 {
+			return
+		}
+		group := make([]*models.UnifiedAlert, len(g.indexes))
+		for i, index := range g.indexes {
+			assigned[index] = true
+			group[i] = &sorted[index]
+		}
+		groups = append(groups, group)
+	}
+
+	for i := range sorted {
+		if assigned[i] {
+			continue
+		}
+		k := key(&sorted[i])
+		if k == "" {
+			continue
+		}
+
+		start, end := activePeriod(&sorted[i])
+		g, exists := open[k]
+		if exists && start.After(g.end.Add(c.Window)) {
+			flush(g)
+			exists = false
+		}
+		if !exists {
+			g = &linkGroup{}
+			open[k] = g
+			keys = append(keys, k)
+		}
+
+		g.indexes = append(g.indexes, i)
+		if end.After(g.end) {
+			g.end = end
+		}
+	}
+
+	for _, k := range keys {
+		if g, exists := open[k]; exists {
+			flush(g)
+			delete(open, k)
+		}
+	}
+
+	return groups
+}
+
+// incident monta o incidente de um grupo (alertas em ordem cronológica)
+func (c *Correlator) incident(group []*models.UnifiedAlert) models.Incident {
+	incident := models.Incident{
+		ID:    group[0].ID,
+		Start: group[0].Timestamp,
+	}
+
+	for _, alert := range group {
+		incident.Alerts = append(incident.Alerts, alert.ID)
+		if alert.Severity > incident.Severity {
+			incident.Severity = alert.Severity
+		}
+
+		name := chainName(alert)
+		if n := len(incident.Chain); n == 0 || incident.Chain[n-1] != name {
+			incident.Chain = append(incident.Chain, name)
+		}
+	}
+
+	// Regra causal mais longa define a causa provável (raiz do incidente)
+	bestLength := 1
+	for _, rule := range c.Rules {
+		cause, length := matchRule(rule, group)
+		if length > bestLength {
+			bestLength = length
+			incident.ID = cause.ID
+			incident.Rule = rule.Name
+		}
+	}
+
+	return incident
+}
+
+// matchRule retorna o alerta da causa e quantos passos da regra aparecem em ordem no grupo
+func matchRule(rule CausalRule, group []*models.UnifiedAlert) (*models.UnifiedAlert, int) {
+	var cause *models.UnifiedAlert
+	step := 0
+
+	for _, alert := range group {
+		if step == len(rule.Sequence) {
+			break
+		}
+		if alert.Source == models.AlertSourceWatchdog && alert.Type == rule.Sequence[step] {
+			if step == 0 {
+				cause = alert
+			}
+			step++
+		}
+	}
+
+	return cause, step
+}
+
+// activePeriod início e fim (resolução ou última vez visto) de um alerta
+func activePeriod(a *models.UnifiedAlert) (time.Time, time.Time) {
+	end := a.LastSeen
+	if a.ResolvedAt != nil {
+		end = *a.ResolvedAt
+	}
+	if end.Before(a.Timestamp) {
+		end = a.Timestamp
+	}
+	return a.Timestamp, end
+}
+
+// hpaLinkKey liga alertas do mesmo HPA (vazio = alerta sem HPA)
+func hpaLinkKey(a *models.UnifiedAlert) string {
+	if a.HPAName == "" {
+		return ""
+	}
+	return models.HPAKey(a.Cluster, a.Namespace, a.HPAName)
+}
+
+// namespaceLinkKey liga alertas do mesmo namespace com o mesmo sintoma
+func namespaceLinkKey(a *models.UnifiedAlert) string {
+	if a.Namespace == "" {
+		return ""
+	}
+	return a.Cluster + "/" + a.Namespace + "/" + chainName(a)
+}
+
+// clusterLinkKey liga alertas do mesmo cluster com o mesmo sintoma
+func clusterLinkKey(a *models.UnifiedAlert) string {
+	return a.Cluster + "/" + chainName(a)
+}
+
+// chainName nome do alerta na cadeia do incidente
+func chainName(a *models.UnifiedAlert) string {
+	if a.Source == models.AlertSourceAlertmanager {
+		if a.AlertName != "" {
+			return a.AlertName
+		}
+		return a.Source.String()
+	}
+	return a.Type.String()
+}
@@ -0,0 +1,214 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// newCorrelationAlert cria um alerta já com ID, início e LastSeen
+func newCorrelationAlert(hpa string, anomaly models.AnomalyType, start time.Time) models.UnifiedAlert {
+	alert := newTestAlert(hpa, anomaly, models.SeverityWarning)
+	alert.ID = AlertID(&alert)
+	alert.Timestamp = start
+	alert.LastSeen = start
+	return alert
+}
+
+func TestCorrelatorCausalChain(t *testing.T) {
+	start := time.Now()
+	c := NewCorrelator(10 * time.Minute)
+
+	maxed := newCorrelationAlert("api", models.AnomalyMaxedOut, start.Add(4*time.Minute))
+	maxed.Severity = models.SeverityCritical
+
+	alerts := []models.UnifiedAlert{
+		maxed,
+		newCorrelationAlert("api", models.AnomalyReplicaSpike, start.Add(2*time.Minute)),
+		newCorrelationAlert("api", models.AnomalyTargetMiss, start.Add(-time.Minute)),
+		newCorrelationAlert("api", models.AnomalyCPUSpike, start),
+		newCorrelationAlert("worker", models.AnomalyCPUSpike, start), // outro HPA: não relacionado
+	}
+
+	incidents := c.Correlate(alerts)
+	if len(incidents) != 1 {
+		t.Fatalf("Expected 1 incident, got %+v", incidents)
+	}
+
+	incident := incidents[0]
+	if incident.ID != "test-cluster/production/api/CPUSpike" || incident.Rule != "cpu-saturation" {
+		t.Errorf("Expected CPUSpike as root cause via cpu-saturation, got %s (%s)", incident.ID, incident.Rule)
+	}
+	if chain := incident.ChainString(); chain != "TargetMiss → CPUSpike → ReplicaSpike → MaxedOut" {
+		t.Errorf("Unexpected chain %q", chain)
+	}
+	if len(incident.Alerts) != 4 || incident.Severity != models.SeverityCritical {
+		t.Errorf("Expected 4 alerts with Critical severity, got %+v", incident)
+	}
+	if !incident.Start.Equal(start.Add(-time.Minute)) {
+		t.Errorf("Expected incident to start with the first alert, got %s", incident.Start)
+	}
+}
+
+func TestCorrelatorWindow(t *testing.T) {
+	start := time.Now()
+	c := NewCorrelator(5 * time.Minute)
+
+	old := newCorrelationAlert("api", models.AnomalyCPUSpike, start)
+	resolved := start.Add(time.Minute)
+	old.ResolvedAt = &resolved
+
+	// Começou 4 min após a resolução: dentro da janela
+	near := newCorrelationAlert("api", models.AnomalyReplicaSpike, start.Add(5*time.Minute))
+	if incidents := c.Correlate([]models.UnifiedAlert{old, near}); len(incidents) != 1 {
+		t.Errorf("Expected alerts within window to correlate, got %+v", incidents)
+	}
+
+	// Começou 9 min após a resolução: fora da janela
+	far := newCorrelationAlert("api", models.AnomalyReplicaSpike, start.Add(10*time.Minute))
+	if incidents := c.Correlate([]models.UnifiedAlert{old, far}); len(incidents) != 0 {
+		t.Errorf("Expected alerts outside window not to correlate, got %+v", incidents)
+	}
+}
+
+func TestCorrelatorAlertmanagerScopes(t *testing.T) {
+	start := time.Now()
+	c := NewCorrelator(10 * time.Minute)
+
+	// Alertmanager no mesmo workload
+	workload := models.UnifiedAlert{
+		Source:      models.AlertSourceAlertmanager,
+		AlertName:   "KubeHpaMaxedOut",
+		Fingerprint: "f1",
+		Cluster:     "test-cluster",
+		Namespace:   "production",
+		HPAName:     "api",
+		Timestamp:   start.Add(time.Minute),
+	}
+	workload.ID = AlertID(&workload)
+
+	// Alertmanager de namespace (sem label de HPA): só se liga pelo sintoma
+	namespace := models.UnifiedAlert{
+		Source:      models.AlertSourceAlertmanager,
+		AlertName:   "KubeQuotaExceeded",
+		Fingerprint: "f2",
+		Cluster:     "test-cluster",
+		Namespace:   "production",
+		Timestamp:   start.Add(2 * time.Minute),
+	}
+	namespace.ID = AlertID(&namespace)
+
+	api := newCorrelationAlert("api", models.AnomalyMaxedOut, start)
+	worker := newCorrelationAlert("worker", models.AnomalyScalingStuck, start)
+	other := newCorrelationAlert("api", models.AnomalyCPUSpike, start)
+	other.Namespace = "staging"
+
+	incidents := c.Correlate([]models.UnifiedAlert{api, workload})
+	if len(incidents) != 1 || incidents[0].ChainString() != "MaxedOut → KubeHpaMaxedOut" {
+		t.Errorf("Expected Alertmanager alert linked to the watchdog alert of the same HPA, got %+v", incidents)
+	}
+
+	if incidents := c.Correlate([]models.UnifiedAlert{api, worker, namespace, other}); len(incidents) != 0 {
+		t.Errorf("Expected namespace alert not to group unrelated HPAs, got %+v", incidents)
+	}
+}
+
+func TestCorrelatorNamespaceAndClusterLinks(t *testing.T) {
+	start := time.Now()
+	c := NewCorrelator(10 * time.Minute)
+
+	// Mesmo sintoma em HPAs do mesmo namespace
+	api := newCorrelationAlert("api", models.AnomalyMaxedOut, start)
+	worker := newCorrelationAlert("worker", models.AnomalyMaxedOut, start.Add(time.Minute))
+	incidents := c.Correlate([]models.UnifiedAlert{api, worker})
+	if len(incidents) != 1 || len(incidents[0].Alerts) != 2 || incidents[0].ChainString() != "MaxedOut" {
+		t.Fatalf("Expected namespace incident for MaxedOut in two HPAs, got %+v", incidents)
+	}
+
+	// Mesmo sintoma em namespaces diferentes do cluster
+	staging := newCorrelationAlert("api", models.AnomalyMaxedOut, start.Add(2*time.Minute))
+	staging.Namespace = "staging"
+	staging.ID = AlertID(&staging)
+	cpu := newCorrelationAlert("worker", models.AnomalyCPUSpike, start)
+	cpu.Namespace = "staging"
+	cpu.ID = AlertID(&cpu)
+	incidents = c.Correlate([]models.UnifiedAlert{api, staging, cpu})
+	if len(incidents) != 1 || len(incidents[0].Alerts) != 2 {
+		t.Fatalf("Expected cluster incident for MaxedOut in two namespaces (without CPUSpike), got %+v", incidents)
+	}
+
+	// Alerta de cluster (sem namespace) não agrupa todos os alertas do cluster
+	nodes := models.UnifiedAlert{
+		Source:      models.AlertSourceAlertmanager,
+		AlertName:   "KubeNodeNotReady",
+		Fingerprint: "n1",
+		Cluster:     "test-cluster",
+		Timestamp:   start,
+	}
+	nodes.ID = AlertID(&nodes)
+	incidents = c.Correlate([]models.UnifiedAlert{nodes, cpu, newCorrelationAlert("api", models.AnomalyScalingStuck, start)})
+	if len(incidents) != 0 {
+		t.Errorf("Expected cluster-level alert not to link unrelated alerts, got %+v", incidents)
+	}
+
+	// Regra mais específica vence: api já está no incidente do próprio HPA
+	incidents = c.Correlate([]models.UnifiedAlert{
+		newCorrelationAlert("api", models.AnomalyCPUSpike, start),
+		api,
+		worker,
+	})
+	if len(incidents) != 1 || len(incidents[0].Alerts) != 2 || incidents[0].ID != "test-cluster/production/api/CPUSpike" {
+		t.Errorf("Expected only the api HPA incident, got %+v", incidents)
+	}
+}
+
+func TestAlertStoreCorrelation(t *testing.T) {
+	store := NewAlertStore(DefaultAlertStoreOptions())
+	store.SetCorrelator(NewCorrelator(DefaultCorrelationWindow))
+
+	now := time.Now()
+	scope := models.HPAKey("test-cluster", "production", "api")
+
+	store.Sync(scope, []models.UnifiedAlert{newTestAlert("api", models.AnomalyCPUSpike, models.SeverityWarning)}, now)
+	store.Sync(scope, []models.UnifiedAlert{
+		newTestAlert("api", models.AnomalyCPUSpike, models.SeverityWarning),
+		newTestAlert("api", models.AnomalyReplicaSpike, models.SeverityWarning),
+	}, now.Add(time.Minute))
+
+	// Sync não correlaciona: uma vez por lote (Correlate) ou scan (Prune)
+	if len(store.Incidents()) != 0 {
+		t.Error("Expected no correlation on Sync")
+	}
+	store.Prune(now.Add(time.Minute))
+
+	incidents := store.Incidents()
+	if len(incidents) != 1 || incidents[0].Rule != "cpu-saturation" {
+		t.Fatalf("Expected 1 cpu-saturation incident, got %+v", incidents)
+	}
+
+	alert, _ := store.Get("test-cluster/production/api/ReplicaSpike")
+	if alert.IncidentID != "test-cluster/production/api/CPUSpike" {
+		t.Errorf("Expected IncidentID of the root cause, got %q", alert.IncidentID)
+	}
+	if len(alert.Correlation) != 1 || alert.Correlation[0] != "test-cluster/production/api/CPUSpike" {
+		t.Errorf("Expected correlation with CPUSpike, got %v", alert.Correlation)
+	}
+
+	// Sem correlator, nada é correlacionado
+	store.SetCorrelator(nil)
+	if alert, _ := store.Get(alert.ID); alert.IncidentID != "" || len(store.Incidents()) != 0 {
+		t.Errorf("Expected correlation cleared without correlator, got %+v", alert)
+	}
+}
+
+func TestCorrelatorFromConfig(t *testing.T) {
+	if c := CorrelatorFromConfig(&models.WatchdogConfig{AutoCorrelate: false}); c != nil {
+		t.Error("Expected no correlator when auto_correlate is disabled")
+	}
+
+	c := CorrelatorFromConfig(&models.WatchdogConfig{AutoCorrelate: true})
+	if c == nil || c.Window != DefaultCorrelationWindow {
+		t.Errorf("Expected default window, got %+v", c)
+	}
+}
@@ -3,6 +3,8 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
@@ -11,8 +13,13 @@ import (
 
 // MonitoringSession representa uma sessão de monitoramento ativa
 type MonitoringSession struct {
-	k8sClients     map[string]*K8sClient // cluster -> client
+	k8sClients     map[string]*K8sClient        // cluster -> client
+	informers      map[string]*ClusterInformers // cluster -> informers (clusters sem informer usam polling)
+	handlers       []HPAEventHandler
+	collectOpts    CollectOptions
 	portForwardMgr *PortForwardManager
+	started        bool // StartInformers já executado (clusters adicionados depois também ganham informers)
+	mu             sync.RWMutex
 	ctx            context.Context
 	cancel         context.CancelFunc
 }
@@ -23,6 +30,8 @@ func NewMonitoringSession(clusters []*models.ClusterInfo) (*MonitoringSession, e
 
 	session := &MonitoringSession{
 		k8sClients:     make(map[string]*K8sClient),
+		informers:      make(map[string]*ClusterInformers),
+		collectOpts:    DefaultCollectOptions(),
 		portForwardMgr: NewPortForwardManager(DefaultLocalPort),
 		ctx:            ctx,
 		cancel:         cancel,
@@ -30,26 +39,14 @@ func NewMonitoringSession(clusters []*models.ClusterInfo) (*MonitoringSession, e
 
 	// Inicializa clients para cada cluster
 	for _, cluster := range clusters {
-		client, err := NewK8sClient(cluster)
+		client, err := session.connect(cluster)
 		if err != nil {
-			log.Warn().
-				Err(err).
-				Str("cluster", cluster.Name).
-				Msg("Failed to create K8s client for cluster, skipping")
-			continue
-		}
-
-		// Testa conexão
-		testCtx, testCancel := context.WithTimeout(ctx, 10*time.Second)
-		if err := client.TestConnection(testCtx); err != nil {
-			testCancel()
 			log.Warn().
 				Err(err).
 				Str("cluster", cluster.Name).
 				Msg("Failed to connect to cluster, skipping")
 			continue
 		}
-		testCancel()
 
 		session.k8sClients[cluster.Name] = client
 	}
@@ -86,75 +83,168 @@ func (s *MonitoringSession) heartbeatLoop() {
 	}
 }
 
-// CollectAllHPAs coleta snapshots de todos os HPAs de todos os clusters
-func (s *MonitoringSession) CollectAllHPAs() ([]*models.HPASnapshot, error) {
-	var allSnapshots []*models.HPASnapshot
+// AddEventHandler registra um handler para eventos de HPA de todos os clusters.
+// Deve ser chamado antes de StartInformers.
+func (s *MonitoringSession) AddEventHandler(handler HPAEventHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers = append(s.handlers, handler)
+}
 
+// StartInformers inicia shared informers (HPA, Deployment, StatefulSet) em cada cluster.
+// Clusters cujo cache não sincroniza continuam usando polling via API.
+// Os clusters sincronizam em paralelo e sem o lock da sessão (cada um pode levar até InformerSyncTimeout).
+func (s *MonitoringSession) StartInformers() {
+	s.mu.Lock()
+	s.started = true
+	clients := make(map[string]*K8sClient, len(s.k8sClients))
 	for clusterName, client := range s.k8sClients {
-		log.Debug().
+		clients[clusterName] = client
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for clusterName, client := range clients {
+		wg.Add(1)
+		go func(clusterName string, client *K8sClient) {
+			defer wg.Done()
+			s.publishInformers(clusterName, client, s.startInformers(clusterName, client))
+		}(clusterName, client)
+	}
+	wg.Wait()
+
+	s.mu.RLock()
+	withInformers := len(s.informers)
+	s.mu.RUnlock()
+
+	log.Info().
+		Int("clusters", len(clients)).
+		Int("with_informers", withInformers).
+		Msg("Informers initialized")
+}
+
+// publishInformers registra os informers do cluster. Se o cluster saiu da sessão (ou foi
+// trocado) durante a sincronização, os informers são parados e descartados.
+func (s *MonitoringSession) publishInformers(clusterName string, client *K8sClient, ci *ClusterInformers) {
+	if ci == nil {
+		return
+	}
+
+	s.mu.Lock()
+	current, exists := s.k8sClients[clusterName]
+	if exists && current == client {
+		s.informers[clusterName] = ci
+	}
+	s.mu.Unlock()
+
+	if !exists || current != client {
+		ci.Stop()
+	}
+}
+
+// startInformers inicia os informers de um cluster (nil = cache não sincronizou, usa polling)
+func (s *MonitoringSession) startInformers(clusterName string, client *K8sClient) *ClusterInformers {
+	s.mu.RLock()
+	handlers := append([]HPAEventHandler(nil), s.handlers...)
+	s.mu.RUnlock()
+
+	ci := NewClusterInformers(client)
+	for _, handler := range handlers {
+		ci.AddEventHandler(handler)
+	}
+
+	if err := ci.Start(s.ctx); err != nil {
+		log.Warn().
+			Err(err).
 			Str("cluster", clusterName).
-			Msg("Collecting HPAs from cluster")
+			Msg("Failed to start informers, falling back to polling")
+		return nil
+	}
 
-		// Lista namespaces
-		ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
-		namespaces, err := client.ListNamespaces(ctx, []string{})
-		cancel()
+	return ci
+}
 
-		if err != nil {
-			log.Error().
-				Err(err).
-				Str("cluster", clusterName).
-				Msg("Failed to list namespaces")
-			continue
-		}
+// connect cria o client do cluster e testa a conexão
+func (s *MonitoringSession) connect(cluster *models.ClusterInfo) (*K8sClient, error) {
+	client, err := NewK8sClient(cluster)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create K8s client: %w", err)
+	}
 
-		// Para cada namespace, lista HPAs
-		for _, namespace := range namespaces {
-			ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
-			hpas, err := client.ListHPAs(ctx, namespace)
-			cancel()
+	testCtx, testCancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer testCancel()
 
-			if err != nil {
-				log.Warn().
-					Err(err).
-					Str("cluster", clusterName).
-					Str("namespace", namespace).
-					Msg("Failed to list HPAs")
-				continue
-			}
+	if err := client.TestConnection(testCtx); err != nil {
+		return nil, err
+	}
 
-			// Coleta snapshot de cada HPA
-			for _, hpa := range hpas {
-				ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
-				snapshot, err := client.CollectHPASnapshot(ctx, &hpa)
-				cancel()
-
-				if err != nil {
-					log.Warn().
-						Err(err).
-						Str("cluster", clusterName).
-						Str("namespace", namespace).
-						Str("hpa", hpa.Name).
-						Msg("Failed to collect HPA snapshot")
-					continue
-				}
-
-				allSnapshots = append(allSnapshots, snapshot)
-			}
-		}
+	return client, nil
+}
+
+// AddCluster conecta um cluster à sessão em execução (ex: removido de clusters.exclude no reload).
+// Se os informers já foram iniciados, o cluster também ganha informers.
+func (s *MonitoringSession) AddCluster(cluster *models.ClusterInfo) error {
+	s.mu.RLock()
+	_, exists := s.k8sClients[cluster.Name]
+	s.mu.RUnlock()
+
+	if exists {
+		return fmt.Errorf("cluster %s already monitored", cluster.Name)
 	}
 
-	log.Info().
-		Int("total_snapshots", len(allSnapshots)).
-		Msg("HPA collection complete")
+	client, err := s.connect(cluster)
+	if err != nil {
+		return err
+	}
 
-	return allSnapshots, nil
+	return s.addClient(cluster.Name, client)
+}
+
+// addClient publica o client na sessão (scans já coletam dele) e, se os informers já foram
+// iniciados, sincroniza os do cluster fora do lock
+func (s *MonitoringSession) addClient(clusterName string, client *K8sClient) error {
+	s.mu.Lock()
+	if _, exists := s.k8sClients[clusterName]; exists {
+		s.mu.Unlock()
+		return fmt.Errorf("cluster %s already monitored", clusterName)
+	}
+	s.k8sClients[clusterName] = client
+	started := s.started
+	s.mu.Unlock()
+
+	// Sincronização fora do lock: leitores da sessão não esperam o cache do novo cluster
+	if started {
+		s.publishInformers(clusterName, client, s.startInformers(clusterName, client))
+	}
+
+	log.Info().Str("cluster", clusterName).Msg("Cluster added to monitoring session")
+	return nil
+}
+
+// RemoveCluster para os informers e remove o cluster da sessão. Retorna false se não existia.
+func (s *MonitoringSession) RemoveCluster(clusterName string) bool {
+	s.mu.Lock()
+	_, exists := s.k8sClients[clusterName]
+	ci := s.informers[clusterName]
+	delete(s.k8sClients, clusterName)
+	delete(s.informers, clusterName)
+	s.mu.Unlock()
+
+	if ci != nil {
+		ci.Stop()
+	}
+
+	if exists {
+		log.Info().Str("cluster", clusterName).Msg("Cluster removed from monitoring session")
+	}
+	return exists
 }
 
 // SetupPrometheusPortForward configura port-forward para Prometheus em um cluster
 func (s *MonitoringSession) SetupPrometheusPortForward(clusterName, namespace string, service string) (string, error) {
 	// Verifica se cluster existe
-	if _, exists := s.k8sClients[clusterName]; !exists {
+	if _, exists := s.GetClient(clusterName); !exists {
 		return "", fmt.Errorf("cluster %s not found", clusterName)
 	}
 
@@ -172,6 +262,30 @@ func (s *MonitoringSession) SetupPrometheusPortForward(clusterName, namespace st
 	return endpoint, nil
 }
 
+// GetClient retorna o client de um cluster da sessão
+func (s *MonitoringSession) GetClient(clusterName string) (*K8sClient, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	client, exists := s.k8sClients[clusterName]
+	return client, exists
+}
+
+// Clusters retorna cópias das informações dos clusters conectados
+func (s *MonitoringSession) Clusters() []models.ClusterInfo {
+	s.mu.RLock()
+	clusters := make([]models.ClusterInfo, 0, len(s.k8sClients))
+	for _, client := range s.k8sClients {
+		clusters = append(clusters, *client.GetClusterInfo())
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].Name < clusters[j].Name
+	})
+	return clusters
+}
+
 // GetPortForwardStatus retorna status de todos os port-forwards ativos
 func (s *MonitoringSession) GetPortForwardStatus() map[string]interface{} {
 	return s.portForwardMgr.GetStatus()
@@ -184,6 +298,13 @@ func (s *MonitoringSession) Shutdown() {
 	// Para heartbeat loop
 	s.cancel()
 
+	// Para informers
+	s.mu.RLock()
+	for _, ci := range s.informers {
+		ci.Stop()
+	}
+	s.mu.RUnlock()
+
 	// Shutdown port-forward manager
 	if s.portForwardMgr != nil {
 		s.portForwardMgr.Shutdown()
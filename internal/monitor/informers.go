@@ -0,0 +1,305 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/rs/zerolog/log"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	autoscalinglisters "k8s.io/client-go/listers/autoscaling/v2"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// InformerSyncTimeout tempo máximo para o cache inicial dos informers sincronizar
+	InformerSyncTimeout = 60 * time.Second
+)
+
+// HPAEventType tipo de evento recebido dos informers
+type HPAEventType int
+
+const (
+	HPAEventAdded HPAEventType = iota
+	HPAEventUpdated
+	HPAEventDeleted
+)
+
+func (e HPAEventType) String() string {
+	switch e {
+	case HPAEventAdded:
+		return "Added"
+	case HPAEventUpdated:
+		return "Updated"
+	case HPAEventDeleted:
+		return "Deleted"
+	default:
+		return "Unknown"
+	}
+}
+
+// HPAEvent evento de mudança em um HPA (ou no workload alvo dele)
+type HPAEvent struct {
+	Type      HPAEventType
+	Cluster   string
+	Namespace string
+	Name      string
+	Snapshot  *models.HPASnapshot // nil em HPAEventDeleted
+}
+
+// HPAEventHandler recebe eventos dos informers.
+// É chamado na goroutine do informer, então não deve bloquear.
+type HPAEventHandler func(event HPAEvent)
+
+// ClusterInformers mantém caches locais (shared informers) de HPAs e workloads de um cluster
+type ClusterInformers struct {
+	client       *K8sClient
+	factory      informers.SharedInformerFactory
+	hpaLister    autoscalinglisters.HorizontalPodAutoscalerLister
+	deployLister appslisters.DeploymentLister
+	stsLister    appslisters.StatefulSetLister
+	synced       []cache.InformerSynced
+	handlers     []HPAEventHandler
+	mu           sync.RWMutex
+	stopCh       chan struct{}
+	stopOnce     sync.Once
+}
+
+// NewClusterInformers cria os informers de HPA, Deployment e StatefulSet de um cluster
+func NewClusterInformers(client *K8sClient) *ClusterInformers {
+	factory := informers.NewSharedInformerFactory(client.Clientset, 0)
+
+	hpaInformer := factory.Autoscaling().V2().HorizontalPodAutoscalers()
+	deployInformer := factory.Apps().V1().Deployments()
+	stsInformer := factory.Apps().V1().StatefulSets()
+
+	ci := &ClusterInformers{
+		client:       client,
+		factory:      factory,
+		hpaLister:    hpaInformer.Lister(),
+		deployLister: deployInformer.Lister(),
+		stsLister:    stsInformer.Lister(),
+		synced: []cache.InformerSynced{
+			hpaInformer.Informer().HasSynced,
+			deployInformer.Informer().HasSynced,
+			stsInformer.Informer().HasSynced,
+		},
+		stopCh: make(chan struct{}),
+	}
+
+	_, _ = hpaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerDetailedFuncs{
+		AddFunc:    ci.onHPAAdd,
+		UpdateFunc: ci.onHPAUpdate,
+		DeleteFunc: ci.onHPADelete,
+	})
+
+	_, _ = deployInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldDeploy, ok1 := oldObj.(*appsv1.Deployment)
+			newDeploy, ok2 := newObj.(*appsv1.Deployment)
+			if !ok1 || !ok2 || reflect.DeepEqual(oldDeploy.Spec.Template, newDeploy.Spec.Template) {
+				return
+			}
+			ci.onWorkloadChange(newDeploy.Namespace, "Deployment", newDeploy.Name)
+		},
+	})
+
+	_, _ = stsInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldSts, ok1 := oldObj.(*appsv1.StatefulSet)
+			newSts, ok2 := newObj.(*appsv1.StatefulSet)
+			if !ok1 || !ok2 || reflect.DeepEqual(oldSts.Spec.Template, newSts.Spec.Template) {
+				return
+			}
+			ci.onWorkloadChange(newSts.Namespace, "StatefulSet", newSts.Name)
+		},
+	})
+
+	return ci
+}
+
+// AddEventHandler registra um handler para eventos de HPA
+func (ci *ClusterInformers) AddEventHandler(handler HPAEventHandler) {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	ci.handlers = append(ci.handlers, handler)
+}
+
+// Start inicia os informers e aguarda o cache inicial sincronizar
+func (ci *ClusterInformers) Start(ctx context.Context) error {
+	ci.factory.Start(ci.stopCh)
+
+	syncCtx, cancel := context.WithTimeout(ctx, InformerSyncTimeout)
+	defer cancel()
+
+	if !cache.WaitForCacheSync(syncCtx.Done(), ci.synced...) {
+		ci.Stop()
+		return fmt.Errorf("informer cache sync failed for cluster %s", ci.client.cluster.Name)
+	}
+
+	ci.client.setInformers(ci)
+
+	log.Info().
+		Str("cluster", ci.client.cluster.Name).
+		Msg("Informers started and synced")
+
+	return nil
+}
+
+// Stop encerra os informers
+func (ci *ClusterInformers) Stop() {
+	ci.stopOnce.Do(func() {
+		ci.client.dropInformers(ci)
+		close(ci.stopCh)
+		ci.factory.Shutdown()
+	})
+}
+
+// HasSynced retorna se todos os caches estão sincronizados
+func (ci *ClusterInformers) HasSynced() bool {
+	for _, synced := range ci.synced {
+		if !synced() {
+			return false
+		}
+	}
+	return true
+}
+
+// ListHPAs lista HPAs do cache local (namespace vazio = todos os namespaces)
+func (ci *ClusterInformers) ListHPAs(namespace string) ([]*autoscalingv2.HorizontalPodAutoscaler, error) {
+	if namespace == "" {
+		return ci.hpaLister.List(labels.Everything())
+	}
+	return ci.hpaLister.HorizontalPodAutoscalers(namespace).List(labels.Everything())
+}
+
+// GetPodTemplate retorna o pod template do workload alvo a partir do cache local
+func (ci *ClusterInformers) GetPodTemplate(namespace, kind, name string) (*corev1.PodTemplateSpec, error) {
+	switch kind {
+	case "Deployment":
+		deployment, err := ci.deployLister.Deployments(namespace).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get deployment %s/%s from cache: %w", namespace, name, err)
+		}
+		return &deployment.Spec.Template, nil
+
+	case "StatefulSet":
+		sts, err := ci.stsLister.StatefulSets(namespace).Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get statefulset %s/%s from cache: %w", namespace, name, err)
+		}
+		return &sts.Spec.Template, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported scale target kind: %s", kind)
+	}
+}
+
+// onHPAAdd ignora a lista inicial (já coberta pelo primeiro scan)
+func (ci *ClusterInformers) onHPAAdd(obj interface{}, isInInitialList bool) {
+	if isInInitialList {
+		return
+	}
+
+	if hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler); ok {
+		ci.emitSnapshot(HPAEventAdded, hpa)
+	}
+}
+
+// onHPAUpdate emite evento apenas quando o objeto mudou de fato
+func (ci *ClusterInformers) onHPAUpdate(oldObj, newObj interface{}) {
+	oldHPA, ok1 := oldObj.(*autoscalingv2.HorizontalPodAutoscaler)
+	newHPA, ok2 := newObj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok1 || !ok2 || oldHPA.ResourceVersion == newHPA.ResourceVersion {
+		return
+	}
+
+	ci.emitSnapshot(HPAEventUpdated, newHPA)
+}
+
+// onHPADelete trata também tombstones (DeletedFinalStateUnknown)
+func (ci *ClusterInformers) onHPADelete(obj interface{}) {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = tombstone.Obj
+	}
+
+	hpa, ok := obj.(*autoscalingv2.HorizontalPodAutoscaler)
+	if !ok || isExcludedNamespace(hpa.Namespace) {
+		return
+	}
+
+	ci.emit(HPAEvent{
+		Type:      HPAEventDeleted,
+		Cluster:   ci.client.cluster.Name,
+		Namespace: hpa.Namespace,
+		Name:      hpa.Name,
+	})
+}
+
+// onWorkloadChange re-emite os HPAs cujo scale target é o workload alterado
+func (ci *ClusterInformers) onWorkloadChange(namespace, kind, name string) {
+	hpas, err := ci.ListHPAs(namespace)
+	if err != nil {
+		return
+	}
+
+	for _, hpa := range hpas {
+		ref := hpa.Spec.ScaleTargetRef
+		if ref.Kind == kind && ref.Name == name {
+			ci.emitSnapshot(HPAEventUpdated, hpa)
+		}
+	}
+}
+
+// emitSnapshot monta o snapshot a partir do cache e emite o evento
+func (ci *ClusterInformers) emitSnapshot(eventType HPAEventType, hpa *autoscalingv2.HorizontalPodAutoscaler) {
+	if isExcludedNamespace(hpa.Namespace) {
+		return
+	}
+
+	snapshot, err := ci.client.CollectHPASnapshot(context.Background(), hpa)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("cluster", ci.client.cluster.Name).
+			Str("namespace", hpa.Namespace).
+			Str("hpa", hpa.Name).
+			Msg("Failed to build snapshot from informer event")
+		return
+	}
+
+	ci.emit(HPAEvent{
+		Type:      eventType,
+		Cluster:   ci.client.cluster.Name,
+		Namespace: hpa.Namespace,
+		Name:      hpa.Name,
+		Snapshot:  snapshot,
+	})
+}
+
+// emit entrega o evento a todos os handlers registrados
+func (ci *ClusterInformers) emit(event HPAEvent) {
+	ci.mu.RLock()
+	handlers := ci.handlers
+	ci.mu.RUnlock()
+
+	log.Debug().
+		Str("cluster", event.Cluster).
+		Str("namespace", event.Namespace).
+		Str("hpa", event.Name).
+		Str("event", event.Type.String()).
+		Msg("HPA event received")
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
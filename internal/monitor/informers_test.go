@@ -0,0 +1,317 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+// newTestDeployment cria um deployment com requests de CPU
+func newTestDeployment(namespace, name, cpuRequest string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "app",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceCPU: resource.MustParse(cpuRequest),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newTestHPAWithTarget cria um HPA apontando para um deployment
+func newTestHPAWithTarget(namespace, name, deployment string) *autoscalingv2.HorizontalPodAutoscaler {
+	hpa := newTestHPA(namespace, name)
+	hpa.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+		Kind: "Deployment",
+		Name: deployment,
+	}
+	return hpa
+}
+
+// waitForEvent aguarda um evento no canal ou falha após timeout
+func waitForEvent(t *testing.T, events <-chan HPAEvent) HPAEvent {
+	t.Helper()
+
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for HPA event")
+		return HPAEvent{}
+	}
+}
+
+func TestClusterInformersCache(t *testing.T) {
+	clientset := fake.NewClientset(
+		newTestHPAWithTarget("production", "api", "api"),
+		newTestHPA("kube-system", "ignored"),
+		newTestDeployment("production", "api", "500m"),
+	)
+
+	client := &K8sClient{
+		Clientset: clientset,
+		cluster:   &models.ClusterInfo{Name: "test-cluster"},
+	}
+
+	ci, err := client.StartInformers(context.Background())
+	if err != nil {
+		t.Fatalf("StartInformers() error = %v", err)
+	}
+	defer ci.Stop()
+
+	if !ci.HasSynced() {
+		t.Error("Expected informers to be synced")
+	}
+
+	hpas, err := ci.ListHPAs("production")
+	if err != nil {
+		t.Fatalf("ListHPAs() error = %v", err)
+	}
+	if len(hpas) != 1 {
+		t.Fatalf("Expected 1 HPA in production, got %d", len(hpas))
+	}
+
+	// Snapshot deve usar o deployment do cache
+	snapshot, err := client.CollectHPASnapshot(context.Background(), hpas[0])
+	if err != nil {
+		t.Fatalf("CollectHPASnapshot() error = %v", err)
+	}
+	if snapshot.CPURequest != "500m" || snapshot.ResourcesUnknown {
+		t.Errorf("Expected CPURequest 500m from cache, got %q", snapshot.CPURequest)
+	}
+
+	// Coleta da sessão via cache ignora namespaces de sistema
+	session := &MonitoringSession{
+		k8sClients: map[string]*K8sClient{"test-cluster": client},
+		informers:  map[string]*ClusterInformers{"test-cluster": ci},
+		ctx:        context.Background(),
+		cancel:     func() {},
+	}
+
+	snapshots, err := session.CollectAllHPAs()
+	if err != nil {
+		t.Fatalf("CollectAllHPAs() error = %v", err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "api" {
+		t.Errorf("Expected only production/api snapshot, got %d snapshots", len(snapshots))
+	}
+}
+
+func TestClusterInformersEvents(t *testing.T) {
+	clientset := fake.NewClientset(
+		newTestHPAWithTarget("production", "api", "api"),
+		newTestDeployment("production", "api", "500m"),
+	)
+
+	client := &K8sClient{
+		Clientset: clientset,
+		cluster:   &models.ClusterInfo{Name: "test-cluster"},
+	}
+
+	events := make(chan HPAEvent, 10)
+	ci := NewClusterInformers(client)
+	ci.AddEventHandler(func(event HPAEvent) {
+		events <- event
+	})
+
+	if err := ci.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer ci.Stop()
+
+	ctx := context.Background()
+
+	// Update no HPA
+	hpa, _ := clientset.AutoscalingV2().HorizontalPodAutoscalers("production").Get(ctx, "api", metav1.GetOptions{})
+	hpa.Spec.MaxReplicas = 20
+	hpa.ResourceVersion = "2"
+	if _, err := clientset.AutoscalingV2().HorizontalPodAutoscalers("production").Update(ctx, hpa, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update HPA error = %v", err)
+	}
+
+	event := waitForEvent(t, events)
+	if event.Type != HPAEventUpdated || event.Snapshot == nil || event.Snapshot.MaxReplicas != 20 {
+		t.Errorf("Expected Updated event with MaxReplicas 20, got %+v", event)
+	}
+
+	// Update no deployment alvo re-emite o HPA
+	deployment := newTestDeployment("production", "api", "250m")
+	if _, err := clientset.AppsV1().Deployments("production").Update(ctx, deployment, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Update deployment error = %v", err)
+	}
+
+	event = waitForEvent(t, events)
+	if event.Type != HPAEventUpdated || event.Snapshot == nil || event.Snapshot.CPURequest != "250m" {
+		t.Errorf("Expected Updated event with CPURequest 250m, got %+v", event)
+	}
+
+	// Delete do HPA
+	if err := clientset.AutoscalingV2().HorizontalPodAutoscalers("production").Delete(ctx, "api", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Delete HPA error = %v", err)
+	}
+
+	event = waitForEvent(t, events)
+	if event.Type != HPAEventDeleted || event.Name != "api" || event.Snapshot != nil {
+		t.Errorf("Expected Deleted event for api, got %+v", event)
+	}
+}
+
+func TestWatcherProcessEvent(t *testing.T) {
+	session := newTestSession("test-cluster")
+	defer session.Shutdown()
+
+	watcher := NewWatcher(&models.WatchdogConfig{HistoryRetentionMinutes: 5}, session)
+
+	snapshot := &models.HPASnapshot{
+		Timestamp: time.Now(),
+		Cluster:   "test-cluster",
+		Namespace: "production",
+		Name:      "api",
+	}
+
+	watcher.processEvent(HPAEvent{Type: HPAEventAdded, Cluster: "test-cluster", Namespace: "production", Name: "api", Snapshot: snapshot})
+	if _, exists := watcher.GetTimeSeries(snapshot.Key()); !exists {
+		t.Fatal("Expected series to be created from Added event")
+	}
+
+	watcher.processEvent(HPAEvent{Type: HPAEventDeleted, Cluster: "test-cluster", Namespace: "production", Name: "api"})
+	if _, exists := watcher.GetTimeSeries(snapshot.Key()); exists {
+		t.Error("Expected series to be removed after Deleted event")
+	}
+}
+
+func TestSessionStartInformersWithoutLock(t *testing.T) {
+	session := newTestSession("fast")
+	session.informers = make(map[string]*ClusterInformers)
+	defer session.Shutdown()
+
+	// Cluster lento: a listagem de HPAs só responde após release
+	release := make(chan struct{})
+	slow := fake.NewClientset()
+	slow.PrependReactor("list", "horizontalpodautoscalers", func(k8stesting.Action) (bool, runtime.Object, error) {
+		<-release
+		return false, nil, nil
+	})
+	session.k8sClients["slow"] = &K8sClient{Clientset: slow, cluster: &models.ClusterInfo{Name: "slow"}}
+
+	done := make(chan struct{})
+	go func() {
+		session.StartInformers()
+		close(done)
+	}()
+
+	// Leitores da sessão não esperam a sincronização do cluster lento
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		session.mu.RLock()
+		_, fastReady := session.informers["fast"]
+		session.mu.RUnlock()
+		if fastReady {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected fast cluster informers to be published while slow cluster syncs")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if clusters := session.Clusters(); len(clusters) != 2 {
+		t.Errorf("Expected 2 clusters, got %d", len(clusters))
+	}
+
+	// Cluster removido durante a sincronização não tem informers publicados
+	session.RemoveCluster("slow")
+	close(release)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for StartInformers")
+	}
+
+	session.mu.RLock()
+	defer session.mu.RUnlock()
+	if _, exists := session.informers["slow"]; exists {
+		t.Error("Expected informers of removed cluster to be discarded")
+	}
+	if len(session.informers) != 1 {
+		t.Errorf("Expected 1 cluster with informers, got %d", len(session.informers))
+	}
+}
+
+func TestSessionAddClusterWhileScanning(t *testing.T) {
+	session := newTestSession("existing")
+	session.informers = make(map[string]*ClusterInformers)
+	defer session.Shutdown()
+
+	session.StartInformers()
+	session.AddEventHandler(func(HPAEvent) {})
+
+	// Scans coletam do cluster novo enquanto o Start dos informers publica o cache (go test -race)
+	stop := make(chan struct{})
+	scans := make(chan int)
+	go func() {
+		count := 0
+		for {
+			select {
+			case <-stop:
+				scans <- count
+				return
+			default:
+				session.CollectClusters()
+				count++
+			}
+		}
+	}()
+
+	client := &K8sClient{
+		Clientset: fake.NewClientset(
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}},
+			newTestHPAWithTarget("production", "api", "api"),
+			newTestDeployment("production", "api", "500m"),
+		),
+		cluster: &models.ClusterInfo{Name: "added"},
+	}
+	if err := session.addClient("added", client); err != nil {
+		t.Fatalf("addClient() error = %v", err)
+	}
+
+	close(stop)
+	if count := <-scans; count == 0 {
+		t.Error("Expected scans to run while the cluster was added")
+	}
+
+	if client.Informers() == nil {
+		t.Fatal("Expected added cluster to use informers")
+	}
+	snapshots, err := session.CollectAllHPAs()
+	if err != nil || len(snapshots) != 1 || snapshots[0].CPURequest != "500m" {
+		t.Errorf("Expected api snapshot from informers cache, got %d snapshots (err %v)", len(snapshots), err)
+	}
+
+	// Cluster removido: informers parados e client volta a usar a API
+	session.RemoveCluster("added")
+	if client.Informers() != nil {
+		t.Error("Expected stopped informers dropped from client")
+	}
+}
@@ -3,6 +3,8 @@ package monitor
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
@@ -18,9 +20,19 @@ import (
 
 // K8sClient wrapper para client-go com contexto do cluster
 type K8sClient struct {
-	Clientset *kubernetes.Clientset // Exportado para uso em outros packages
+	Clientset kubernetes.Interface // Exportado para uso em outros packages
 	config    *rest.Config
 	cluster   *models.ClusterInfo
+	informers *ClusterInformers // nil até StartInformers; usado como cache local
+	mu        sync.RWMutex      // Protege informers: publicado pelo Start enquanto scans coletam
+}
+
+// defaultExcludedNamespaces namespaces de sistema ignorados na coleta
+var defaultExcludedNamespaces = []string{
+	"kube-system",
+	"kube-public",
+	"kube-node-lease",
+	"default",
 }
 
 // NewK8sClient cria um novo client para um cluster específico
@@ -71,15 +83,7 @@ func (k *K8sClient) ListNamespaces(ctx context.Context, excludePatterns []string
 		return nil, fmt.Errorf("failed to list namespaces: %w", err)
 	}
 
-	// Default patterns to exclude
-	defaultExclude := []string{
-		"kube-system",
-		"kube-public",
-		"kube-node-lease",
-		"default",
-	}
-
-	exclude := append(defaultExclude, excludePatterns...)
+	exclude := append(append([]string{}, defaultExcludedNamespaces...), excludePatterns...)
 	result := []string{}
 
 	for _, ns := range namespaces.Items {
@@ -133,6 +137,131 @@ func (k *K8sClient) GetDeployment(ctx context.Context, namespace, name string) (
 	return deployment, nil
 }
 
+// GetStatefulSet obtém statefulset associado ao HPA
+func (k *K8sClient) GetStatefulSet(ctx context.Context, namespace, name string) (*appsv1.StatefulSet, error) {
+	sts, err := k.Clientset.AppsV1().StatefulSets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statefulset %s/%s: %w", namespace, name, err)
+	}
+
+	return sts, nil
+}
+
+// getPodTemplate retorna o pod template do workload alvo.
+// Usa o cache dos informers quando disponível, senão faz GET na API.
+func (k *K8sClient) getPodTemplate(ctx context.Context, namespace, kind, name string) (*corev1.PodTemplateSpec, error) {
+	if ci := k.Informers(); ci != nil {
+		return ci.GetPodTemplate(namespace, kind, name)
+	}
+
+	switch kind {
+	case "Deployment":
+		deployment, err := k.GetDeployment(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return &deployment.Spec.Template, nil
+
+	case "StatefulSet":
+		sts, err := k.GetStatefulSet(ctx, namespace, name)
+		if err != nil {
+			return nil, err
+		}
+		return &sts.Spec.Template, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported scale target kind: %s", kind)
+	}
+}
+
+// applyContainerResources extrai requests/limits do primeiro container
+func applyContainerResources(snapshot *models.HPASnapshot, template *corev1.PodTemplateSpec) {
+	if len(template.Spec.Containers) == 0 {
+		return
+	}
+
+	container := template.Spec.Containers[0]
+	if container.Resources.Requests != nil {
+		if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
+			snapshot.CPURequest = cpu.String()
+		}
+		if mem, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
+			snapshot.MemoryRequest = mem.String()
+		}
+	}
+	if container.Resources.Limits != nil {
+		if cpu, ok := container.Resources.Limits[corev1.ResourceCPU]; ok {
+			snapshot.CPULimit = cpu.String()
+		}
+		if mem, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
+			snapshot.MemoryLimit = mem.String()
+		}
+	}
+}
+
+// describeMetricSpec normaliza uma entrada de spec.metrics para comparação entre snapshots
+func describeMetricSpec(metric autoscalingv2.MetricSpec) string {
+	switch metric.Type {
+	case autoscalingv2.ResourceMetricSourceType:
+		if metric.Resource != nil {
+			return fmt.Sprintf("resource:%s/%s", metric.Resource.Name, describeMetricTarget(metric.Resource.Target))
+		}
+	case autoscalingv2.ContainerResourceMetricSourceType:
+		if metric.ContainerResource != nil {
+			return fmt.Sprintf("container:%s:%s/%s", metric.ContainerResource.Container,
+				metric.ContainerResource.Name, describeMetricTarget(metric.ContainerResource.Target))
+		}
+	case autoscalingv2.PodsMetricSourceType:
+		if metric.Pods != nil {
+			return fmt.Sprintf("pods:%s/%s", metric.Pods.Metric.Name, describeMetricTarget(metric.Pods.Target))
+		}
+	case autoscalingv2.ObjectMetricSourceType:
+		if metric.Object != nil {
+			return fmt.Sprintf("object:%s/%s:%s/%s", metric.Object.DescribedObject.Kind, metric.Object.DescribedObject.Name,
+				metric.Object.Metric.Name, describeMetricTarget(metric.Object.Target))
+		}
+	case autoscalingv2.ExternalMetricSourceType:
+		if metric.External != nil {
+			return fmt.Sprintf("external:%s/%s", metric.External.Metric.Name, describeMetricTarget(metric.External.Target))
+		}
+	}
+	return strings.ToLower(string(metric.Type))
+}
+
+// describeMetricTarget formata o target de uma métrica (ex: "Utilization=70", "AverageValue=500m")
+func describeMetricTarget(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%s=%d", target.Type, *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return fmt.Sprintf("%s=%s", target.Type, target.AverageValue.String())
+	case target.Value != nil:
+		return fmt.Sprintf("%s=%s", target.Type, target.Value.String())
+	default:
+		return string(target.Type)
+	}
+}
+
+// specManager retorna o manager do managedFields que alterou o spec mais recentemente
+func specManager(managedFields []metav1.ManagedFieldsEntry) (string, *time.Time) {
+	var manager string
+	var managedAt *time.Time
+
+	for _, entry := range managedFields {
+		if entry.Time == nil || entry.FieldsV1 == nil || !strings.Contains(string(entry.FieldsV1.Raw), `"f:spec"`) {
+			continue
+		}
+
+		if managedAt == nil || entry.Time.After(*managedAt) {
+			t := entry.Time.Time
+			manager = entry.Manager
+			managedAt = &t
+		}
+	}
+
+	return manager, managedAt
+}
+
 // CollectHPASnapshot coleta um snapshot completo de um HPA
 func (k *K8sClient) CollectHPASnapshot(ctx context.Context, hpa *autoscalingv2.HorizontalPodAutoscaler) (*models.HPASnapshot, error) {
 	snapshot := &models.HPASnapshot{
@@ -142,14 +271,19 @@ func (k *K8sClient) CollectHPASnapshot(ctx context.Context, hpa *autoscalingv2.H
 		Name:      hpa.Name,
 	}
 
-	// HPA Config
-	snapshot.MinReplicas = *hpa.Spec.MinReplicas
+	// HPA Config (minReplicas default do K8s é 1)
+	snapshot.MinReplicas = 1
+	if hpa.Spec.MinReplicas != nil {
+		snapshot.MinReplicas = *hpa.Spec.MinReplicas
+	}
 	snapshot.MaxReplicas = hpa.Spec.MaxReplicas
 	snapshot.CurrentReplicas = hpa.Status.CurrentReplicas
 	snapshot.DesiredReplicas = hpa.Status.DesiredReplicas
 
 	// Targets (CPU/Memory)
 	for _, metric := range hpa.Spec.Metrics {
+		snapshot.MetricSpecs = append(snapshot.MetricSpecs, describeMetricSpec(metric))
+
 		if metric.Type == autoscalingv2.ResourceMetricSourceType {
 			if metric.Resource.Name == corev1.ResourceCPU && metric.Resource.Target.AverageUtilization != nil {
 				snapshot.CPUTarget = *metric.Resource.Target.AverageUtilization
@@ -168,45 +302,38 @@ func (k *K8sClient) CollectHPASnapshot(ctx context.Context, hpa *autoscalingv2.H
 		if condition.Type == autoscalingv2.AbleToScale {
 			snapshot.Ready = condition.Status == corev1.ConditionTrue
 		}
+
+		snapshot.Conditions = append(snapshot.Conditions, models.HPACondition{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: condition.LastTransitionTime.Time,
+		})
 	}
 
+	snapshot.SpecManager, snapshot.SpecManagedAt = specManager(hpa.ManagedFields)
+
 	if hpa.Status.LastScaleTime != nil {
 		scaleTime := hpa.Status.LastScaleTime.Time
 		snapshot.LastScaleTime = &scaleTime
 	}
 
-	// Tenta obter resources do deployment/target
-	if hpa.Spec.ScaleTargetRef.Kind == "Deployment" {
-		deployment, err := k.GetDeployment(ctx, hpa.Namespace, hpa.Spec.ScaleTargetRef.Name)
+	// Tenta obter resources do workload alvo (cache dos informers ou API)
+	ref := hpa.Spec.ScaleTargetRef
+	if ref.Kind == "Deployment" || ref.Kind == "StatefulSet" {
+		template, err := k.getPodTemplate(ctx, hpa.Namespace, ref.Kind, ref.Name)
 		if err != nil {
 			log.Warn().
 				Err(err).
 				Str("cluster", k.cluster.Name).
 				Str("namespace", hpa.Namespace).
 				Str("hpa", hpa.Name).
-				Str("deployment", hpa.Spec.ScaleTargetRef.Name).
-				Msg("Failed to get deployment for HPA")
+				Str("target", ref.Kind+"/"+ref.Name).
+				Msg("Failed to get scale target for HPA")
+			snapshot.ResourcesUnknown = true
 		} else {
-			// Extrai resources do primeiro container
-			if len(deployment.Spec.Template.Spec.Containers) > 0 {
-				container := deployment.Spec.Template.Spec.Containers[0]
-				if container.Resources.Requests != nil {
-					if cpu, ok := container.Resources.Requests[corev1.ResourceCPU]; ok {
-						snapshot.CPURequest = cpu.String()
-					}
-					if mem, ok := container.Resources.Requests[corev1.ResourceMemory]; ok {
-						snapshot.MemoryRequest = mem.String()
-					}
-				}
-				if container.Resources.Limits != nil {
-					if cpu, ok := container.Resources.Limits[corev1.ResourceCPU]; ok {
-						snapshot.CPULimit = cpu.String()
-					}
-					if mem, ok := container.Resources.Limits[corev1.ResourceMemory]; ok {
-						snapshot.MemoryLimit = mem.String()
-					}
-				}
-			}
+			applyContainerResources(snapshot, template)
 		}
 	}
 
@@ -243,6 +370,46 @@ func (k *K8sClient) GetClusterInfo() *models.ClusterInfo {
 	return k.cluster
 }
 
+// StartInformers inicia os informers do cluster; a partir daí os snapshots usam o cache local
+func (k *K8sClient) StartInformers(ctx context.Context) (*ClusterInformers, error) {
+	ci := NewClusterInformers(k)
+	if err := ci.Start(ctx); err != nil {
+		return nil, err
+	}
+	return ci, nil
+}
+
+// Informers retorna os informers ativos (nil se o cluster usa polling)
+func (k *K8sClient) Informers() *ClusterInformers {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	return k.informers
+}
+
+// setInformers publica os informers usados como cache local
+func (k *K8sClient) setInformers(ci *ClusterInformers) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.informers = ci
+}
+
+// dropInformers volta a usar a API se ci ainda é o cache em uso (informers parados)
+func (k *K8sClient) dropInformers(ci *ClusterInformers) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.informers == ci {
+		k.informers = nil
+	}
+}
+
+// isExcludedNamespace verifica se o namespace é de sistema
+func isExcludedNamespace(namespace string) bool {
+	return contains(defaultExcludedNamespaces, namespace)
+}
+
 // Helper function
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
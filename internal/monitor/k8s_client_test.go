@@ -10,6 +10,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 // TestCollectHPASnapshot testa a coleta de snapshot de HPA
@@ -75,13 +76,20 @@ func TestCollectHPASnapshot(t *testing.T) {
 					Type:   autoscalingv2.AbleToScale,
 					Status: corev1.ConditionTrue,
 				},
+				{
+					Type:    autoscalingv2.ScalingLimited,
+					Status:  corev1.ConditionTrue,
+					Reason:  "TooManyReplicas",
+					Message: "the desired replica count is more than the maximum replica count",
+				},
 			},
 		},
 	}
 
-	// Create K8sClient (sem clientset real para teste unitário)
+	// Create K8sClient (clientset fake para teste unitário)
 	client := &K8sClient{
-		cluster: cluster,
+		Clientset: fake.NewClientset(),
+		cluster:   cluster,
 	}
 
 	// Collect snapshot
@@ -145,10 +153,23 @@ func TestCollectHPASnapshot(t *testing.T) {
 		t.Error("Expected Ready to be true")
 	}
 
+	if len(snapshot.Conditions) != 3 {
+		t.Errorf("Expected 3 conditions, got %d", len(snapshot.Conditions))
+	}
+
+	if c := snapshot.Condition(models.ConditionScalingLimited); c == nil || !c.IsTrue() || c.Reason != "TooManyReplicas" {
+		t.Errorf("Expected ScalingLimited/TooManyReplicas condition, got %+v", c)
+	}
+
 	if snapshot.LastScaleTime == nil {
 		t.Error("Expected LastScaleTime to be set")
 	}
 
+	// Deployment não existe no clientset fake: resources desconhecidos, não vazios
+	if !snapshot.ResourcesUnknown || snapshot.CPURequest != "" {
+		t.Errorf("Expected ResourcesUnknown after scale target fetch failure, got %v/%q", snapshot.ResourcesUnknown, snapshot.CPURequest)
+	}
+
 	// Verify timestamp is recent
 	if time.Since(snapshot.Timestamp) > time.Second {
 		t.Error("Expected recent timestamp")
@@ -265,7 +286,8 @@ func BenchmarkCollectHPASnapshot(b *testing.B) {
 	}
 
 	client := &K8sClient{
-		cluster: cluster,
+		Clientset: fake.NewClientset(),
+		cluster:   cluster,
 	}
 
 	ctx := context.Background()
@@ -303,3 +325,59 @@ func TestResourceParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestDescribeMetricSpec(t *testing.T) {
+	cpuTarget := int32(70)
+	averageValue := resource.MustParse("100")
+
+	tests := []struct {
+		metric   autoscalingv2.MetricSpec
+		expected string
+	}{
+		{
+			metric: autoscalingv2.MetricSpec{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name:   corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.UtilizationMetricType, AverageUtilization: &cpuTarget},
+				},
+			},
+			expected: "resource:cpu/Utilization=70",
+		},
+		{
+			metric: autoscalingv2.MetricSpec{
+				Type: autoscalingv2.PodsMetricSourceType,
+				Pods: &autoscalingv2.PodsMetricSource{
+					Metric: autoscalingv2.MetricIdentifier{Name: "http_requests_per_second"},
+					Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &averageValue},
+				},
+			},
+			expected: "pods:http_requests_per_second/AverageValue=100",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := describeMetricSpec(tt.metric); got != tt.expected {
+			t.Errorf("describeMetricSpec() = %q, want %q", got, tt.expected)
+		}
+	}
+}
+
+func TestSpecManager(t *testing.T) {
+	older := metav1.NewTime(time.Now().Add(-time.Hour))
+	newer := metav1.NewTime(time.Now().Add(-time.Minute))
+
+	managedFields := []metav1.ManagedFieldsEntry{
+		{Manager: "argocd-controller", Time: &older, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:maxReplicas":{}}}`)}},
+		{Manager: "kube-controller-manager", Time: &newer, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:status":{}}`)}},
+		{Manager: "kubectl-edit", Time: &newer, FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:maxReplicas":{}}}`)}},
+	}
+
+	manager, managedAt := specManager(managedFields)
+	if manager != "kubectl-edit" {
+		t.Errorf("Expected kubectl-edit, got %q", manager)
+	}
+	if managedAt == nil || !managedAt.Equal(newer.Time) {
+		t.Errorf("Expected managedAt %s, got %v", newer.Time, managedAt)
+	}
+}
@@ -0,0 +1,154 @@
+package monitor
+
+import (
+	"sort"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// DefaultSourcePriority usado quando a config não define source_priority
+var DefaultSourcePriority = []models.AlertSource{models.AlertSourceAlertmanager, models.AlertSourceWatchdog}
+
+// sourcePriorityFromConfig converte source_priority (nomes inválidos são ignorados; validados no config.Load)
+func sourcePriorityFromConfig(names []string) []models.AlertSource {
+	var priority []models.AlertSource
+	for _, name := range names {
+		if source, err := models.ParseAlertSource(name); err == nil {
+			priority = append(priority, source)
+		}
+	}
+	return priority
+}
+
+// duplicateKey chave da condição reportada pelo alerta: mesmo HPA e mesmo tipo de anomalia.
+// Alertas sem HPA ou sem anomalia equivalente (External) não são mesclados.
+func duplicateKey(alert *models.UnifiedAlert) (string, bool) {
+	if alert.HPAName == "" || alert.Type == models.AnomalyExternal {
+		return "", false
+	}
+	return models.HPAKey(alert.Cluster, alert.Namespace, alert.HPAName) + "/" + alert.Type.String(), true
+}
+
+// sourceRank posição da fonte em source_priority (fontes ausentes ficam por último)
+func (s *AlertStore) sourceRank(source models.AlertSource) int {
+	for i, preferred := range s.opts.SourcePriority {
+		if preferred == source {
+			return i
+		}
+	}
+	return len(s.opts.SourcePriority)
+}
+
+// duplicatesOf IDs dos alertas de outras fontes com a mesma condição (mesmo status: ativo ou resolvido)
+func (s *AlertStore) duplicatesOf(alert *models.UnifiedAlert) []string {
+	key, ok := duplicateKey(alert)
+	if !ok {
+		return nil
+	}
+
+	resolved := alert.Status == models.AlertStatusResolved
+
+	var ids []string
+	for id, other := range s.alerts {
+		if id == alert.ID || other.Source == alert.Source || (other.Status == models.AlertStatusResolved) != resolved {
+			continue
+		}
+		if otherKey, ok := duplicateKey(other); ok && otherKey == key {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+// mergeSources mescla alertas de fontes diferentes que reportam a mesma condição.
+// A fonte preferida (source_priority) define ID, resumo e severidade; as demais enriquecem
+// o alerta (Snapshot, Context, GeneratorURL, labels) e ficam em MergedFrom.
+func (s *AlertStore) mergeSources(alerts []models.UnifiedAlert) []models.UnifiedAlert {
+	groups := make(map[string][]int)
+	for i := range alerts {
+		if key, ok := duplicateKey(&alerts[i]); ok {
+			groups[key] = append(groups[key], i)
+		}
+	}
+
+	drop := make(map[int]bool)
+	for _, indexes := range groups {
+		if len(indexes) < 2 {
+			continue
+		}
+
+		sort.SliceStable(indexes, func(i, j int) bool {
+			a, b := &alerts[indexes[i]], &alerts[indexes[j]]
+			if ra, rb := s.sourceRank(a.Source), s.sourceRank(b.Source); ra != rb {
+				return ra < rb
+			}
+			return a.Timestamp.Before(b.Timestamp)
+		})
+
+		primary := &alerts[indexes[0]]
+		for _, idx := range indexes[1:] {
+			if alerts[idx].Source == primary.Source {
+				continue // Mesma fonte: alertas distintos (ex: fingerprints diferentes)
+			}
+			enrich(primary, &alerts[idx])
+			drop[idx] = true
+		}
+	}
+
+	if len(drop) == 0 {
+		return alerts
+	}
+
+	merged := make([]models.UnifiedAlert, 0, len(alerts)-len(drop))
+	for i := range alerts {
+		if !drop[i] {
+			merged = append(merged, alerts[i])
+		}
+	}
+	return merged
+}
+
+// enrich completa o alerta preferido com os dados do alerta da outra fonte
+func enrich(primary, other *models.UnifiedAlert) {
+	primary.MergedFrom = append(primary.MergedFrom, other.ID)
+
+	if primary.Snapshot == nil {
+		primary.Snapshot = other.Snapshot
+	}
+	if primary.Context == nil {
+		primary.Context = other.Context
+	}
+	if primary.Description == "" {
+		primary.Description = other.Description
+	}
+	if primary.GeneratorURL == "" {
+		primary.GeneratorURL = other.GeneratorURL
+	}
+	if primary.Fingerprint == "" {
+		primary.Fingerprint = other.Fingerprint
+	}
+	if primary.AlertName == "" {
+		primary.AlertName = other.AlertName
+	}
+	if len(primary.Labels) == 0 {
+		primary.Labels = other.Labels
+	}
+	if len(primary.SilencedBy) == 0 {
+		primary.SilencedBy = other.SilencedBy
+	}
+
+	// A condição começou quando a primeira fonte detectou
+	if other.Timestamp.Before(primary.Timestamp) {
+		primary.Timestamp = other.Timestamp
+	}
+	if other.LastSeen.After(primary.LastSeen) {
+		primary.LastSeen = other.LastSeen
+	}
+	if !primary.Acknowledged && other.Acknowledged {
+		primary.Acknowledged = true
+		primary.AckedAt = other.AckedAt
+		primary.AckedBy = other.AckedBy
+	}
+}
@@ -0,0 +1,126 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// newAlertmanagerAlert alerta KubeHpaMaxedOut do Alertmanager para o HPA
+func newAlertmanagerAlert(hpa string) models.UnifiedAlert {
+	return models.UnifiedAlert{
+		Source:       models.AlertSourceAlertmanager,
+		Severity:     models.SeverityCritical,
+		Type:         models.AnomalyMaxedOut,
+		Cluster:      "test-cluster",
+		Namespace:    "production",
+		HPAName:      hpa,
+		Summary:      "HPA has been running at max replicas",
+		AlertName:    "KubeHpaMaxedOut",
+		Labels:       map[string]string{"alertname": "KubeHpaMaxedOut"},
+		Fingerprint:  "fp-" + hpa,
+		GeneratorURL: "http://prometheus/graph",
+	}
+}
+
+func TestAlertStoreMergesSources(t *testing.T) {
+	store := NewAlertStore(DefaultAlertStoreOptions())
+	now := time.Now()
+
+	watchdog := newTestAlert("api", models.AnomalyMaxedOut, models.SeverityWarning)
+	watchdog.Snapshot = &models.HPASnapshot{Name: "api", CurrentReplicas: 10}
+
+	if opened := store.Sync(models.HPAKey("test-cluster", "production", "api"), []models.UnifiedAlert{watchdog}, now); len(opened) != 1 {
+		t.Fatalf("Expected watchdog alert opened, got %d", len(opened))
+	}
+
+	// Mesma condição pelo Alertmanager: não é um alerta novo
+	if opened := store.Sync("alertmanager/test-cluster", []models.UnifiedAlert{newAlertmanagerAlert("api")}, now.Add(time.Minute)); len(opened) != 0 {
+		t.Errorf("Expected duplicate Alertmanager alert not reported as opened, got %+v", opened)
+	}
+
+	active := store.Active()
+	if len(active) != 1 {
+		t.Fatalf("Expected 1 merged alert, got %d", len(active))
+	}
+
+	merged := active[0]
+	if merged.Source != models.AlertSourceAlertmanager || merged.ID != "test-cluster/alertmanager/fp-api" {
+		t.Errorf("Expected Alertmanager as preferred source, got %s (%s)", merged.Source, merged.ID)
+	}
+	if merged.Severity != models.SeverityCritical || merged.Summary != "HPA has been running at max replicas" {
+		t.Errorf("Expected severity and summary from Alertmanager, got %s %q", merged.Severity, merged.Summary)
+	}
+	if merged.Snapshot == nil || merged.Snapshot.CurrentReplicas != 10 {
+		t.Error("Expected snapshot from the watchdog alert")
+	}
+	if len(merged.MergedFrom) != 1 || merged.MergedFrom[0] != "test-cluster/production/api/MaxedOut" {
+		t.Errorf("Expected MergedFrom with the watchdog alert, got %v", merged.MergedFrom)
+	}
+	if !merged.Timestamp.Equal(now) {
+		t.Errorf("Expected merged alert to start with the first detection, got %s", merged.Timestamp)
+	}
+
+	// Ack no alerta mesclado reconhece as duas fontes
+	if err := store.Acknowledge(merged.ID, "alice", now); err != nil {
+		t.Fatalf("Acknowledge failed: %v", err)
+	}
+	if alert, _ := store.Get("test-cluster/production/api/MaxedOut"); !alert.Acknowledged || alert.AckedBy != "alice" {
+		t.Errorf("Expected watchdog alert acknowledged with the merged alert, got %+v", alert)
+	}
+}
+
+func TestAlertStoreSourcePriority(t *testing.T) {
+	opts := DefaultAlertStoreOptions()
+	opts.SourcePriority = []models.AlertSource{models.AlertSourceWatchdog, models.AlertSourceAlertmanager}
+	store := NewAlertStore(opts)
+	now := time.Now()
+
+	store.Sync("alertmanager/test-cluster", []models.UnifiedAlert{newAlertmanagerAlert("api")}, now)
+	store.Sync(models.HPAKey("test-cluster", "production", "api"),
+		[]models.UnifiedAlert{newTestAlert("api", models.AnomalyMaxedOut, models.SeverityWarning)}, now)
+
+	active := store.Active()
+	if len(active) != 1 {
+		t.Fatalf("Expected 1 merged alert, got %d", len(active))
+	}
+
+	merged := active[0]
+	if merged.Source != models.AlertSourceWatchdog || merged.Severity != models.SeverityWarning {
+		t.Errorf("Expected watchdog as preferred source, got %s/%s", merged.Source, merged.Severity)
+	}
+	if merged.GeneratorURL != "http://prometheus/graph" || merged.Fingerprint != "fp-api" {
+		t.Errorf("Expected Alertmanager enrichment, got %+v", merged)
+	}
+}
+
+func TestAlertStoreDoesNotMergeDifferentConditions(t *testing.T) {
+	store := NewAlertStore(DefaultAlertStoreOptions())
+	now := time.Now()
+
+	external := newAlertmanagerAlert("api")
+	external.Type = models.AnomalyExternal
+	external.AlertName = "KubePodCrashLooping"
+	other := newAlertmanagerAlert("worker")
+
+	store.Sync("alertmanager/test-cluster", []models.UnifiedAlert{external, other}, now)
+	store.Sync(models.HPAKey("test-cluster", "production", "api"),
+		[]models.UnifiedAlert{newTestAlert("api", models.AnomalyMaxedOut, models.SeverityWarning)}, now)
+
+	if active := store.Active(); len(active) != 3 {
+		t.Errorf("Expected external and other-HPA alerts kept separate, got %d", len(active))
+	}
+}
+
+func TestSourcePriorityFromConfig(t *testing.T) {
+	opts := AlertStoreOptionsFromConfig(&models.WatchdogConfig{SourcePriority: []string{"watchdog", "alertmanager"}})
+	if len(opts.SourcePriority) != 2 || opts.SourcePriority[0] != models.AlertSourceWatchdog {
+		t.Errorf("Expected watchdog first, got %v", opts.SourcePriority)
+	}
+
+	opts = AlertStoreOptionsFromConfig(&models.WatchdogConfig{})
+	if len(opts.SourcePriority) != 2 || opts.SourcePriority[0] != models.AlertSourceAlertmanager {
+		t.Errorf("Expected default priority, got %v", opts.SourcePriority)
+	}
+}
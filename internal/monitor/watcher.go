@@ -0,0 +1,631 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/analyzer"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/storage"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultScanInterval intervalo usado quando a config não define scan_interval_seconds
+	DefaultScanInterval = 30 * time.Second
+
+	// enrichTimeout timeout para enriquecer um snapshot (várias queries Prometheus)
+	enrichTimeout = 30 * time.Second
+
+	// eventBufferSize eventos de informer pendentes antes de descartar
+	eventBufferSize = 256
+
+	// storageTimeout timeout para gravar um scan no storage
+	storageTimeout = 30 * time.Second
+
+	// cleanupInterval intervalo entre limpezas de retenção do storage
+	cleanupInterval = time.Hour
+)
+
+// SnapshotEnricher enriquece snapshots com métricas de fontes externas.
+// Implementado por *prometheus.Client; definido aqui para evitar import cycle.
+type SnapshotEnricher interface {
+	EnrichSnapshot(ctx context.Context, snapshot *models.HPASnapshot) error
+}
+
+// Watcher executa o loop contínuo de monitoramento e mantém o histórico entre scans
+type Watcher struct {
+	cfg       *models.WatchdogConfig
+	session   *MonitoringSession
+	enrichers map[string]SnapshotEnricher       // cluster -> enricher
+	series    map[string]*models.TimeSeriesData // "cluster/namespace/name" -> histórico
+	analyzer  *analyzer.Engine
+	alerts    *AlertStore
+	store     storage.Store // nil = sem persistência
+	cleanedAt time.Time
+	clusters  []models.ClusterInfo
+	events    chan HPAEvent
+	reconfig  chan struct{} // Sinaliza o loop para recriar o ticker (scan interval mudou)
+	lastScan  time.Time
+	scanCount int
+	mu        sync.RWMutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewWatcher cria um watcher para os clusters da sessão.
+// Registra-se para eventos dos informers; chame antes de session.StartInformers.
+func NewWatcher(cfg *models.WatchdogConfig, session *MonitoringSession) *Watcher {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	w := &Watcher{
+		cfg:       cfg,
+		session:   session,
+		enrichers: make(map[string]SnapshotEnricher),
+		series:    make(map[string]*models.TimeSeriesData),
+		alerts:    NewAlertStore(AlertStoreOptionsFromConfig(cfg)),
+		clusters:  session.Clusters(),
+		events:    make(chan HPAEvent, eventBufferSize),
+		reconfig:  make(chan struct{}, 1),
+		ctx:       ctx,
+		cancel:    cancel,
+	}
+
+	if correlator := CorrelatorFromConfig(cfg); correlator != nil {
+		w.alerts.SetCorrelator(correlator)
+	}
+
+	session.AddEventHandler(w.handleEvent)
+	session.SetCollectOptions(CollectOptionsFromConfig(cfg))
+
+	return w
+}
+
+// SetEnricher registra o enricher (ex: Prometheus) de um cluster (nil = remove)
+func (w *Watcher) SetEnricher(cluster string, enricher SnapshotEnricher) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if enricher == nil {
+		delete(w.enrichers, cluster)
+		return
+	}
+	w.enrichers[cluster] = enricher
+}
+
+// SetConfig troca a configuração em uso (reload). Scan interval e retenção valem imediatamente;
+// o ticker é recriado se o intervalo mudou.
+func (w *Watcher) SetConfig(cfg *models.WatchdogConfig) {
+	before := w.scanInterval()
+
+	w.mu.Lock()
+	w.cfg = cfg
+	w.mu.Unlock()
+
+	if after := w.scanInterval(); after != before {
+		select {
+		case w.reconfig <- struct{}{}:
+		default:
+		}
+
+		log.Info().Dur("from", before).Dur("to", after).Msg("Scan interval updated")
+	}
+}
+
+// AddCluster conecta o cluster na sessão e passa a monitorá-lo a partir do próximo scan
+func (w *Watcher) AddCluster(cluster *models.ClusterInfo) error {
+	if err := w.session.AddCluster(cluster); err != nil {
+		return err
+	}
+
+	client, _ := w.session.GetClient(cluster.Name)
+	info := *client.GetClusterInfo()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.clusters = append(w.clusters, info)
+	sort.Slice(w.clusters, func(i, j int) bool {
+		return w.clusters[i].Name < w.clusters[j].Name
+	})
+	return nil
+}
+
+// RemoveCluster para de monitorar o cluster: remove da sessão, descarta histórico e enricher
+// e resolve os alertas dos HPAs dele. Retorna false se o cluster não era monitorado.
+func (w *Watcher) RemoveCluster(clusterName string) bool {
+	removed := w.session.RemoveCluster(clusterName)
+
+	prefix := clusterName + "/"
+
+	w.mu.Lock()
+	for i := range w.clusters {
+		if w.clusters[i].Name == clusterName {
+			w.clusters = append(w.clusters[:i], w.clusters[i+1:]...)
+			removed = true
+			break
+		}
+	}
+	delete(w.enrichers, clusterName)
+
+	var keys []string
+	for key := range w.series {
+		if strings.HasPrefix(key, prefix) {
+			delete(w.series, key)
+			keys = append(keys, key)
+		}
+	}
+	engine := w.analyzer
+	w.mu.Unlock()
+
+	if engine != nil {
+		for _, key := range keys {
+			engine.Forget(key)
+		}
+	}
+
+	// Alertas do watchdog do cluster, inclusive de HPAs sem histórico (ex: restaurados do storage)
+	scopes := make(map[string]bool)
+	for _, key := range keys {
+		scopes[key] = true
+	}
+	for _, alert := range w.alerts.Active() {
+		if alert.Cluster == clusterName && alert.Source == models.AlertSourceWatchdog {
+			scopes[models.HPAKey(alert.Cluster, alert.Namespace, alert.HPAName)] = true
+		}
+	}
+
+	now := time.Now()
+	for scope := range scopes {
+		w.alerts.Sync(scope, nil, now)
+	}
+
+	if removed {
+		log.Info().
+			Str("cluster", clusterName).
+			Int("removed_hpas", len(keys)).
+			Msg("Cluster removed from watcher")
+	}
+	return removed
+}
+
+// SetAnalyzer define o engine de detecção de anomalias executado a cada snapshot
+func (w *Watcher) SetAnalyzer(engine *analyzer.Engine) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.analyzer = engine
+}
+
+// SetStorage define onde snapshots e alertas são persistidos a cada scan
+func (w *Watcher) SetStorage(store storage.Store) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.store = store
+}
+
+// Start inicia o loop de monitoramento em background
+func (w *Watcher) Start() {
+	w.wg.Add(1)
+	go w.loop()
+
+	log.Info().
+		Dur("interval", w.scanInterval()).
+		Dur("retention", w.retention()).
+		Int("clusters", len(w.clusters)).
+		Msg("Watcher started")
+}
+
+// Stop encerra o loop e aguarda o scan em andamento terminar
+func (w *Watcher) Stop() {
+	w.cancel()
+	w.wg.Wait()
+
+	log.Info().Msg("Watcher stopped")
+}
+
+// loop executa um scan imediato e depois um a cada scan interval
+func (w *Watcher) loop() {
+	defer w.wg.Done()
+
+	w.Scan()
+
+	ticker := time.NewTicker(w.scanInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+
+		case <-ticker.C:
+			w.Scan()
+
+		case <-w.reconfig:
+			ticker.Reset(w.scanInterval())
+
+		case event := <-w.events:
+			w.processEvent(event)
+		}
+	}
+}
+
+// handleEvent enfileira eventos dos informers sem bloquear a goroutine do informer
+func (w *Watcher) handleEvent(event HPAEvent) {
+	select {
+	case w.events <- event:
+	default:
+		log.Warn().
+			Str("cluster", event.Cluster).
+			Str("namespace", event.Namespace).
+			Str("hpa", event.Name).
+			Msg("Event buffer full, dropping HPA event (next scan will catch up)")
+	}
+}
+
+// processEvent aplica um evento de informer ao histórico sem esperar o próximo scan
+func (w *Watcher) processEvent(event HPAEvent) {
+	switch event.Type {
+	case HPAEventDeleted:
+		key := models.HPAKey(event.Cluster, event.Namespace, event.Name)
+
+		w.mu.Lock()
+		delete(w.series, key)
+		engine := w.analyzer
+		w.mu.Unlock()
+
+		w.alerts.Sync(key, nil, time.Now())
+
+		if engine != nil {
+			engine.Forget(key)
+		}
+
+		log.Info().Str("hpa", key).Msg("HPA deleted, history removed")
+
+	case HPAEventAdded, HPAEventUpdated:
+		if event.Snapshot == nil {
+			return
+		}
+
+		w.enrich(w.ctx, event.Snapshot)
+		w.record(event.Snapshot)
+		w.analyze(event.Snapshot)
+
+		// Só os alertas do HPA do evento; o estado completo é gravado em lote a cada scan
+		w.persist([]models.HPASnapshot{*event.Snapshot}, w.alerts.ForHPA(event.Snapshot.Key()), time.Now())
+	}
+}
+
+// Scan coleta (em paralelo por cluster), enriquece e armazena snapshots.
+// Retorna o número de snapshots armazenados.
+func (w *Watcher) Scan() int {
+	start := time.Now()
+
+	// Enriquecimento roda no worker de cada cluster, dentro do deadline da coleta
+	results := w.session.CollectAndEnrich(w.enrich)
+
+	var scanned []models.HPASnapshot
+	for _, result := range results {
+		for _, snapshot := range result.Snapshots {
+			if w.ctx.Err() != nil {
+				return len(scanned)
+			}
+
+			w.record(snapshot)
+			w.analyze(snapshot)
+			scanned = append(scanned, *snapshot)
+		}
+	}
+	stored := len(scanned)
+
+	now := time.Now()
+	removed := w.pruneStale(now)
+	w.alerts.Prune(now)
+	w.persist(scanned, w.alerts.All(), now)
+
+	byCluster := make(map[string]ClusterCollectResult, len(results))
+	for _, result := range results {
+		byCluster[result.Cluster] = result
+	}
+
+	alertCount := make(map[string]int)
+	for _, alert := range w.alerts.Active() {
+		alertCount[alert.Cluster]++
+	}
+
+	w.mu.Lock()
+	for i := range w.clusters {
+		w.clusters[i].AlertCount = alertCount[w.clusters[i].Name]
+
+		result, ok := byCluster[w.clusters[i].Name]
+		if !ok {
+			continue
+		}
+
+		w.clusters[i].Status = result.Status()
+		w.clusters[i].LastScan = now
+
+		// Contagem parcial seria enganosa; mantém a do último scan completo
+		if result.Status() == models.ClusterStatusOnline {
+			w.clusters[i].HPACount = len(result.Snapshots)
+		}
+	}
+	w.lastScan = now
+	w.scanCount++
+	tracked := len(w.series)
+	w.mu.Unlock()
+
+	log.Info().
+		Int("snapshots", stored).
+		Int("tracked_hpas", tracked).
+		Int("removed_hpas", removed).
+		Dur("duration", time.Since(start)).
+		Msg("Scan complete")
+
+	return stored
+}
+
+// persist grava snapshots e alertas no storage e aplica a retenção a cada cleanupInterval
+func (w *Watcher) persist(snapshots []models.HPASnapshot, alerts []models.UnifiedAlert, now time.Time) {
+	w.mu.Lock()
+	store := w.store
+	cfg := w.cfg
+	cleanup := store != nil && now.Sub(w.cleanedAt) >= cleanupInterval
+	if cleanup {
+		w.cleanedAt = now
+	}
+	w.mu.Unlock()
+
+	if store == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, storageTimeout)
+	defer cancel()
+
+	if err := store.SaveSnapshots(ctx, snapshots); err != nil {
+		log.Warn().Err(err).Int("snapshots", len(snapshots)).Msg("Failed to persist snapshots")
+	}
+
+	if err := store.SaveAlerts(ctx, alerts); err != nil {
+		log.Warn().Err(err).Int("alerts", len(alerts)).Msg("Failed to persist alerts")
+	}
+
+	if !cleanup {
+		return
+	}
+
+	removed, err := store.Cleanup(ctx, now.Add(-storage.Retention(cfg)))
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to apply storage retention")
+		return
+	}
+
+	log.Debug().Int("removed", removed).Msg("Storage retention applied")
+}
+
+// PersistAlerts grava o estado atual dos alertas no storage (ex: logo após um reconhecimento)
+func (w *Watcher) PersistAlerts() error {
+	w.mu.RLock()
+	store := w.store
+	w.mu.RUnlock()
+
+	if store == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, storageTimeout)
+	defer cancel()
+
+	return store.SaveAlerts(ctx, w.alerts.All())
+}
+
+// RestoreAlerts carrega do storage os alertas que estavam ativos no último encerramento,
+// mantendo reconhecimentos se voltarem a disparar (ver AlertStore.Restore)
+func (w *Watcher) RestoreAlerts() (int, error) {
+	w.mu.RLock()
+	store := w.store
+	w.mu.RUnlock()
+
+	if store == nil {
+		return 0, nil
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, storageTimeout)
+	defer cancel()
+
+	now := time.Now()
+	alerts, err := store.Alerts(ctx, storage.AlertQuery{Since: now.Add(-w.alerts.opts.DedupeWindow)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to load persisted alerts: %w", err)
+	}
+
+	return w.alerts.Restore(alerts, now), nil
+}
+
+// enrich enriquece o snapshot com o enricher do cluster (se houver), limitado por ctx e enrichTimeout
+func (w *Watcher) enrich(ctx context.Context, snapshot *models.HPASnapshot) {
+	w.mu.RLock()
+	enricher, exists := w.enrichers[snapshot.Cluster]
+	w.mu.RUnlock()
+
+	if !exists || enricher == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, enrichTimeout)
+	defer cancel()
+
+	if err := enricher.EnrichSnapshot(ctx, snapshot); err != nil {
+		log.Warn().
+			Err(err).
+			Str("cluster", snapshot.Cluster).
+			Str("namespace", snapshot.Namespace).
+			Str("hpa", snapshot.Name).
+			Msg("Failed to enrich snapshot")
+	}
+}
+
+// record adiciona o snapshot à série temporal do HPA
+func (w *Watcher) record(snapshot *models.HPASnapshot) {
+	key := snapshot.Key()
+	retention := w.retention()
+
+	w.mu.Lock()
+	ts, exists := w.series[key]
+	if !exists {
+		ts = &models.TimeSeriesData{
+			HPAKey:      key,
+			MaxDuration: retention,
+		}
+		w.series[key] = ts
+	}
+	w.mu.Unlock()
+
+	ts.Add(*snapshot)
+}
+
+// analyze executa o analyzer sobre o snapshot e o histórico do HPA e sincroniza os alertas do HPA no store
+func (w *Watcher) analyze(snapshot *models.HPASnapshot) {
+	w.mu.RLock()
+	engine := w.analyzer
+	ts := w.series[snapshot.Key()]
+	w.mu.RUnlock()
+
+	if engine == nil {
+		return
+	}
+
+	in := analyzer.Input{Current: snapshot}
+	if ts != nil {
+		in.History = ts.GetHistory()
+	}
+
+	opened := w.alerts.Sync(snapshot.Key(), engine.Analyze(in), time.Now())
+
+	for _, alert := range opened {
+		log.Warn().
+			Str("cluster", alert.Cluster).
+			Str("namespace", alert.Namespace).
+			Str("hpa", alert.HPAName).
+			Str("type", alert.Type.String()).
+			Str("severity", alert.Severity.String()).
+			Str("incident", alert.IncidentID).
+			Msg(alert.Summary)
+	}
+}
+
+// pruneStale remove séries de HPAs que não aparecem há mais que a retenção (ex: HPA deletado)
+func (w *Watcher) pruneStale(now time.Time) int {
+	cutoff := now.Add(-w.retention())
+
+	w.mu.Lock()
+	var stale []string
+	for key, ts := range w.series {
+		latest := ts.GetLatest()
+		if latest == nil || latest.Timestamp.Before(cutoff) {
+			delete(w.series, key)
+			stale = append(stale, key)
+		}
+	}
+	engine := w.analyzer
+	w.mu.Unlock()
+
+	for _, key := range stale {
+		w.alerts.Sync(key, nil, now)
+	}
+
+	if engine != nil {
+		for _, key := range stale {
+			engine.Forget(key)
+		}
+	}
+
+	return len(stale)
+}
+
+// GetTimeSeries retorna a série temporal de um HPA ("cluster/namespace/name")
+func (w *Watcher) GetTimeSeries(key string) (*models.TimeSeriesData, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	ts, exists := w.series[key]
+	return ts, exists
+}
+
+// LatestSnapshots retorna o snapshot mais recente de cada HPA, ordenado por chave
+func (w *Watcher) LatestSnapshots() []models.HPASnapshot {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	snapshots := make([]models.HPASnapshot, 0, len(w.series))
+	for _, ts := range w.series {
+		if latest := ts.GetLatest(); latest != nil {
+			snapshots = append(snapshots, *latest)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Key() < snapshots[j].Key()
+	})
+
+	return snapshots
+}
+
+// Alerts retorna os alertas ativos de todos os HPAs, do mais severo ao menos severo
+func (w *Watcher) Alerts() []models.UnifiedAlert {
+	return w.alerts.Active()
+}
+
+// AlertStore retorna o store de alertas (ack, resolvidos, alertas de outras fontes)
+func (w *Watcher) AlertStore() *AlertStore {
+	return w.alerts
+}
+
+// Clusters retorna cópias das informações dos clusters monitorados
+func (w *Watcher) Clusters() []models.ClusterInfo {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	clusters := make([]models.ClusterInfo, len(w.clusters))
+	copy(clusters, w.clusters)
+	return clusters
+}
+
+// LastScan retorna o horário do último scan completo e o total de scans
+func (w *Watcher) LastScan() (time.Time, int) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.lastScan, w.scanCount
+}
+
+// scanInterval retorna o intervalo entre scans
+func (w *Watcher) scanInterval() time.Duration {
+	w.mu.RLock()
+	seconds := w.cfg.ScanIntervalSeconds
+	w.mu.RUnlock()
+
+	if seconds < 1 {
+		return DefaultScanInterval
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// retention retorna por quanto tempo o histórico é mantido
+func (w *Watcher) retention() time.Duration {
+	w.mu.RLock()
+	minutes := w.cfg.HistoryRetentionMinutes
+	w.mu.RUnlock()
+
+	if minutes < 1 {
+		return 5 * time.Minute
+	}
+	return time.Duration(minutes) * time.Minute
+}
@@ -0,0 +1,369 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/analyzer"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/storage"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeEnricher enricher de teste que marca snapshots como Prometheus
+type fakeEnricher struct {
+	calls int
+}
+
+func (f *fakeEnricher) EnrichSnapshot(ctx context.Context, snapshot *models.HPASnapshot) error {
+	f.calls++
+	snapshot.CPUCurrent = 42
+	snapshot.DataSource = models.DataSourcePrometheus
+	return nil
+}
+
+// newTestSession cria uma sessão com um cluster fake (sem port-forward)
+func newTestSession(clusterName string, objects ...runtime.Object) *MonitoringSession {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &MonitoringSession{
+		k8sClients: map[string]*K8sClient{
+			clusterName: {
+				Clientset: fake.NewClientset(objects...),
+				cluster: &models.ClusterInfo{
+					Name:   clusterName,
+					Status: models.ClusterStatusOffline,
+				},
+			},
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// newTestHPA cria um HPA mínimo para testes
+func newTestHPA(namespace, name string) *autoscalingv2.HorizontalPodAutoscaler {
+	minReplicas := int32(2)
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			MinReplicas: &minReplicas,
+			MaxReplicas: 10,
+		},
+		Status: autoscalingv2.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 3,
+			DesiredReplicas: 3,
+		},
+	}
+}
+
+func TestWatcherScanKeepsHistory(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}}
+	session := newTestSession("test-cluster", ns, newTestHPA("production", "api"))
+	defer session.Shutdown()
+
+	cfg := &models.WatchdogConfig{ScanIntervalSeconds: 30, HistoryRetentionMinutes: 5}
+	watcher := NewWatcher(cfg, session)
+
+	enricher := &fakeEnricher{}
+	watcher.SetEnricher("test-cluster", enricher)
+
+	if got := watcher.Scan(); got != 1 {
+		t.Fatalf("Expected 1 snapshot on first scan, got %d", got)
+	}
+	if got := watcher.Scan(); got != 1 {
+		t.Fatalf("Expected 1 snapshot on second scan, got %d", got)
+	}
+
+	if enricher.calls != 2 {
+		t.Errorf("Expected enricher to be called 2 times, got %d", enricher.calls)
+	}
+
+	ts, exists := watcher.GetTimeSeries("test-cluster/production/api")
+	if !exists {
+		t.Fatal("Expected time series for test-cluster/production/api")
+	}
+
+	if len(ts.GetHistory()) != 2 {
+		t.Errorf("Expected 2 snapshots in history, got %d", len(ts.GetHistory()))
+	}
+
+	if ts.MaxDuration != 5*time.Minute {
+		t.Errorf("Expected MaxDuration 5m, got %s", ts.MaxDuration)
+	}
+
+	latest := watcher.LatestSnapshots()
+	if len(latest) != 1 || latest[0].DataSource != models.DataSourcePrometheus {
+		t.Errorf("Expected 1 enriched snapshot, got %+v", latest)
+	}
+
+	clusters := watcher.Clusters()
+	if len(clusters) != 1 {
+		t.Fatalf("Expected 1 cluster, got %d", len(clusters))
+	}
+	if clusters[0].HPACount != 1 {
+		t.Errorf("Expected HPACount 1, got %d", clusters[0].HPACount)
+	}
+	if clusters[0].Status != models.ClusterStatusOnline {
+		t.Errorf("Expected cluster Online, got %s", clusters[0].Status)
+	}
+
+	if _, scans := watcher.LastScan(); scans != 2 {
+		t.Errorf("Expected 2 scans, got %d", scans)
+	}
+}
+
+func TestWatcherPruneStale(t *testing.T) {
+	session := newTestSession("test-cluster")
+	defer session.Shutdown()
+
+	cfg := &models.WatchdogConfig{ScanIntervalSeconds: 30, HistoryRetentionMinutes: 5}
+	watcher := NewWatcher(cfg, session)
+
+	old := &models.HPASnapshot{
+		Timestamp: time.Now().Add(-10 * time.Minute),
+		Cluster:   "test-cluster",
+		Namespace: "production",
+		Name:      "deleted",
+	}
+	watcher.series[old.Key()] = &models.TimeSeriesData{
+		HPAKey:      old.Key(),
+		Snapshots:   []models.HPASnapshot{*old},
+		MaxDuration: 5 * time.Minute,
+	}
+
+	recent := &models.HPASnapshot{
+		Timestamp: time.Now(),
+		Cluster:   "test-cluster",
+		Namespace: "production",
+		Name:      "api",
+	}
+	watcher.record(recent)
+
+	if removed := watcher.pruneStale(time.Now()); removed != 1 {
+		t.Errorf("Expected 1 stale series removed, got %d", removed)
+	}
+
+	if _, exists := watcher.GetTimeSeries(old.Key()); exists {
+		t.Error("Expected stale series to be removed")
+	}
+
+	if _, exists := watcher.GetTimeSeries(recent.Key()); !exists {
+		t.Error("Expected recent series to be kept")
+	}
+}
+
+func TestWatcherDefaults(t *testing.T) {
+	session := newTestSession("test-cluster")
+	defer session.Shutdown()
+
+	watcher := NewWatcher(&models.WatchdogConfig{}, session)
+
+	if watcher.scanInterval() != DefaultScanInterval {
+		t.Errorf("Expected default scan interval %s, got %s", DefaultScanInterval, watcher.scanInterval())
+	}
+
+	if watcher.retention() != 5*time.Minute {
+		t.Errorf("Expected default retention 5m, got %s", watcher.retention())
+	}
+}
+
+func TestWatcherAnalyze(t *testing.T) {
+	session := newTestSession("test-cluster")
+	defer session.Shutdown()
+
+	watcher := NewWatcher(&models.WatchdogConfig{HistoryRetentionMinutes: 5}, session)
+	watcher.SetAnalyzer(analyzer.NewEngine(config.NewThresholdManager(config.DefaultThresholds())))
+
+	snapshot := &models.HPASnapshot{
+		Timestamp:       time.Now(),
+		Cluster:         "test-cluster",
+		Namespace:       "production",
+		Name:            "api",
+		MinReplicas:     2,
+		MaxReplicas:     10,
+		CurrentReplicas: 4,
+		DesiredReplicas: 4,
+		CPUCurrent:      95,
+	}
+
+	watcher.processEvent(HPAEvent{Type: HPAEventUpdated, Cluster: "test-cluster", Namespace: "production", Name: "api", Snapshot: snapshot})
+
+	alerts := watcher.Alerts()
+	if len(alerts) != 1 || alerts[0].Type != models.AnomalyCPUSpike {
+		t.Fatalf("Expected 1 CPUSpike alert, got %+v", alerts)
+	}
+
+	// Snapshot saudável resolve os alertas do HPA
+	healthy := *snapshot
+	healthy.Timestamp = snapshot.Timestamp.Add(30 * time.Second)
+	healthy.CPUCurrent = 50
+	watcher.processEvent(HPAEvent{Type: HPAEventUpdated, Cluster: "test-cluster", Namespace: "production", Name: "api", Snapshot: &healthy})
+
+	if alerts := watcher.Alerts(); len(alerts) != 0 {
+		t.Errorf("Expected alerts to clear, got %+v", alerts)
+	}
+
+	if resolved := watcher.AlertStore().Resolved(); len(resolved) != 1 || resolved[0].Type != models.AnomalyCPUSpike {
+		t.Errorf("Expected CPUSpike to move to resolved, got %+v", resolved)
+	}
+}
+
+func TestWatcherPersistsScans(t *testing.T) {
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}}
+	session := newTestSession("test-cluster", ns, newTestHPA("production", "api"))
+	defer session.Shutdown()
+
+	cfg := &models.WatchdogConfig{ScanIntervalSeconds: 30, HistoryRetentionMinutes: 5}
+	watcher := NewWatcher(cfg, session)
+
+	store := storage.NewMemoryStore()
+	watcher.SetStorage(store)
+
+	watcher.Scan()
+	watcher.Scan()
+
+	snapshots, err := store.Snapshots(context.Background(), storage.SnapshotQuery{Name: "api"})
+	if err != nil {
+		t.Fatalf("Snapshots failed: %v", err)
+	}
+	if len(snapshots) != 2 || snapshots[0].Cluster != "test-cluster" {
+		t.Errorf("Expected 2 persisted snapshots, got %+v", snapshots)
+	}
+}
+
+// recordingStore storage em memória que registra os lotes de alertas gravados
+type recordingStore struct {
+	*storage.MemoryStore
+	batches [][]models.UnifiedAlert
+}
+
+func (r *recordingStore) SaveAlerts(ctx context.Context, alerts []models.UnifiedAlert) error {
+	r.batches = append(r.batches, alerts)
+	return r.MemoryStore.SaveAlerts(ctx, alerts)
+}
+
+func TestWatcherEventPersistsOnlyHPAAlerts(t *testing.T) {
+	session := newTestSession("test-cluster")
+	defer session.Shutdown()
+
+	watcher := NewWatcher(&models.WatchdogConfig{HistoryRetentionMinutes: 5}, session)
+	watcher.SetAnalyzer(analyzer.NewEngine(config.NewThresholdManager(config.DefaultThresholds())))
+	store := &recordingStore{MemoryStore: storage.NewMemoryStore()}
+	watcher.SetStorage(store)
+
+	now := time.Now()
+	for i := 0; i < 20; i++ {
+		name := fmt.Sprintf("worker-%d", i)
+		watcher.AlertStore().Sync(models.HPAKey("test-cluster", "production", name),
+			[]models.UnifiedAlert{newTestAlert(name, models.AnomalyMaxedOut, models.SeverityWarning)}, now)
+	}
+
+	snapshot := &models.HPASnapshot{Timestamp: now, Cluster: "test-cluster", Namespace: "production", Name: "api",
+		MinReplicas: 2, MaxReplicas: 10, CurrentReplicas: 4, DesiredReplicas: 4, CPUCurrent: 95}
+	watcher.processEvent(HPAEvent{Type: HPAEventUpdated, Cluster: "test-cluster", Namespace: "production", Name: "api", Snapshot: snapshot})
+
+	if len(store.batches) != 1 || len(store.batches[0]) != 1 || store.batches[0][0].HPAName != "api" {
+		t.Fatalf("Expected event to persist only the api alert, got %+v", store.batches)
+	}
+
+	// Scan grava o estado completo em um lote
+	watcher.Scan()
+	if last := store.batches[len(store.batches)-1]; len(last) != 21 {
+		t.Errorf("Expected scan to persist all 21 alerts, got %d", len(last))
+	}
+}
+
+func TestWatcherPersistsAcknowledgements(t *testing.T) {
+	session := newTestSession("test-cluster")
+	defer session.Shutdown()
+
+	cfg := &models.WatchdogConfig{ScanIntervalSeconds: 30, HistoryRetentionMinutes: 5}
+	store := storage.NewMemoryStore()
+
+	watcher := NewWatcher(cfg, session)
+	watcher.SetStorage(store)
+
+	now := time.Now()
+	scope := models.HPAKey("test-cluster", "production", "api")
+	watcher.AlertStore().Sync(scope, []models.UnifiedAlert{newTestAlert("api", models.AnomalyMaxedOut, models.SeverityCritical)}, now)
+	watcher.AlertStore().Acknowledge("test-cluster/production/api/MaxedOut", "alice", now)
+
+	if err := watcher.PersistAlerts(); err != nil {
+		t.Fatalf("PersistAlerts failed: %v", err)
+	}
+
+	// Novo processo com o mesmo storage
+	restarted := NewWatcher(cfg, session)
+	restarted.SetStorage(store)
+
+	if restored, err := restarted.RestoreAlerts(); err != nil || restored != 1 {
+		t.Fatalf("Expected 1 alert restored, got %d (%v)", restored, err)
+	}
+
+	restarted.AlertStore().Sync(scope, []models.UnifiedAlert{newTestAlert("api", models.AnomalyMaxedOut, models.SeverityCritical)}, time.Now())
+	if alerts := restarted.Alerts(); len(alerts) != 1 || !alerts[0].Acknowledged || alerts[0].AckedBy != "alice" {
+		t.Errorf("Expected acknowledgement kept across restart, got %+v", alerts)
+	}
+}
+
+func TestWatcherReload(t *testing.T) {
+	session := newMultiClusterSession(map[string]kubernetes.Interface{
+		"prod":    fake.NewClientset(newClusterObjects("production")...),
+		"staging": fake.NewClientset(newClusterObjects("apps")...),
+	})
+	defer session.Shutdown()
+
+	watcher := NewWatcher(&models.WatchdogConfig{ScanIntervalSeconds: 30, HistoryRetentionMinutes: 5}, session)
+	watcher.SetEnricher("staging", &fakeEnricher{})
+	watcher.SetAnalyzer(analyzer.NewEngine(config.NewThresholdManager(config.DefaultThresholds())))
+	watcher.Scan()
+
+	// Scan interval novo: loop sinalizado para recriar o ticker
+	watcher.SetConfig(&models.WatchdogConfig{ScanIntervalSeconds: 10, HistoryRetentionMinutes: 5})
+	if watcher.scanInterval() != 10*time.Second || len(watcher.reconfig) != 1 {
+		t.Errorf("Expected scan interval 10s with ticker reset pending, got %s", watcher.scanInterval())
+	}
+
+	if _, ok := watcher.GetTimeSeries(models.HPAKey("staging", "apps", "api")); !ok {
+		t.Fatal("Expected staging history before removal")
+	}
+
+	// Alerta de um HPA sem histórico (ex: restaurado do storage) também é resolvido
+	scope := models.HPAKey("staging", "apps", "api")
+	restored := newTestAlert("worker", models.AnomalyMaxedOut, models.SeverityCritical)
+	restored.Cluster = "staging"
+	watcher.AlertStore().Sync(models.HPAKey("staging", "production", "worker"), []models.UnifiedAlert{restored}, time.Now())
+
+	// Cluster excluído: sai da sessão e do watcher com histórico, enricher e alertas
+	if !watcher.RemoveCluster("staging") {
+		t.Fatal("Expected staging removed")
+	}
+	if clusters := watcher.Clusters(); len(clusters) != 1 || clusters[0].Name != "prod" {
+		t.Errorf("Expected only prod monitored, got %+v", clusters)
+	}
+	if _, ok := session.GetClient("staging"); ok {
+		t.Error("Expected staging removed from session")
+	}
+	if _, ok := watcher.GetTimeSeries(scope); ok || len(watcher.enrichers) != 0 {
+		t.Error("Expected staging history and enricher dropped")
+	}
+	if alerts := watcher.Alerts(); len(alerts) != 0 {
+		t.Errorf("Expected staging alerts resolved, got %+v", alerts)
+	}
+
+	if got := watcher.Scan(); got != 1 {
+		t.Errorf("Expected only prod collected after removal, got %d snapshots", got)
+	}
+	if watcher.RemoveCluster("staging") {
+		t.Error("Expected second removal to report missing cluster")
+	}
+}
@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// MemoryStore backend em memória (enable_persistence: false); os dados somem ao encerrar
+type MemoryStore struct {
+	snapshots []models.HPASnapshot
+	alerts    map[occurrenceKey]models.UnifiedAlert
+	mu        sync.RWMutex
+}
+
+// occurrenceKey identifica uma ocorrência: o ID do alerta se repete quando a condição volta
+type occurrenceKey struct {
+	id    string
+	start int64
+}
+
+// NewMemoryStore cria um store em memória vazio
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		alerts: make(map[occurrenceKey]models.UnifiedAlert),
+	}
+}
+
+// SaveSnapshots grava snapshots (append, só os campos escalares)
+func (m *MemoryStore) SaveSnapshots(ctx context.Context, snapshots []models.HPASnapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, snapshot := range snapshots {
+		m.snapshots = append(m.snapshots, persistedSnapshot(snapshot))
+	}
+	return nil
+}
+
+// SaveAlerts grava alertas, substituindo versões anteriores da mesma ocorrência (ID + início)
+func (m *MemoryStore) SaveAlerts(ctx context.Context, alerts []models.UnifiedAlert) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, alert := range alerts {
+		m.alerts[occurrenceKey{alert.ID, alert.Timestamp.UnixNano()}] = alert
+	}
+	return nil
+}
+
+// Snapshots retorna os snapshots do filtro, do mais antigo ao mais recente
+func (m *MemoryStore) Snapshots(ctx context.Context, q SnapshotQuery) ([]models.HPASnapshot, error) {
+	m.mu.RLock()
+	var result []models.HPASnapshot
+	for i := range m.snapshots {
+		if q.matchSnapshot(&m.snapshots[i]) {
+			result = append(result, m.snapshots[i])
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result, nil
+}
+
+// Alerts retorna os alertas ativos em algum momento do período, do mais antigo ao mais recente
+func (m *MemoryStore) Alerts(ctx context.Context, q AlertQuery) ([]models.UnifiedAlert, error) {
+	m.mu.RLock()
+	var result []models.UnifiedAlert
+	for _, alert := range m.alerts {
+		if q.matchAlert(&alert) {
+			result = append(result, alert)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].Timestamp.Equal(result[j].Timestamp) {
+			return result[i].Timestamp.Before(result[j].Timestamp)
+		}
+		return result[i].ID < result[j].ID
+	})
+
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[:q.Limit]
+	}
+	return result, nil
+}
+
+// Cleanup remove snapshots anteriores a before e alertas não vistos desde before
+func (m *MemoryStore) Cleanup(ctx context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	removed := 0
+
+	kept := m.snapshots[:0]
+	for _, snapshot := range m.snapshots {
+		if snapshot.Timestamp.Before(before) {
+			removed++
+			continue
+		}
+		kept = append(kept, snapshot)
+	}
+	m.snapshots = kept
+
+	for key, alert := range m.alerts {
+		if lastSeen(&alert).Before(before) {
+			delete(m.alerts, key)
+			removed++
+		}
+	}
+
+	return removed, nil
+}
+
+// Close não faz nada no backend em memória
+func (m *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,374 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/rs/zerolog/log"
+	_ "modernc.org/sqlite" // driver SQLite em Go puro (sem cgo)
+)
+
+// migrations schema do banco; a versão aplicada fica em PRAGMA user_version.
+// Nunca altere uma migration existente: adicione uma nova ao final.
+var migrations = []string{
+	// 1: snapshots e alertas (colunas indexadas para filtros + JSON completo)
+	`CREATE TABLE snapshots (
+		id        INTEGER PRIMARY KEY AUTOINCREMENT,
+		cluster   TEXT    NOT NULL,
+		namespace TEXT    NOT NULL,
+		name      TEXT    NOT NULL,
+		timestamp INTEGER NOT NULL,
+		data      TEXT    NOT NULL
+	);
+	CREATE INDEX idx_snapshots_hpa ON snapshots (cluster, namespace, name, timestamp);
+	CREATE INDEX idx_snapshots_timestamp ON snapshots (timestamp);
+
+	CREATE TABLE alerts (
+		id        TEXT    PRIMARY KEY,
+		cluster   TEXT    NOT NULL,
+		namespace TEXT    NOT NULL,
+		hpa       TEXT    NOT NULL,
+		source    INTEGER NOT NULL,
+		type      INTEGER NOT NULL,
+		severity  INTEGER NOT NULL,
+		status    TEXT    NOT NULL,
+		timestamp INTEGER NOT NULL,
+		last_seen INTEGER NOT NULL,
+		data      TEXT    NOT NULL
+	);
+	CREATE INDEX idx_alerts_timestamp ON alerts (timestamp);
+	CREATE INDEX idx_alerts_last_seen ON alerts (last_seen);`,
+
+	// 2: uma linha por ocorrência (ID + início); o ID é estável por HPA/tipo e se repete
+	`CREATE TABLE alerts_v2 (
+		id        TEXT    NOT NULL,
+		cluster   TEXT    NOT NULL,
+		namespace TEXT    NOT NULL,
+		hpa       TEXT    NOT NULL,
+		source    INTEGER NOT NULL,
+		type      INTEGER NOT NULL,
+		severity  INTEGER NOT NULL,
+		status    TEXT    NOT NULL,
+		timestamp INTEGER NOT NULL,
+		last_seen INTEGER NOT NULL,
+		data      TEXT    NOT NULL,
+		PRIMARY KEY (id, timestamp)
+	);
+	INSERT INTO alerts_v2 (id, cluster, namespace, hpa, source, type, severity, status, timestamp, last_seen, data)
+		SELECT id, cluster, namespace, hpa, source, type, severity, status, timestamp, last_seen, data FROM alerts;
+	DROP TABLE alerts;
+	ALTER TABLE alerts_v2 RENAME TO alerts;
+	CREATE INDEX idx_alerts_timestamp ON alerts (timestamp);
+	CREATE INDEX idx_alerts_last_seen ON alerts (last_seen);`,
+}
+
+// SQLiteStore backend SQLite (modernc.org/sqlite, sem cgo)
+type SQLiteStore struct {
+	db   *sql.DB
+	path string
+}
+
+// NewSQLiteStore abre (ou cria) o banco em path e aplica as migrations pendentes
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	dsn := "file:" + path + "?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// SQLite aceita um escritor por vez; uma conexão evita SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	s := &SQLiteStore{db: db, path: path}
+	if err := s.migrate(context.Background()); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// migrate aplica as migrations ainda não aplicadas, uma transação por migration
+func (s *SQLiteStore) migrate(ctx context.Context) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than supported (%d)", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := s.db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to apply migration %d: %w", i+1, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to update schema version: %w", err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", i+1, err)
+		}
+
+		log.Info().
+			Str("path", s.path).
+			Int("version", i+1).
+			Msg("Storage migration applied")
+	}
+
+	return nil
+}
+
+// SaveSnapshots grava snapshots (só os campos escalares) em uma transação
+func (s *SQLiteStore) SaveSnapshots(ctx context.Context, snapshots []models.HPASnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx,
+			`INSERT INTO snapshots (cluster, namespace, name, timestamp, data) VALUES (?, ?, ?, ?, ?)`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for i := range snapshots {
+			snapshot := persistedSnapshot(snapshots[i])
+
+			data, err := json.Marshal(&snapshot)
+			if err != nil {
+				return fmt.Errorf("failed to encode snapshot %s: %w", snapshot.Key(), err)
+			}
+
+			if _, err := stmt.ExecContext(ctx, snapshot.Cluster, snapshot.Namespace, snapshot.Name,
+				snapshot.Timestamp.UnixNano(), string(data)); err != nil {
+				return fmt.Errorf("failed to save snapshot %s: %w", snapshot.Key(), err)
+			}
+		}
+		return nil
+	})
+}
+
+// SaveAlerts grava alertas (upsert por ID e início da ocorrência) em uma transação
+func (s *SQLiteStore) SaveAlerts(ctx context.Context, alerts []models.UnifiedAlert) error {
+	if len(alerts) == 0 {
+		return nil
+	}
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx,
+			`INSERT INTO alerts (id, cluster, namespace, hpa, source, type, severity, status, timestamp, last_seen, data)
+			 VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			 ON CONFLICT (id, timestamp) DO UPDATE SET
+			   severity = excluded.severity, status = excluded.status,
+			   last_seen = excluded.last_seen, data = excluded.data`)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for i := range alerts {
+			alert := &alerts[i]
+
+			data, err := json.Marshal(alert)
+			if err != nil {
+				return fmt.Errorf("failed to encode alert %s: %w", alert.ID, err)
+			}
+
+			if _, err := stmt.ExecContext(ctx, alert.ID, alert.Cluster, alert.Namespace, alert.HPAName,
+				int(alert.Source), int(alert.Type), int(alert.Severity), alert.Status,
+				alert.Timestamp.UnixNano(), lastSeen(alert).UnixNano(), string(data)); err != nil {
+				return fmt.Errorf("failed to save alert %s: %w", alert.ID, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Snapshots retorna os snapshots do filtro, do mais antigo ao mais recente
+func (s *SQLiteStore) Snapshots(ctx context.Context, q SnapshotQuery) ([]models.HPASnapshot, error) {
+	var where []string
+	var args []interface{}
+
+	if !q.Since.IsZero() {
+		where = append(where, "timestamp >= ?")
+		args = append(args, q.Since.UnixNano())
+	}
+	if !q.Until.IsZero() {
+		where = append(where, "timestamp <= ?")
+		args = append(args, q.Until.UnixNano())
+	}
+	where, args = appendEq(where, args, "cluster", q.Cluster)
+	where, args = appendEq(where, args, "namespace", q.Namespace)
+	where, args = appendEq(where, args, "name", q.Name)
+
+	query := "SELECT data FROM snapshots" + whereClause(where) + " ORDER BY timestamp, id" + limitClause(q.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	var snapshots []models.HPASnapshot
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var snapshot models.HPASnapshot
+		if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, rows.Err()
+}
+
+// Alerts retorna os alertas ativos em algum momento do período, do mais antigo ao mais recente
+func (s *SQLiteStore) Alerts(ctx context.Context, q AlertQuery) ([]models.UnifiedAlert, error) {
+	var where []string
+	var args []interface{}
+
+	if !q.Since.IsZero() {
+		where = append(where, "last_seen >= ?")
+		args = append(args, q.Since.UnixNano())
+	}
+	if !q.Until.IsZero() {
+		where = append(where, "timestamp <= ?")
+		args = append(args, q.Until.UnixNano())
+	}
+	where, args = appendEq(where, args, "cluster", q.Cluster)
+	where, args = appendEq(where, args, "namespace", q.Namespace)
+	where, args = appendEq(where, args, "hpa", q.HPAName)
+
+	if len(q.Severities) > 0 {
+		values := make([]interface{}, len(q.Severities))
+		for i, severity := range q.Severities {
+			values[i] = int(severity)
+		}
+		where = append(where, "severity IN ("+placeholders(len(values))+")")
+		args = append(args, values...)
+	}
+	if len(q.Types) > 0 {
+		values := make([]interface{}, len(q.Types))
+		for i, anomaly := range q.Types {
+			values[i] = int(anomaly)
+		}
+		where = append(where, "type IN ("+placeholders(len(values))+")")
+		args = append(args, values...)
+	}
+
+	query := "SELECT data FROM alerts" + whereClause(where) + " ORDER BY timestamp, id" + limitClause(q.Limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	var alerts []models.UnifiedAlert
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		var alert models.UnifiedAlert
+		if err := json.Unmarshal([]byte(data), &alert); err != nil {
+			return nil, fmt.Errorf("failed to decode alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+
+	return alerts, rows.Err()
+}
+
+// Cleanup remove snapshots anteriores a before e alertas não vistos desde before
+func (s *SQLiteStore) Cleanup(ctx context.Context, before time.Time) (int, error) {
+	removed := 0
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		for _, query := range []string{
+			"DELETE FROM snapshots WHERE timestamp < ?",
+			"DELETE FROM alerts WHERE last_seen < ?",
+		} {
+			result, err := tx.ExecContext(ctx, query, before.UnixNano())
+			if err != nil {
+				return err
+			}
+			n, _ := result.RowsAffected()
+			removed += int(n)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to cleanup storage: %w", err)
+	}
+
+	return removed, nil
+}
+
+// Close fecha o banco
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// inTx executa fn em uma transação (rollback em erro)
+func (s *SQLiteStore) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// appendEq adiciona "column = ?" se value não for vazio
+func appendEq(where []string, args []interface{}, column, value string) ([]string, []interface{}) {
+	if value == "" {
+		return where, args
+	}
+	return append(where, column+" = ?"), append(args, value)
+}
+
+func whereClause(where []string) string {
+	if len(where) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(where, " AND ")
+}
+
+func limitClause(limit int) string {
+	if limit <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(" LIMIT %d", limit)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// DefaultRetention usado quando a config não define storage.retention_days
+const DefaultRetention = 7 * 24 * time.Hour
+
+// Store persistência de snapshots e alertas
+type Store interface {
+	// SaveSnapshots grava snapshots (append), só com os campos escalares (ver persistedSnapshot)
+	SaveSnapshots(ctx context.Context, snapshots []models.HPASnapshot) error
+
+	// SaveAlerts grava alertas, substituindo versões anteriores da mesma ocorrência (ID + início).
+	// Cada vez que a condição volta a disparar é uma ocorrência nova, com linha própria.
+	SaveAlerts(ctx context.Context, alerts []models.UnifiedAlert) error
+
+	// Snapshots retorna os snapshots do filtro, do mais antigo ao mais recente
+	Snapshots(ctx context.Context, q SnapshotQuery) ([]models.HPASnapshot, error)
+
+	// Alerts retorna os alertas ativos em algum momento do período, do mais antigo ao mais recente
+	Alerts(ctx context.Context, q AlertQuery) ([]models.UnifiedAlert, error)
+
+	// Cleanup remove snapshots anteriores a before e alertas não vistos desde before.
+	// Retorna quantos registros foram removidos.
+	Cleanup(ctx context.Context, before time.Time) (int, error)
+
+	Close() error
+}
+
+// persistedSnapshot cópia do snapshot só com os campos escalares (os das colunas do export).
+// As séries de histórico cobrem só os últimos minutos e se repetem quase inteiras a cada scan;
+// o histórico persistido é a própria sequência de snapshots, um por scan.
+func persistedSnapshot(snapshot models.HPASnapshot) models.HPASnapshot {
+	snapshot.CPUHistory = nil
+	snapshot.MemoryHistory = nil
+	snapshot.ReplicaHistory = nil
+	snapshot.MetricSpecs = nil
+	snapshot.Conditions = nil
+	return snapshot
+}
+
+// SnapshotQuery filtro de snapshots (campos vazios = sem filtro)
+type SnapshotQuery struct {
+	Since     time.Time
+	Until     time.Time
+	Cluster   string
+	Namespace string
+	Name      string
+	Limit     int // 0 = sem limite
+}
+
+// AlertQuery filtro de alertas (campos vazios = sem filtro)
+type AlertQuery struct {
+	Since      time.Time // Alertas vistos a partir de Since (LastSeen >= Since)
+	Until      time.Time // Alertas iniciados até Until (Timestamp <= Until)
+	Cluster    string
+	Namespace  string
+	HPAName    string
+	Severities []models.AlertSeverity
+	Types      []models.AnomalyType
+	Limit      int // 0 = sem limite
+}
+
+// Open abre o store da config: SQLite em storage.persistence_path com enable_persistence,
+// senão em memória
+func Open(cfg *models.WatchdogConfig) (Store, error) {
+	if !cfg.EnablePersistence {
+		return NewMemoryStore(), nil
+	}
+
+	if cfg.PersistencePath == "" {
+		return nil, fmt.Errorf("storage.persistence_path is required when enable_persistence is true")
+	}
+
+	path, err := config.ExpandPath(cfg.PersistencePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return NewSQLiteStore(path)
+}
+
+// Retention retorna por quanto tempo os dados persistidos são mantidos
+func Retention(cfg *models.WatchdogConfig) time.Duration {
+	if cfg.PersistenceRetentionDays < 1 {
+		return DefaultRetention
+	}
+	return time.Duration(cfg.PersistenceRetentionDays) * 24 * time.Hour
+}
+
+// matchSnapshot verifica se o snapshot satisfaz o filtro
+func (q SnapshotQuery) matchSnapshot(s *models.HPASnapshot) bool {
+	if !q.Since.IsZero() && s.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && s.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.Cluster != "" && s.Cluster != q.Cluster {
+		return false
+	}
+	if q.Namespace != "" && s.Namespace != q.Namespace {
+		return false
+	}
+	if q.Name != "" && s.Name != q.Name {
+		return false
+	}
+	return true
+}
+
+// matchAlert verifica se o alerta satisfaz o filtro
+func (q AlertQuery) matchAlert(a *models.UnifiedAlert) bool {
+	if !q.Since.IsZero() && lastSeen(a).Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && a.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.Cluster != "" && a.Cluster != q.Cluster {
+		return false
+	}
+	if q.Namespace != "" && a.Namespace != q.Namespace {
+		return false
+	}
+	if q.HPAName != "" && a.HPAName != q.HPAName {
+		return false
+	}
+	if len(q.Severities) > 0 && !containsSeverity(q.Severities, a.Severity) {
+		return false
+	}
+	if len(q.Types) > 0 && !containsType(q.Types, a.Type) {
+		return false
+	}
+	return true
+}
+
+// lastSeen última vez que o alerta esteve ativo (Timestamp se LastSeen não foi preenchido)
+func lastSeen(a *models.UnifiedAlert) time.Time {
+	if a.LastSeen.IsZero() {
+		return a.Timestamp
+	}
+	return a.LastSeen
+}
+
+func containsSeverity(list []models.AlertSeverity, severity models.AlertSeverity) bool {
+	for _, s := range list {
+		if s == severity {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(list []models.AnomalyType, anomaly models.AnomalyType) bool {
+	for _, t := range list {
+		if t == anomaly {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,320 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+)
+
+// backends cria um store de cada backend para os testes
+func backends(t *testing.T) map[string]Store {
+	t.Helper()
+
+	sqlite, err := NewSQLiteStore(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	t.Cleanup(func() { sqlite.Close() })
+
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"sqlite": sqlite,
+	}
+}
+
+func newSnapshot(name string, at time.Time, replicas int32) models.HPASnapshot {
+	return models.HPASnapshot{
+		Timestamp:       at,
+		Cluster:         "prod",
+		Namespace:       "production",
+		Name:            name,
+		CurrentReplicas: replicas,
+		CPUHistory:      []float64{50, 60},
+	}
+}
+
+func TestStoreSnapshots(t *testing.T) {
+	start := time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC)
+
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			err := store.SaveSnapshots(ctx, []models.HPASnapshot{
+				newSnapshot("api", start.Add(2*time.Minute), 5),
+				newSnapshot("api", start, 3),
+				newSnapshot("worker", start.Add(time.Minute), 2),
+			})
+			if err != nil {
+				t.Fatalf("SaveSnapshots failed: %v", err)
+			}
+
+			snapshots, err := store.Snapshots(ctx, SnapshotQuery{Name: "api"})
+			if err != nil {
+				t.Fatalf("Snapshots failed: %v", err)
+			}
+			if len(snapshots) != 2 || snapshots[0].CurrentReplicas != 3 || snapshots[1].CurrentReplicas != 5 {
+				t.Fatalf("Expected api snapshots in chronological order, got %+v", snapshots)
+			}
+			if !snapshots[0].Timestamp.Equal(start) || snapshots[0].CPUHistory != nil {
+				t.Errorf("Expected snapshot round-trip with timestamp and without history, got %+v", snapshots[0])
+			}
+
+			snapshots, _ = store.Snapshots(ctx, SnapshotQuery{Since: start.Add(30 * time.Second), Until: start.Add(90 * time.Second)})
+			if len(snapshots) != 1 || snapshots[0].Name != "worker" {
+				t.Errorf("Expected only worker within time range, got %+v", snapshots)
+			}
+
+			if snapshots, _ := store.Snapshots(ctx, SnapshotQuery{Limit: 2}); len(snapshots) != 2 {
+				t.Errorf("Expected limit of 2, got %d", len(snapshots))
+			}
+		})
+	}
+}
+
+func TestStoreAlerts(t *testing.T) {
+	start := time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC)
+
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			maxed := models.UnifiedAlert{
+				ID: "prod/production/api/MaxedOut", Source: models.AlertSourceWatchdog, Type: models.AnomalyMaxedOut,
+				Severity: models.SeverityWarning, Cluster: "prod", Namespace: "production", HPAName: "api",
+				Timestamp: start, LastSeen: start.Add(10 * time.Minute), Status: models.AlertStatusActive,
+				Snapshot: &models.HPASnapshot{Name: "api", CurrentReplicas: 10},
+			}
+			spike := models.UnifiedAlert{
+				ID: "prod/production/worker/CPUSpike", Source: models.AlertSourceWatchdog, Type: models.AnomalyCPUSpike,
+				Severity: models.SeverityCritical, Cluster: "prod", Namespace: "production", HPAName: "worker",
+				Timestamp: start.Add(time.Hour), LastSeen: start.Add(2 * time.Hour),
+			}
+
+			if err := store.SaveAlerts(ctx, []models.UnifiedAlert{maxed, spike}); err != nil {
+				t.Fatalf("SaveAlerts failed: %v", err)
+			}
+
+			// Upsert: nova versão do mesmo alerta (escalou e resolveu)
+			resolvedAt := start.Add(20 * time.Minute)
+			maxed.Severity = models.SeverityCritical
+			maxed.Status = models.AlertStatusResolved
+			maxed.ResolvedAt = &resolvedAt
+			maxed.LastSeen = resolvedAt
+			if err := store.SaveAlerts(ctx, []models.UnifiedAlert{maxed}); err != nil {
+				t.Fatalf("SaveAlerts (upsert) failed: %v", err)
+			}
+
+			alerts, err := store.Alerts(ctx, AlertQuery{})
+			if err != nil {
+				t.Fatalf("Alerts failed: %v", err)
+			}
+			if len(alerts) != 2 || alerts[0].ID != maxed.ID {
+				t.Fatalf("Expected 2 alerts ordered by start, got %+v", alerts)
+			}
+			if alerts[0].Severity != models.SeverityCritical || alerts[0].ResolvedAt == nil || alerts[0].Snapshot == nil {
+				t.Errorf("Expected latest version with snapshot, got %+v", alerts[0])
+			}
+
+			// Ativo em algum momento entre 22:15 e 22:30: só o MaxedOut
+			alerts, _ = store.Alerts(ctx, AlertQuery{Since: start.Add(15 * time.Minute), Until: start.Add(30 * time.Minute)})
+			if len(alerts) != 1 || alerts[0].ID != maxed.ID {
+				t.Errorf("Expected only alerts active within range, got %+v", alerts)
+			}
+
+			alerts, _ = store.Alerts(ctx, AlertQuery{Severities: []models.AlertSeverity{models.SeverityCritical}, Types: []models.AnomalyType{models.AnomalyCPUSpike}})
+			if len(alerts) != 1 || alerts[0].ID != spike.ID {
+				t.Errorf("Expected severity and type filters, got %+v", alerts)
+			}
+
+			if alerts, _ := store.Alerts(ctx, AlertQuery{HPAName: "worker", Cluster: "staging"}); len(alerts) != 0 {
+				t.Errorf("Expected no alerts for another cluster, got %+v", alerts)
+			}
+		})
+	}
+}
+
+func TestStoreAlertOccurrences(t *testing.T) {
+	start := time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC)
+
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// Incidente da noite: dispara, escala e resolve
+			first := models.UnifiedAlert{
+				ID: "prod/production/api/MaxedOut", Type: models.AnomalyMaxedOut, Severity: models.SeverityWarning,
+				Cluster: "prod", Namespace: "production", HPAName: "api",
+				Timestamp: start, LastSeen: start, Status: models.AlertStatusActive,
+			}
+			store.SaveAlerts(ctx, []models.UnifiedAlert{first})
+
+			resolvedAt := start.Add(30 * time.Minute)
+			first.Severity = models.SeverityCritical
+			first.Status = models.AlertStatusResolved
+			first.LastSeen = resolvedAt
+			first.ResolvedAt = &resolvedAt
+			store.SaveAlerts(ctx, []models.UnifiedAlert{first})
+
+			// Mesma condição volta de manhã: mesmo ID, ocorrência nova
+			second := models.UnifiedAlert{
+				ID: first.ID, Type: models.AnomalyMaxedOut, Severity: models.SeverityWarning,
+				Cluster: "prod", Namespace: "production", HPAName: "api",
+				Timestamp: start.Add(10 * time.Hour), LastSeen: start.Add(10 * time.Hour), Status: models.AlertStatusActive,
+			}
+			if err := store.SaveAlerts(ctx, []models.UnifiedAlert{second}); err != nil {
+				t.Fatalf("SaveAlerts failed: %v", err)
+			}
+
+			alerts, err := store.Alerts(ctx, AlertQuery{})
+			if err != nil {
+				t.Fatalf("Alerts failed: %v", err)
+			}
+			if len(alerts) != 2 {
+				t.Fatalf("Expected both occurrences kept, got %+v", alerts)
+			}
+			if !alerts[0].Timestamp.Equal(start) || alerts[0].Severity != models.SeverityCritical || alerts[0].ResolvedAt == nil {
+				t.Errorf("Expected first incident with its start, severity and resolution, got %+v", alerts[0])
+			}
+			if !alerts[1].Timestamp.Equal(second.Timestamp) || alerts[1].Status != models.AlertStatusActive {
+				t.Errorf("Expected second incident active, got %+v", alerts[1])
+			}
+
+			// Consulta da noite encontra só o primeiro incidente
+			alerts, _ = store.Alerts(ctx, AlertQuery{Since: start, Until: start.Add(time.Hour)})
+			if len(alerts) != 1 || !alerts[0].Timestamp.Equal(start) {
+				t.Errorf("Expected only the night incident, got %+v", alerts)
+			}
+		})
+	}
+}
+
+func TestStoreCleanup(t *testing.T) {
+	start := time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC)
+
+	for name, store := range backends(t) {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			store.SaveSnapshots(ctx, []models.HPASnapshot{
+				newSnapshot("api", start, 3),
+				newSnapshot("api", start.Add(48*time.Hour), 4),
+			})
+			store.SaveAlerts(ctx, []models.UnifiedAlert{
+				{ID: "old", Timestamp: start, LastSeen: start.Add(time.Hour)},
+				{ID: "recent", Timestamp: start, LastSeen: start.Add(48 * time.Hour)},
+			})
+
+			removed, err := store.Cleanup(ctx, start.Add(24*time.Hour))
+			if err != nil {
+				t.Fatalf("Cleanup failed: %v", err)
+			}
+			if removed != 2 {
+				t.Errorf("Expected 2 records removed, got %d", removed)
+			}
+
+			snapshots, _ := store.Snapshots(ctx, SnapshotQuery{})
+			alerts, _ := store.Alerts(ctx, AlertQuery{})
+			if len(snapshots) != 1 || len(alerts) != 1 || alerts[0].ID != "recent" {
+				t.Errorf("Expected only recent data kept, got %d snapshots and %+v", len(snapshots), alerts)
+			}
+		})
+	}
+}
+
+func TestSQLiteSnapshotRowSize(t *testing.T) {
+	store, err := NewSQLiteStore(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	defer store.Close()
+
+	snapshot := newSnapshot("payments-api", time.Now(), 5)
+	snapshot.CPUHistory = make([]float64, 60)
+	snapshot.MemoryHistory = make([]float64, 60)
+	snapshot.ReplicaHistory = make([]int32, 60)
+	snapshot.MetricSpecs = []string{"resource:cpu/Utilization=70", "resource:memory/Utilization=80"}
+	snapshot.Conditions = []models.HPACondition{
+		{Type: "AbleToScale", Status: "True", Reason: "ReadyForNewScale"},
+		{Type: "ScalingActive", Status: "True", Reason: "ValidMetricFound"},
+	}
+	if err := store.SaveSnapshots(context.Background(), []models.HPASnapshot{snapshot}); err != nil {
+		t.Fatalf("SaveSnapshots failed: %v", err)
+	}
+
+	var data string
+	if err := store.db.QueryRow("SELECT data FROM snapshots").Scan(&data); err != nil {
+		t.Fatalf("Failed to read snapshot row: %v", err)
+	}
+
+	// Tamanho esperado documentado no README (~0,7 KB de JSON por snapshot)
+	if len(data) > 1024 || strings.Contains(data, "[") {
+		t.Errorf("Expected only scalar fields (< 1 KB), got %d bytes: %s", len(data), data)
+	}
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+
+	store, err := NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStore failed: %v", err)
+	}
+	store.SaveSnapshots(context.Background(), []models.HPASnapshot{newSnapshot("api", time.Now(), 3)})
+	store.Close()
+
+	// Reabrir não reaplica migrations nem perde dados
+	store, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if snapshots, _ := store.Snapshots(context.Background(), SnapshotQuery{}); len(snapshots) != 1 {
+		t.Errorf("Expected data kept across reopen, got %d snapshots", len(snapshots))
+	}
+
+	// Banco no schema 1 (alertas por ID): migration 2 mantém os alertas gravados
+	store.db.Exec("PRAGMA user_version = 1")
+	store.db.Exec(`DROP TABLE alerts; CREATE TABLE alerts (id TEXT PRIMARY KEY, cluster TEXT NOT NULL, namespace TEXT NOT NULL,
+		hpa TEXT NOT NULL, source INTEGER NOT NULL, type INTEGER NOT NULL, severity INTEGER NOT NULL, status TEXT NOT NULL,
+		timestamp INTEGER NOT NULL, last_seen INTEGER NOT NULL, data TEXT NOT NULL);
+		INSERT INTO alerts VALUES ('legacy', 'prod', 'production', 'api', 0, 0, 0, 'active', 1, 1, '{"ID":"legacy"}')`)
+	store.Close()
+	store, err = NewSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("Reopen after migration failed: %v", err)
+	}
+	if alerts, _ := store.Alerts(context.Background(), AlertQuery{}); len(alerts) != 1 || alerts[0].ID != "legacy" {
+		t.Errorf("Expected alerts kept by migration 2, got %+v", alerts)
+	}
+
+	// Banco de uma versão mais nova é recusado
+	store.db.Exec("PRAGMA user_version = 99")
+	store.Close()
+	if _, err := NewSQLiteStore(path); err == nil {
+		t.Error("Expected error for newer schema version")
+	}
+}
+
+func TestOpen(t *testing.T) {
+	store, err := Open(&models.WatchdogConfig{EnablePersistence: false})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if _, ok := store.(*MemoryStore); !ok {
+		t.Errorf("Expected memory store without persistence, got %T", store)
+	}
+
+	path := filepath.Join(t.TempDir(), "nested", "history.db")
+	store, err = Open(&models.WatchdogConfig{EnablePersistence: true, PersistencePath: path})
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer store.Close()
+	if _, ok := store.(*SQLiteStore); !ok {
+		t.Errorf("Expected SQLite store with persistence, got %T", store)
+	}
+}
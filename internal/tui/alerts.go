@@ -0,0 +1,527 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// alertOrder ordem da lista de alertas
+type alertOrder int
+
+const (
+	orderBySeverity alertOrder = iota // Mais severos primeiro; entre eles, os mais antigos
+	orderByAge                        // Mais recentes primeiro
+)
+
+func (o alertOrder) String() string {
+	if o == orderByAge {
+		return "mais recentes"
+	}
+	return "severidade"
+}
+
+// statusKind tipo da mensagem de status do rodapé
+type statusKind int
+
+const (
+	statusInfo statusKind = iota
+	statusOK
+	statusError
+)
+
+// alertFilters filtros da aba de alertas ("" = todos)
+type alertFilters struct {
+	source  string // "Watchdog" ou "Alertmanager"
+	cluster string
+	anomaly string // AnomalyType.String()
+}
+
+// active indica se algum filtro está aplicado
+func (f alertFilters) active() bool {
+	return f.source != "" || f.cluster != "" || f.anomaly != ""
+}
+
+// match verifica se o alerta passa pelos filtros
+func (f alertFilters) match(a *models.UnifiedAlert) bool {
+	if f.source != "" && a.Source.String() != f.source {
+		return false
+	}
+	if f.cluster != "" && a.Cluster != f.cluster {
+		return false
+	}
+	if f.anomaly != "" && a.Type.String() != f.anomaly {
+		return false
+	}
+	return true
+}
+
+// applyAlertFilters recalcula a lista filtrada e ordenada da aba de alertas
+func (m *Model) applyAlertFilters() {
+	m.filtered = m.filtered[:0]
+	for i := range m.alerts {
+		if m.filters.match(&m.alerts[i]) {
+			m.filtered = append(m.filtered, m.alerts[i])
+		}
+	}
+
+	sort.SliceStable(m.filtered, func(i, j int) bool {
+		a, b := &m.filtered[i], &m.filtered[j]
+
+		if m.alertOrder == orderBySeverity && a.Severity != b.Severity {
+			return a.Severity > b.Severity
+		}
+		if !a.Timestamp.Equal(b.Timestamp) {
+			if m.alertOrder == orderByAge {
+				return a.Timestamp.After(b.Timestamp)
+			}
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		return a.ID < b.ID
+	})
+}
+
+// selectedAlertID ID do alerta selecionado na aba de alertas
+func (m *Model) selectedAlertID() string {
+	if m.listCursor < 0 || m.listCursor >= len(m.filtered) {
+		return ""
+	}
+	return m.filtered[m.listCursor].ID
+}
+
+// selectAlert move o cursor da aba de alertas para o ID indicado (se visível)
+func (m *Model) selectAlert(id string) {
+	if id == "" {
+		return
+	}
+	for i := range m.filtered {
+		if m.filtered[i].ID == id {
+			m.listCursor = i
+			return
+		}
+	}
+}
+
+// refilter reaplica filtros/ordem mantendo o alerta selecionado
+func (m *Model) refilter() {
+	selected := m.selectedAlertID()
+	m.applyAlertFilters()
+	m.listCursor = 0
+	m.selectAlert(selected)
+	m.clampCursors()
+}
+
+// cycleFilter avança o filtro para o próximo valor presente nos alertas ("" = todos)
+func cycleFilter(current string, values []string) string {
+	options := append([]string{""}, values...)
+	for i, value := range options {
+		if value == current {
+			return options[(i+1)%len(options)]
+		}
+	}
+	return ""
+}
+
+// filterValues valores distintos (ordenados) de um campo dos alertas
+func (m *Model) filterValues(field func(*models.UnifiedAlert) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for i := range m.alerts {
+		if value := field(&m.alerts[i]); value != "" && !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// acknowledge reconhece o alerta em background (pode criar silence no Alertmanager)
+func (m *Model) acknowledge(alert *models.UnifiedAlert) tea.Cmd {
+	switch {
+	case alert == nil:
+		return nil
+	case m.opts.Acknowledge == nil:
+		m.setStatus(statusError, "Reconhecimento indisponível")
+		return nil
+	case alert.Acknowledged:
+		m.setStatus(statusInfo, fmt.Sprintf("Alerta já reconhecido por %s", alert.AckedBy))
+		return nil
+	case m.acking[alert.ID]:
+		return nil
+	case m.opts.SilenceDuration > 0 && alert.Source == models.AlertSourceAlertmanager:
+		// Silence exige duração e comentário: confirmados no prompt
+		m.silencePrompt = newSilencePrompt(alert, m.opts.SilenceDuration)
+		return nil
+	}
+
+	return m.startAck(alert.ID, "", 0)
+}
+
+// startAck dispara o reconhecimento em background
+func (m *Model) startAck(id, comment string, duration time.Duration) tea.Cmd {
+	by, ack := m.ackUser, m.opts.Acknowledge
+	m.acking[id] = true
+	m.setStatus(statusInfo, "Reconhecendo "+id+"...")
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ackTimeout)
+		defer cancel()
+
+		return ackDoneMsg{id: id, by: by, err: ack(ctx, id, by, comment, duration, time.Now())}
+	}
+}
+
+// findAlert alerta ativo pelo ID
+func (m *Model) findAlert(id string) *models.UnifiedAlert {
+	for i := range m.alerts {
+		if m.alerts[i].ID == id {
+			return &m.alerts[i]
+		}
+	}
+	return nil
+}
+
+// handleAlertsKey teclas da aba de alertas
+func (m Model) handleAlertsKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "up", "k":
+		m.listCursor--
+	case "down", "j":
+		m.listCursor++
+	case "pgup":
+		m.listCursor -= m.alertListRows()
+	case "pgdown":
+		m.listCursor += m.alertListRows()
+	case "home", "g":
+		m.listCursor = 0
+	case "end", "G":
+		m.listCursor = len(m.filtered) - 1
+
+	case "enter", "d":
+		m.showDetail = !m.showDetail
+	case "esc":
+		if m.showDetail {
+			m.showDetail = false
+		} else {
+			m.view = viewDashboard
+		}
+
+	case "f":
+		m.filters.source = cycleFilter(m.filters.source, []string{
+			models.AlertSourceWatchdog.String(), models.AlertSourceAlertmanager.String(),
+		})
+		m.refilter()
+	case "c":
+		m.filters.cluster = cycleFilter(m.filters.cluster, m.filterValues(func(a *models.UnifiedAlert) string { return a.Cluster }))
+		m.refilter()
+	case "t":
+		m.filters.anomaly = cycleFilter(m.filters.anomaly, m.filterValues(func(a *models.UnifiedAlert) string { return a.Type.String() }))
+		m.refilter()
+	case "0", "backspace":
+		m.filters = alertFilters{}
+		m.refilter()
+	case "o":
+		m.alertOrder = (m.alertOrder + 1) % 2
+		m.refilter()
+
+	case "a":
+		var selected *models.UnifiedAlert
+		if m.listCursor >= 0 && m.listCursor < len(m.filtered) {
+			selected = &m.filtered[m.listCursor]
+		}
+		cmd := m.acknowledge(selected)
+		return m, cmd
+	}
+
+	m.clampCursors()
+	return m, nil
+}
+
+// renderAlertsView aba de alertas: filtros, lista e painel de detalhes
+func (m Model) renderAlertsView() string {
+	sections := []string{m.renderTitle(), m.renderFilterBar(), m.renderAlertList()}
+	if m.showDetail {
+		sections = append(sections, m.renderAlertDetail())
+	}
+	sections = append(sections, m.renderAlertsFooter())
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderFilterBar filtros e ordem atuais
+func (m Model) renderFilterBar() string {
+	value := func(v, all string) string {
+		if v == "" {
+			return all
+		}
+		return m.styles.Warning.Render(v)
+	}
+
+	bar := fmt.Sprintf(" Fonte: %s [f]   Cluster: %s [c]   Tipo: %s [t]   Ordem: %s [o]",
+		value(m.filters.source, "todas"), value(m.filters.cluster, "todos"), value(m.filters.anomaly, "todos"), m.alertOrder)
+	if m.filters.active() {
+		bar += "   [0] limpar"
+	}
+	return bar
+}
+
+// alertColumns colunas da lista de alertas
+func (m Model) alertColumns() []column {
+	return []column{
+		{"SEV", 10},
+		{"IDADE", 6},
+		{"ACK", 3},
+		{"FONTE", 12},
+		{"CLUSTER", widest(7, 24, len(m.filtered), func(i int) string { return m.filtered[i].Cluster })},
+		{"NAMESPACE/HPA", widest(13, 40, len(m.filtered), func(i int) string { return alertTarget(&m.filtered[i]) })},
+		{"TIPO", 18},
+		{"RESUMO", 0},
+	}
+}
+
+// renderAlertList lista filtrada de alertas
+func (m Model) renderAlertList() string {
+	columns := m.alertColumns()
+
+	// RESUMO ocupa o resto da linha
+	used := 2
+	for _, c := range columns[:len(columns)-1] {
+		used += c.width + 1
+	}
+	columns[len(columns)-1].width = m.innerWidth() - used
+	if columns[len(columns)-1].width < 10 {
+		columns[len(columns)-1].width = 10
+	}
+
+	lines := []string{m.styles.Header.Render("  " + formatRow(columns, headerCells(columns)))}
+
+	if len(m.filtered) == 0 {
+		message := "Nenhum alerta ativo"
+		if m.filters.active() {
+			message = "Nenhum alerta com os filtros atuais"
+		}
+		lines = append(lines, m.styles.OK.Render(message))
+	}
+
+	rows := m.alertListRows()
+	start := scrollStart(m.listCursor, rows)
+	for i := start; i < len(m.filtered) && i < start+rows; i++ {
+		alert := &m.filtered[i]
+
+		ack := ""
+		switch {
+		case m.acking[alert.ID]:
+			ack = "…"
+		case alert.Acknowledged:
+			ack = "✓"
+		}
+
+		cells := []string{
+			"● " + severityLabel(alert.Severity),
+			formatAge(m.refreshedAt.Sub(alert.Timestamp)),
+			ack,
+			alert.Source.String(),
+			alert.Cluster,
+			alertTarget(alert),
+			alert.Type.String(),
+			alert.Summary,
+		}
+
+		if i == m.listCursor {
+			lines = append(lines, m.styles.Selected.Render(cursorMark+formatRow(columns, cells)))
+			continue
+		}
+
+		styles := make([]lipgloss.Style, len(columns))
+		styles[0] = m.severityStyle(alert.Severity)
+		styles[1] = m.styles.Muted
+		styles[2] = m.styles.OK
+		styles[3] = m.styles.Muted
+		lines = append(lines, "  "+formatStyledRow(columns, cells, styles))
+	}
+
+	title := fmt.Sprintf("Alertas (%d de %d)", len(m.filtered), len(m.alerts))
+	return m.renderPanel(title, lines, !m.showDetail)
+}
+
+// alertDetailLines conteúdo do painel de detalhes do alerta selecionado
+func (m Model) alertDetailLines() []string {
+	if m.listCursor < 0 || m.listCursor >= len(m.filtered) {
+		return []string{m.styles.Muted.Render("Nenhum alerta selecionado")}
+	}
+	a := &m.filtered[m.listCursor]
+
+	lines := []string{
+		fmt.Sprintf("%s  %s  %s  fonte %s  status %s",
+			m.severityStyle(a.Severity).Render("● "+severityLabel(a.Severity)), a.Type, a.ID, a.Source, orDash(a.Status)),
+		fmt.Sprintf("Início %s (há %s)", a.Timestamp.Format("02/01 15:04:05"), formatAge(m.refreshedAt.Sub(a.Timestamp))),
+	}
+	if !a.LastSeen.IsZero() {
+		lines[1] += fmt.Sprintf("  •  visto %s  •  %d ocorrência(s)", a.LastSeen.Format("15:04:05"), a.Occurrences)
+	}
+
+	switch {
+	case m.acking[a.ID]:
+		lines = append(lines, "Reconhecimento em andamento...")
+	case a.Acknowledged && a.AckedAt != nil:
+		lines = append(lines, m.styles.OK.Render(fmt.Sprintf("✓ Reconhecido por %s às %s", a.AckedBy, a.AckedAt.Format("15:04:05"))))
+	default:
+		lines = append(lines, m.styles.Warning.Render("Não reconhecido  [a] reconhecer como "+m.ackUser))
+	}
+	if len(a.SilencedBy) > 0 {
+		lines = append(lines, "Silences: "+strings.Join(a.SilencedBy, ", "))
+	}
+
+	if a.Summary != "" {
+		lines = append(lines, "Resumo: "+a.Summary)
+	}
+	if a.Description != "" && a.Description != a.Summary {
+		lines = append(lines, "Descrição: "+a.Description)
+	}
+
+	if s := a.Snapshot; s != nil {
+		lines = append(lines, "", m.styles.Header.Render(fmt.Sprintf("Snapshot (%s)", s.Timestamp.Format("15:04:05"))),
+			fmt.Sprintf("Réplicas %d→%d (min %d, max %d)  •  CPU %s  •  Memória %s  •  %s",
+				s.CurrentReplicas, s.DesiredReplicas, s.MinReplicas, s.MaxReplicas,
+				formatUsage(s.CPUCurrent, s.CPUTarget), formatUsage(s.MemoryCurrent, s.MemoryTarget), s.DataSource))
+
+		if s.CPURequest != "" || s.MemoryRequest != "" {
+			lines = append(lines, fmt.Sprintf("Resources: CPU %s/%s  •  Memória %s/%s",
+				orDash(s.CPURequest), orDash(s.CPULimit), orDash(s.MemoryRequest), orDash(s.MemoryLimit)))
+		}
+		if s.RequestRate > 0 || s.ErrorRate > 0 || s.P95Latency > 0 {
+			lines = append(lines, fmt.Sprintf("Tráfego: %.1f req/s  •  erros %.2f%%  •  P95 %.0fms", s.RequestRate, s.ErrorRate, s.P95Latency))
+		}
+	}
+
+	if c := a.Context; c != nil {
+		lines = append(lines, "", m.styles.Header.Render("Contexto"))
+
+		if c.Trend != "" || c.PredictedState != "" {
+			lines = append(lines, fmt.Sprintf("Tendência: %s  •  Previsão: %s", orDash(c.Trend), orDash(c.PredictedState)))
+		}
+		if r := c.ReplicaChange; r != nil {
+			lines = append(lines, fmt.Sprintf("Réplicas: %d → %d em %s", r.From, r.To, r.Window))
+		}
+		for _, change := range c.Changes {
+			line := fmt.Sprintf("Mudança: %s %s → %s", change.Field, change.Before, change.After)
+			if c.ChangedBy != "" {
+				line += " (por " + c.ChangedBy + ")"
+			}
+			lines = append(lines, line)
+		}
+		if c.KubectlCommand != "" {
+			lines = append(lines, "kubectl: "+c.KubectlCommand)
+		}
+		for _, url := range []string{c.GrafanaURL, c.PrometheusURL, a.GeneratorURL} {
+			if url != "" {
+				lines = append(lines, m.styles.Muted.Render(url))
+			}
+		}
+	}
+
+	if len(a.Correlation) > 0 {
+		title := "Correlacionados"
+		if a.IncidentID != "" {
+			title += " (incidente " + a.IncidentID + ")"
+		}
+		lines = append(lines, "", m.styles.Header.Render(title))
+
+		for _, id := range a.Correlation {
+			related := m.findAlert(id)
+			if related == nil {
+				lines = append(lines, m.styles.Muted.Render("  "+id+" (não está mais ativo)"))
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("  %s %s %s  %s",
+				m.severityStyle(related.Severity).Render("● "+severityLabel(related.Severity)), related.Type, alertTarget(related), related.Summary))
+		}
+	}
+
+	if len(a.MergedFrom) > 0 {
+		lines = append(lines, m.styles.Muted.Render("Mesclado de: "+strings.Join(a.MergedFrom, ", ")))
+	}
+
+	for i := range lines {
+		lines[i] = truncate(lines[i], m.innerWidth())
+	}
+	return lines
+}
+
+// renderAlertDetail painel de detalhes (limitado à metade da tela)
+func (m Model) renderAlertDetail() string {
+	lines := m.alertDetailLines()
+	if max := m.detailRows(); len(lines) > max {
+		lines = append(lines[:max-1], m.styles.Muted.Render("…"))
+	}
+	return m.renderPanel("Detalhes", lines, true)
+}
+
+// renderAlertsFooter teclas da aba de alertas + status
+func (m Model) renderAlertsFooter() string {
+	footer := "[1] Dashboard  [↑↓] Navegar  [Enter] Detalhes  [a] Reconhecer  [f/c/t] Filtros  [o] Ordem  [q] Sair"
+	return m.renderStatusLine(footer)
+}
+
+// renderStatusLine rodapé com a mensagem de status (se houver) no lugar das teclas
+func (m Model) renderStatusLine(keys string) string {
+	if m.status == "" {
+		return m.styles.Muted.Render(truncate(keys, m.width))
+	}
+
+	style := m.styles.Info
+	switch m.statusStyle {
+	case statusOK:
+		style = m.styles.OK
+	case statusError:
+		style = m.styles.Critical
+	}
+	return style.Render(truncate(m.status, m.width))
+}
+
+// detailRows linhas máximas do painel de detalhes
+func (m Model) detailRows() int {
+	rows := m.height/2 - 3
+	if rows < 4 {
+		rows = 4
+	}
+	return rows
+}
+
+// alertListRows quantas linhas da lista de alertas cabem na tela
+func (m Model) alertListRows() int {
+	// título + filtros + rodapé + painel (borda 2 + título 1 + cabeçalho 1)
+	used := 3 + 4
+	if m.showDetail {
+		detail := len(m.alertDetailLines())
+		if detail > m.detailRows() {
+			detail = m.detailRows()
+		}
+		used += detail + 3
+	}
+
+	rows := m.height - used
+	if rows < minHPARows {
+		rows = minHPARows
+	}
+	return rows
+}
+
+// alertTarget "namespace/hpa" do alerta (o que existir)
+func alertTarget(a *models.UnifiedAlert) string {
+	return strings.Trim(a.Namespace+"/"+a.HPAName, "/")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,239 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// alertsSource fakeSource com alertas de fontes, clusters e severidades diferentes
+func alertsSource() *fakeSource {
+	source := newFakeSource()
+	base := source.lastScan
+
+	source.alerts = append(source.alerts,
+		models.UnifiedAlert{ID: "prod/api/gateway/CPUSpike", Source: models.AlertSourceWatchdog, Severity: models.SeverityWarning,
+			Type: models.AnomalyCPUSpike, Cluster: "prod", Namespace: "api", HPAName: "gateway", Timestamp: base.Add(-10 * time.Minute),
+			Summary: "CPU subiu 40%", Correlation: []string{"prod/api/auth/MaxedOut"}, IncidentID: "prod/api/auth/MaxedOut",
+			Snapshot: &models.HPASnapshot{CurrentReplicas: 4, DesiredReplicas: 6, MinReplicas: 2, MaxReplicas: 10, CPUCurrent: 95, CPUTarget: 70, CPURequest: "500m"},
+			Context:  &models.AlertContext{Trend: "increasing", KubectlCommand: "kubectl -n api describe hpa gateway"}},
+		models.UnifiedAlert{ID: "staging/alertmanager/abc", Source: models.AlertSourceAlertmanager, Severity: models.SeverityCritical,
+			Type: models.AnomalyExternal, Cluster: "staging", Timestamp: base.Add(-time.Hour), Summary: "KubePodCrashLooping"},
+	)
+	source.alerts[0].Source = models.AlertSourceWatchdog
+	return source
+}
+
+func alertIDs(m Model) []string {
+	var ids []string
+	for _, a := range m.filtered {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestAlertsFiltersAndOrder(t *testing.T) {
+	m := New(&models.WatchdogConfig{}, alertsSource(), Options{})
+	m = update(t, m, keyMsg("2"))
+
+	// Severidade: críticos primeiro, mais antigo antes
+	want := "staging/alertmanager/abc,prod/api/auth/MaxedOut,prod/api/gateway/CPUSpike"
+	if got := strings.Join(alertIDs(m), ","); m.view != viewAlerts || got != want {
+		t.Errorf("Expected severity order %s, got %s", want, got)
+	}
+
+	// o: mais recentes primeiro
+	m = update(t, m, keyMsg("o"))
+	want = "prod/api/auth/MaxedOut,prod/api/gateway/CPUSpike,staging/alertmanager/abc"
+	if got := strings.Join(alertIDs(m), ","); m.alertOrder != orderByAge || got != want {
+		t.Errorf("Expected age order %s, got %s", want, got)
+	}
+
+	// f, f: só Alertmanager
+	m = update(t, m, keyMsg("f"))
+	m = update(t, m, keyMsg("f"))
+	if got := strings.Join(alertIDs(m), ","); m.filters.source != "Alertmanager" || got != "staging/alertmanager/abc" {
+		t.Errorf("Expected Alertmanager filter, got %q (%s)", m.filters.source, got)
+	}
+
+	// 0 limpa; c: primeiro cluster (prod); t: primeiro tipo de prod
+	m = update(t, m, keyMsg("0"))
+	m = update(t, m, keyMsg("c"))
+	if m.filters.cluster != "prod" || len(m.filtered) != 2 {
+		t.Errorf("Expected cluster filter prod with 2 alerts, got %q with %d", m.filters.cluster, len(m.filtered))
+	}
+	m = update(t, m, keyMsg("t"))
+	if m.filters.anomaly == "" || len(m.filtered) != 1 {
+		t.Errorf("Expected type filter with 1 alert, got %q with %d", m.filters.anomaly, len(m.filtered))
+	}
+
+	view := m.View()
+	if !strings.Contains(view, "Alertas (1 de 3)") || !strings.Contains(view, "[0] limpar") {
+		t.Errorf("Expected filtered list in view, got:\n%s", view)
+	}
+}
+
+func TestAlertsAcknowledge(t *testing.T) {
+	source := alertsSource()
+
+	var calls []string
+	ack := func(ctx context.Context, id, by, comment string, duration time.Duration, now time.Time) error {
+		calls = append(calls, id+"@"+by)
+		for i := range source.alerts {
+			if source.alerts[i].ID == id {
+				source.alerts[i].Acknowledged = true
+				source.alerts[i].AckedBy = by
+				source.alerts[i].AckedAt = &now
+			}
+		}
+		return nil
+	}
+
+	m := New(&models.WatchdogConfig{AckUser: "oncall"}, source, Options{Acknowledge: ack})
+	m = update(t, m, keyMsg("2"))
+
+	next, cmd := m.Update(keyMsg("a"))
+	m = next.(Model)
+	if cmd == nil || !m.acking["staging/alertmanager/abc"] {
+		t.Fatal("Expected acknowledge command for selected alert")
+	}
+
+	m = update(t, m, cmd())
+	if len(calls) != 1 || calls[0] != "staging/alertmanager/abc@oncall" {
+		t.Errorf("Expected ack as oncall, got %v", calls)
+	}
+	if len(m.acking) != 0 || m.statusStyle != statusOK || !m.filtered[0].Acknowledged {
+		t.Errorf("Expected acknowledged alert and OK status, got %q", m.status)
+	}
+
+	// Segundo ack não chama a função
+	if _, cmd := m.Update(keyMsg("a")); cmd != nil {
+		t.Error("Expected no command for already acknowledged alert")
+	}
+
+	// Erro aparece no rodapé
+	failing := New(&models.WatchdogConfig{}, alertsSource(), Options{Acknowledge: func(context.Context, string, string, string, time.Duration, time.Time) error {
+		return errors.New("alertmanager offline")
+	}})
+	failing = update(t, failing, keyMsg("2"))
+	_, cmd = failing.Update(keyMsg("a"))
+	failing = update(t, failing, cmd())
+	if failing.statusStyle != statusError || !strings.Contains(failing.View(), "alertmanager offline") {
+		t.Errorf("Expected error status, got %q", failing.status)
+	}
+
+	// Sem AckFunc: indisponível
+	disabled := New(&models.WatchdogConfig{}, alertsSource(), Options{})
+	disabled = update(t, disabled, keyMsg("2"))
+	if next, cmd := disabled.Update(keyMsg("a")); cmd != nil || next.(Model).statusStyle != statusError {
+		t.Error("Expected acknowledge unavailable without AckFunc")
+	}
+}
+
+func TestAlertsSilencePrompt(t *testing.T) {
+	source := alertsSource()
+
+	var calls []string
+	ack := func(ctx context.Context, id, by, comment string, duration time.Duration, now time.Time) error {
+		calls = append(calls, fmt.Sprintf("%s@%s %s %q", id, by, duration, comment))
+		return nil
+	}
+
+	m := New(&models.WatchdogConfig{AckUser: "oncall"}, source, Options{Acknowledge: ack, SilenceDuration: 2 * time.Hour})
+	m = update(t, m, keyMsg("2"))
+
+	// Alerta do Alertmanager: abre o prompt com a duração padrão, sem reconhecer ainda
+	next, cmd := m.Update(keyMsg("a"))
+	m = next.(Model)
+	if cmd != nil || m.silencePrompt == nil || m.silencePrompt.duration != "2h" {
+		t.Fatalf("Expected silence prompt with default duration, got %+v", m.silencePrompt)
+	}
+	if view := m.View(); !strings.Contains(view, "Reconhecer e silenciar") || !strings.Contains(view, "KubePodCrashLooping") {
+		t.Errorf("Expected prompt in view, got:\n%s", view)
+	}
+
+	// Comentário vazio é recusado; "q" é texto, não sai da TUI
+	m = update(t, m, keyMsg("enter"))
+	if m.silencePrompt == nil || !strings.Contains(m.silencePrompt.err, "comentário obrigatório") {
+		t.Fatalf("Expected empty comment rejected, got %+v", m.silencePrompt)
+	}
+	for _, r := range "q deploy" {
+		m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	// Duração inválida também
+	m = update(t, m, keyMsg("tab"))
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("abc")})
+	m = update(t, m, keyMsg("enter"))
+	if m.silencePrompt == nil || !strings.Contains(m.silencePrompt.err, "duração inválida") {
+		t.Fatalf("Expected invalid duration rejected, got %+v", m.silencePrompt)
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("30m")})
+	next, cmd = m.Update(keyMsg("enter"))
+	m = next.(Model)
+	if cmd == nil || m.silencePrompt != nil || !m.acking["staging/alertmanager/abc"] {
+		t.Fatal("Expected acknowledge command after confirming the prompt")
+	}
+	m = update(t, m, cmd())
+	if len(calls) != 1 || calls[0] != `staging/alertmanager/abc@oncall 30m0s "q deploy"` {
+		t.Errorf("Expected ack with chosen duration and comment, got %v", calls)
+	}
+
+	// Alerta do watchdog: reconhece direto, sem prompt
+	m = update(t, m, keyMsg("down"))
+	_, cmd = m.Update(keyMsg("a"))
+	if cmd == nil {
+		t.Fatal("Expected direct acknowledge for watchdog alert")
+	}
+	cmd()
+	if len(calls) != 2 || !strings.HasSuffix(calls[1], `0s ""`) {
+		t.Errorf("Expected watchdog ack without silence, got %v", calls)
+	}
+
+	// Esc cancela
+	m = New(&models.WatchdogConfig{}, alertsSource(), Options{Acknowledge: ack, SilenceDuration: time.Hour})
+	m = update(t, m, keyMsg("2"))
+	m = update(t, m, keyMsg("a"))
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.silencePrompt != nil || len(calls) != 2 {
+		t.Error("Expected Esc to cancel without acknowledging")
+	}
+}
+
+func TestAlertsDetail(t *testing.T) {
+	m := New(&models.WatchdogConfig{AckUser: "oncall"}, alertsSource(), Options{})
+	m = update(t, m, keyMsg("tab"))
+	m = update(t, m, keyMsg("down"))
+
+	// Enter no painel de alertas do dashboard abre o detalhe do alerta selecionado
+	m = update(t, m, keyMsg("enter"))
+	if m.view != viewAlerts || !m.showDetail || m.selectedAlertID() != "prod/api/gateway/CPUSpike" {
+		t.Fatalf("Expected detail of CPUSpike, got view %d detail %v id %s", m.view, m.showDetail, m.selectedAlertID())
+	}
+
+	detail := strings.Join(m.alertDetailLines(), "\n")
+	for _, want := range []string{"Réplicas 4→6 (min 2, max 10)", "95%/70%", "500m", "Tendência: increasing",
+		"kubectl -n api describe hpa gateway", "incidente prod/api/auth/MaxedOut", "HPA no limite", "reconhecer como oncall"} {
+		if !strings.Contains(detail, want) {
+			t.Errorf("Expected detail to contain %q, got:\n%s", want, detail)
+		}
+	}
+}
+
+func TestResolveAckUser(t *testing.T) {
+	if got := ResolveAckUser("oncall"); got != "oncall" {
+		t.Errorf("Expected configured ack user, got %s", got)
+	}
+	if got := ResolveAckUser(""); got == "" {
+		t.Error("Expected fallback ack user")
+	}
+}
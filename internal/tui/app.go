@@ -0,0 +1,288 @@
+package tui
+
+import (
+	"context"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultRefreshInterval usado quando ui.refresh_interval_ms não está definido
+	DefaultRefreshInterval = 500 * time.Millisecond
+
+	// ackTimeout timeout de um reconhecimento (pode criar silence no Alertmanager)
+	ackTimeout = 15 * time.Second
+
+	// statusDuration por quanto tempo a mensagem de status fica no rodapé
+	statusDuration = 5 * time.Second
+)
+
+// Source estado ao vivo exibido pela TUI (implementado por *monitor.Watcher)
+type Source interface {
+	LatestSnapshots() []models.HPASnapshot
+	Alerts() []models.UnifiedAlert
+	Clusters() []models.ClusterInfo
+	LastScan() (time.Time, int)
+}
+
+// AckFunc reconhece um alerta (AlertStore, ou Silencer quando silences.on_ack está ativo).
+// comment e duration são os do silence (vazios para alertas do watchdog).
+type AckFunc func(ctx context.Context, id, by, comment string, duration time.Duration, now time.Time) error
+
+// Options integrações opcionais da TUI
+type Options struct {
+	Acknowledge AckFunc // nil = reconhecimento indisponível
+
+	// SilenceDuration duração sugerida no prompt de silence. > 0 = reconhecer um alerta do
+	// Alertmanager pede duração e comentário (silences.on_ack); 0 = reconhece direto
+	SilenceDuration time.Duration
+
+	// Thresholds manager usado pelos detectores; editado ao vivo pelo modal (nil = somente leitura)
+	Thresholds *config.ThresholdManager
+
+	// SaveThresholds grava os thresholds no watchdog.yaml (nil = gravação indisponível)
+	SaveThresholds func(models.Thresholds) error
+}
+
+// view tela exibida
+type view int
+
+const (
+	viewDashboard view = iota
+	viewAlerts
+)
+
+// panel painel com foco no dashboard (recebe as teclas de navegação)
+type panel int
+
+const (
+	panelHPAs panel = iota
+	panelAlerts
+)
+
+// tickMsg dispara o refresh periódico
+type tickMsg time.Time
+
+// ackDoneMsg resultado de um reconhecimento
+type ackDoneMsg struct {
+	id  string
+	by  string
+	err error
+}
+
+// Model estado da TUI (Bubble Tea)
+type Model struct {
+	cfg     *models.WatchdogConfig
+	source  Source
+	opts    Options
+	styles  Styles
+	ackUser string
+
+	width  int
+	height int
+
+	// Último estado lido da source
+	clusters    []models.ClusterInfo
+	snapshots   []models.HPASnapshot // Ordenados por sortBy/sortDesc
+	alerts      []models.UnifiedAlert
+	lastScan    time.Time
+	scans       int
+	refreshedAt time.Time
+
+	// Dashboard
+	view        view
+	focus       panel
+	sortBy      sortColumn
+	sortDesc    bool
+	hpaCursor   int
+	alertCursor int
+	showChart   bool // Gráfico do HPA selecionado
+
+	// Aba de alertas
+	filters     alertFilters
+	alertOrder  alertOrder
+	filtered    []models.UnifiedAlert // alerts com filtros e ordem aplicados
+	listCursor  int
+	showDetail  bool
+	acking      map[string]bool // IDs com reconhecimento em andamento
+	status      string
+	statusStyle statusKind
+	statusAt    time.Time
+
+	// Modal de thresholds
+	editor     *thresholdEditor
+	showEditor bool
+
+	// Prompt de duração e comentário do silence (nil = fechado)
+	silencePrompt *silencePrompt
+}
+
+// New cria o model lendo o estado inicial da source
+func New(cfg *models.WatchdogConfig, source Source, opts Options) Model {
+	if cfg == nil {
+		cfg = &models.WatchdogConfig{}
+	}
+
+	styles, err := LoadStyles(cfg.Theme)
+
+	m := Model{
+		cfg:     cfg,
+		source:  source,
+		opts:    opts,
+		styles:  styles,
+		ackUser: ResolveAckUser(cfg.AckUser),
+		width:   120,
+		height:  40,
+		acking:  make(map[string]bool),
+	}
+	m.refresh(time.Now())
+
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load theme, using default")
+		m.setStatus(statusError, "Tema inválido, usando "+DefaultTheme+": "+err.Error())
+	}
+	return m
+}
+
+// Run executa a TUI em tela cheia até o usuário sair
+func Run(cfg *models.WatchdogConfig, source Source, opts Options) error {
+	_, err := tea.NewProgram(New(cfg, source, opts), tea.WithAltScreen()).Run()
+	return err
+}
+
+// Init agenda o primeiro refresh
+func (m Model) Init() tea.Cmd {
+	return m.tick()
+}
+
+// Update trata teclas, resize, refresh periódico e resultados de reconhecimento
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.clampCursors()
+		return m, nil
+
+	case tickMsg:
+		m.refresh(time.Time(msg))
+		return m, m.tick()
+
+	case ackDoneMsg:
+		delete(m.acking, msg.id)
+		if msg.err != nil {
+			m.setStatus(statusError, "Falha ao reconhecer "+msg.id+": "+msg.err.Error())
+		} else {
+			m.setStatus(statusOK, "Alerta "+msg.id+" reconhecido por "+msg.by)
+		}
+		m.refresh(time.Now())
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// tick agenda o próximo refresh no intervalo configurado
+func (m Model) tick() tea.Cmd {
+	return tea.Tick(m.refreshInterval(), func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
+
+// refreshInterval retorna o intervalo de refresh (ui.refresh_interval_ms)
+func (m Model) refreshInterval() time.Duration {
+	if m.cfg.RefreshIntervalMs < 1 {
+		return DefaultRefreshInterval
+	}
+	return time.Duration(m.cfg.RefreshIntervalMs) * time.Millisecond
+}
+
+// refresh lê o estado atual da source mantendo o HPA e o alerta selecionados
+func (m *Model) refresh(now time.Time) {
+	selected := m.selectedKey()
+	selectedAlert := m.selectedAlertID()
+
+	m.clusters = m.source.Clusters()
+	m.snapshots = m.source.LatestSnapshots()
+	m.alerts = m.source.Alerts()
+	m.lastScan, m.scans = m.source.LastScan()
+	m.refreshedAt = now
+
+	m.sortSnapshots()
+	m.selectKey(selected)
+	m.applyAlertFilters()
+	m.selectAlert(selectedAlert)
+	m.clampCursors()
+
+	if m.status != "" && now.Sub(m.statusAt) > statusDuration {
+		m.status = ""
+	}
+}
+
+// setStatus mostra uma mensagem no rodapé por statusDuration
+func (m *Model) setStatus(kind statusKind, text string) {
+	m.status = text
+	m.statusStyle = kind
+	m.statusAt = time.Now()
+}
+
+// selectedKey chave do HPA selecionado ("" se a tabela estiver vazia)
+func (m *Model) selectedKey() string {
+	if m.hpaCursor < 0 || m.hpaCursor >= len(m.snapshots) {
+		return ""
+	}
+	return m.snapshots[m.hpaCursor].Key()
+}
+
+// selectKey move o cursor para o HPA com a chave indicada (se ainda existir)
+func (m *Model) selectKey(key string) {
+	if key == "" {
+		return
+	}
+	for i := range m.snapshots {
+		if m.snapshots[i].Key() == key {
+			m.hpaCursor = i
+			return
+		}
+	}
+}
+
+// clampCursors mantém os cursores dentro das listas
+func (m *Model) clampCursors() {
+	m.hpaCursor = clamp(m.hpaCursor, 0, len(m.snapshots)-1)
+	m.alertCursor = clamp(m.alertCursor, 0, len(m.alerts)-1)
+	m.listCursor = clamp(m.listCursor, 0, len(m.filtered)-1)
+}
+
+// ResolveAckUser nome registrado em AckedBy e nos silences: alerts.ack_user, senão o usuário do SO
+func ResolveAckUser(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	if current, err := user.Current(); err == nil && current.Username != "" {
+		return current.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "hpa-watchdog"
+}
+
+func clamp(v, low, high int) int {
+	if v > high {
+		v = high
+	}
+	if v < low {
+		v = low
+	}
+	return v
+}
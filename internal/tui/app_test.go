@@ -0,0 +1,210 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// fakeSource Source com estado fixo
+type fakeSource struct {
+	snapshots []models.HPASnapshot
+	alerts    []models.UnifiedAlert
+	clusters  []models.ClusterInfo
+	lastScan  time.Time
+	scans     int
+}
+
+func (f *fakeSource) LatestSnapshots() []models.HPASnapshot {
+	return append([]models.HPASnapshot(nil), f.snapshots...)
+}
+func (f *fakeSource) Alerts() []models.UnifiedAlert  { return f.alerts }
+func (f *fakeSource) Clusters() []models.ClusterInfo { return f.clusters }
+func (f *fakeSource) LastScan() (time.Time, int)     { return f.lastScan, f.scans }
+
+func newFakeSource() *fakeSource {
+	now := time.Date(2025, 1, 10, 22, 0, 0, 0, time.UTC)
+
+	return &fakeSource{
+		snapshots: []models.HPASnapshot{
+			{Cluster: "prod", Namespace: "api", Name: "gateway", CurrentReplicas: 4, MaxReplicas: 10, CPUCurrent: 95, CPUTarget: 70},
+			{Cluster: "prod", Namespace: "api", Name: "auth", CurrentReplicas: 10, MaxReplicas: 10, CPUCurrent: 50, CPUTarget: 70},
+			{Cluster: "staging", Namespace: "web", Name: "front", CurrentReplicas: 2, MaxReplicas: 5, CPUCurrent: 20, CPUTarget: 70},
+		},
+		alerts: []models.UnifiedAlert{
+			{ID: "prod/api/auth/MaxedOut", Severity: models.SeverityCritical, Type: models.AnomalyMaxedOut,
+				Cluster: "prod", Namespace: "api", HPAName: "auth", Timestamp: now, Summary: "HPA no limite"},
+		},
+		clusters: []models.ClusterInfo{
+			{Name: "prod", Status: models.ClusterStatusOnline, HPACount: 2, AlertCount: 1, LastScan: now},
+			{Name: "staging", Status: models.ClusterStatusOffline},
+		},
+		lastScan: now,
+		scans:    3,
+	}
+}
+
+func keyMsg(key string) tea.KeyMsg {
+	switch key {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)}
+	}
+}
+
+func update(t *testing.T, m Model, msg tea.Msg) Model {
+	t.Helper()
+	next, _ := m.Update(msg)
+	return next.(Model)
+}
+
+func names(m Model) []string {
+	var result []string
+	for _, s := range m.snapshots {
+		result = append(result, s.Name)
+	}
+	return result
+}
+
+func TestModelSorting(t *testing.T) {
+	m := New(&models.WatchdogConfig{}, newFakeSource(), Options{})
+
+	if got := strings.Join(names(m), ","); got != "auth,gateway,front" {
+		t.Errorf("Expected default sort by key, got %s", got)
+	}
+
+	// s: réplicas (decrescente)
+	m = update(t, m, keyMsg("s"))
+	if got := strings.Join(names(m), ","); m.sortBy != sortByReplicas || got != "auth,gateway,front" {
+		t.Errorf("Expected sort by replicas desc, got %s by %s", got, m.sortBy)
+	}
+
+	// s, s: CPU relativa ao target (decrescente)
+	m = update(t, m, keyMsg("s"))
+	m = update(t, m, keyMsg("s"))
+	if got := strings.Join(names(m), ","); m.sortBy != sortByCPU || got != "gateway,auth,front" {
+		t.Errorf("Expected sort by CPU desc, got %s by %s", got, m.sortBy)
+	}
+
+	// r: inverte
+	m = update(t, m, keyMsg("r"))
+	if got := strings.Join(names(m), ","); got != "front,auth,gateway" {
+		t.Errorf("Expected reversed CPU sort, got %s", got)
+	}
+
+	// S: volta para a coluna anterior
+	m = update(t, m, keyMsg("S"))
+	if m.sortBy != sortByLimit {
+		t.Errorf("Expected previous column Min/Max, got %s", m.sortBy)
+	}
+}
+
+func TestModelRefreshKeepsSelection(t *testing.T) {
+	source := newFakeSource()
+	m := New(&models.WatchdogConfig{RefreshIntervalMs: 250}, source, Options{})
+
+	if m.refreshInterval() != 250*time.Millisecond {
+		t.Errorf("Expected refresh interval from config, got %s", m.refreshInterval())
+	}
+
+	m = update(t, m, keyMsg("down"))
+	if m.selectedKey() != "prod/api/gateway" {
+		t.Fatalf("Expected gateway selected, got %s", m.selectedKey())
+	}
+
+	// Novo HPA que ordena antes do selecionado
+	source.snapshots = append(source.snapshots, models.HPASnapshot{Cluster: "prod", Namespace: "api", Name: "billing"})
+	source.scans = 4
+
+	m = update(t, m, tickMsg(time.Now()))
+	if len(m.snapshots) != 4 || m.scans != 4 {
+		t.Fatalf("Expected refreshed state, got %d snapshots, scan %d", len(m.snapshots), m.scans)
+	}
+	if m.selectedKey() != "prod/api/gateway" {
+		t.Errorf("Expected selection kept across refresh, got %s", m.selectedKey())
+	}
+
+	// Tab: navegação passa para os alertas
+	m = update(t, m, keyMsg("tab"))
+	m = update(t, m, keyMsg("down"))
+	if m.focus != panelAlerts || m.alertCursor != 0 || m.selectedKey() != "prod/api/gateway" {
+		t.Errorf("Expected alert cursor clamped and HPA selection untouched, got focus %d cursor %d", m.focus, m.alertCursor)
+	}
+
+	if _, cmd := m.Update(keyMsg("q")); cmd == nil {
+		t.Error("Expected quit command")
+	}
+}
+
+func TestModelView(t *testing.T) {
+	m := New(&models.WatchdogConfig{}, newFakeSource(), Options{})
+	m = update(t, m, tea.WindowSizeMsg{Width: 140, Height: 30})
+
+	view := m.View()
+	for _, want := range []string{"Clusters: 2", "HPAs: 3", "Alertas: 1 (1 críticos)", "scan #3", "staging", "Offline", "gateway", "95%/70%", "HPA no limite", "CRITICAL"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected view to contain %q", want)
+		}
+	}
+
+	empty := New(nil, &fakeSource{}, Options{})
+	if view := empty.View(); !strings.Contains(view, "aguardando primeiro scan") || !strings.Contains(view, "Nenhum alerta ativo") {
+		t.Errorf("Expected empty state messages, got:\n%s", view)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate("produção-cluster", 8); got != "produçã…" {
+		t.Errorf("truncate = %q", got)
+	}
+	if got := pad("api", 5); got != "api  " {
+		t.Errorf("pad = %q", got)
+	}
+}
+
+func TestModelChart(t *testing.T) {
+	source := newFakeSource()
+	source.snapshots[0].MinReplicas = 2
+	source.snapshots[0].CPUHistory = []float64{40, 60, 80, 95}
+	source.snapshots[0].ReplicaHistory = []int32{2, 3, 4, 4}
+	source.snapshots[0].MemoryHistory = []float64{30, 31, 32, 33}
+
+	m := New(&models.WatchdogConfig{Thresholds: models.Thresholds{TargetDeviationPercent: 30}}, source, Options{})
+	m = update(t, m, tea.WindowSizeMsg{Width: 140, Height: 50})
+	m = update(t, m, keyMsg("down"))
+
+	if view := m.View(); !strings.Contains(view, "CPU 5M") || !strings.Contains(view, "▁▁▁") {
+		t.Errorf("Expected CPU sparkline column, got:\n%s", view)
+	}
+
+	rows := m.hpaRows()
+	m = update(t, m, keyMsg("enter"))
+	view := m.View()
+	for _, want := range []string{"Histórico prod/api/gateway", "target 70%", "±30%", "min/max 2/10", "Memória ▁▃▆█"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("Expected chart to contain %q, got:\n%s", want, view)
+		}
+	}
+	if m.hpaRows() >= rows {
+		t.Errorf("Expected HPA table to shrink with chart open, got %d rows (was %d)", m.hpaRows(), rows)
+	}
+
+	// Sem histórico: mensagem no lugar do gráfico
+	m = update(t, m, keyMsg("down"))
+	if view := m.View(); !strings.Contains(view, "Sem histórico") {
+		t.Errorf("Expected no history message, got:\n%s", view)
+	}
+
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showChart {
+		t.Error("Expected Esc to close chart")
+	}
+}
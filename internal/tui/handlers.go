@@ -0,0 +1,213 @@
+package tui
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// sortColumn coluna de ordenação da tabela de HPAs
+type sortColumn int
+
+const (
+	sortByName     sortColumn = iota // cluster/namespace/nome
+	sortByReplicas                   // Réplicas atuais
+	sortByLimit                      // Réplicas atuais / máximo
+	sortByCPU                        // CPU atual / target
+	sortByMemory                     // Memória atual / target
+	sortBySource                     // Fonte das métricas
+	sortColumnCount
+)
+
+func (c sortColumn) String() string {
+	switch c {
+	case sortByName:
+		return "Nome"
+	case sortByReplicas:
+		return "Réplicas"
+	case sortByLimit:
+		return "Min/Max"
+	case sortByCPU:
+		return "CPU"
+	case sortByMemory:
+		return "Memória"
+	case sortBySource:
+		return "Fonte"
+	default:
+		return "?"
+	}
+}
+
+// handleKey trata as teclas globais e delega as demais para a tela atual
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.silencePrompt != nil {
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m.handleSilencePromptKey(msg)
+	}
+	if m.showEditor {
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		return m.handleEditorKey(msg)
+	}
+
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "1":
+		m.view = viewDashboard
+		return m, nil
+	case "2":
+		m.view = viewAlerts
+		return m, nil
+	case "f5", "ctrl+r":
+		m.refresh(time.Now())
+		return m, nil
+	case "e":
+		m.openEditor()
+		return m, nil
+	}
+
+	if m.view == viewAlerts {
+		return m.handleAlertsKey(msg.String())
+	}
+	return m.handleDashboardKey(msg.String())
+}
+
+// handleDashboardKey teclas do dashboard (navegação do painel com foco)
+func (m Model) handleDashboardKey(key string) (tea.Model, tea.Cmd) {
+	switch key {
+	case "tab":
+		if m.focus == panelHPAs {
+			m.focus = panelAlerts
+		} else {
+			m.focus = panelHPAs
+		}
+
+	case "s":
+		m.setSort((m.sortBy + 1) % sortColumnCount)
+
+	case "S":
+		m.setSort((m.sortBy + sortColumnCount - 1) % sortColumnCount)
+
+	case "r":
+		m.sortDesc = !m.sortDesc
+		m.resort()
+
+	case "enter":
+		// HPAs: mostra/oculta o gráfico; alertas: abre o selecionado na aba de alertas
+		if m.focus == panelHPAs {
+			m.showChart = !m.showChart
+		} else if m.alertCursor < len(m.alerts) {
+			m.view = viewAlerts
+			m.showDetail = true
+			m.filters = alertFilters{}
+			m.applyAlertFilters()
+			m.selectAlert(m.alerts[m.alertCursor].ID)
+		}
+
+	case "esc":
+		m.showChart = false
+
+	case "a":
+		if m.focus == panelAlerts && m.alertCursor < len(m.alerts) {
+			cmd := m.acknowledge(&m.alerts[m.alertCursor])
+			return m, cmd
+		}
+
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "pgup":
+		m.moveCursor(-m.pageSize())
+	case "pgdown":
+		m.moveCursor(m.pageSize())
+	case "home", "g":
+		m.moveCursor(-len(m.snapshots) - len(m.alerts))
+	case "end", "G":
+		m.moveCursor(len(m.snapshots) + len(m.alerts))
+	}
+
+	return m, nil
+}
+
+// moveCursor move o cursor do painel com foco
+func (m *Model) moveCursor(delta int) {
+	if m.focus == panelAlerts {
+		m.alertCursor += delta
+	} else {
+		m.hpaCursor += delta
+	}
+	m.clampCursors()
+}
+
+// setSort troca a coluna de ordenação (numéricas começam em ordem decrescente)
+func (m *Model) setSort(column sortColumn) {
+	m.sortBy = column
+	m.sortDesc = column != sortByName && column != sortBySource
+	m.resort()
+}
+
+// resort reordena a tabela mantendo o HPA selecionado
+func (m *Model) resort() {
+	selected := m.selectedKey()
+	m.sortSnapshots()
+	m.selectKey(selected)
+}
+
+// sortSnapshots ordena os snapshots pela coluna atual (desempate por chave)
+func (m *Model) sortSnapshots() {
+	sort.SliceStable(m.snapshots, func(i, j int) bool {
+		a, b := &m.snapshots[i], &m.snapshots[j]
+
+		if cmp := compareSnapshots(a, b, m.sortBy); cmp != 0 {
+			if m.sortDesc {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return a.Key() < b.Key()
+	})
+}
+
+// compareSnapshots compara dois snapshots pela coluna (-1, 0, 1)
+func compareSnapshots(a, b *models.HPASnapshot, column sortColumn) int {
+	switch column {
+	case sortByReplicas:
+		return compareFloat(float64(a.CurrentReplicas), float64(b.CurrentReplicas))
+	case sortByLimit:
+		return compareFloat(ratio(float64(a.CurrentReplicas), float64(a.MaxReplicas)), ratio(float64(b.CurrentReplicas), float64(b.MaxReplicas)))
+	case sortByCPU:
+		return compareFloat(ratio(a.CPUCurrent, float64(a.CPUTarget)), ratio(b.CPUCurrent, float64(b.CPUTarget)))
+	case sortByMemory:
+		return compareFloat(ratio(a.MemoryCurrent, float64(a.MemoryTarget)), ratio(b.MemoryCurrent, float64(b.MemoryTarget)))
+	case sortBySource:
+		return compareFloat(float64(a.DataSource), float64(b.DataSource))
+	default:
+		return 0
+	}
+}
+
+// ratio current/target; sem target ordena pelo valor absoluto em escala percentual
+func ratio(current, target float64) float64 {
+	if target <= 0 {
+		return current / 100
+	}
+	return current / target
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,136 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Campos do prompt de silence
+const (
+	promptDuration = iota
+	promptComment
+	promptFieldCount
+)
+
+// silencePrompt duração e comentário do silence criado ao reconhecer um alerta do Alertmanager
+type silencePrompt struct {
+	alert    models.UnifiedAlert
+	duration string
+	comment  string
+	field    int
+	err      string
+}
+
+func newSilencePrompt(alert *models.UnifiedAlert, duration time.Duration) *silencePrompt {
+	return &silencePrompt{
+		alert:    *alert,
+		duration: formatSilenceDuration(duration),
+		field:    promptComment,
+	}
+}
+
+// input campo com foco
+func (p *silencePrompt) input() *string {
+	if p.field == promptDuration {
+		return &p.duration
+	}
+	return &p.comment
+}
+
+// parse valida duração (> 0) e comentário (obrigatório no Alertmanager)
+func (p *silencePrompt) parse() (time.Duration, string, error) {
+	duration, err := time.ParseDuration(strings.TrimSpace(p.duration))
+	if err != nil || duration <= 0 {
+		return 0, "", fmt.Errorf("duração inválida %q (ex: 30m, 2h, 1h30m)", p.duration)
+	}
+
+	comment := strings.TrimSpace(p.comment)
+	if comment == "" {
+		return 0, "", fmt.Errorf("comentário obrigatório")
+	}
+	return duration, comment, nil
+}
+
+// handleSilencePromptKey teclas do prompt: edição dos campos, Enter confirma, Esc cancela
+func (m Model) handleSilencePromptKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p := m.silencePrompt
+
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.silencePrompt = nil
+		m.setStatus(statusInfo, "Reconhecimento cancelado")
+	case tea.KeyEnter:
+		duration, comment, err := p.parse()
+		if err != nil {
+			p.err = err.Error()
+			return m, nil
+		}
+		m.silencePrompt = nil
+		return m, m.startAck(p.alert.ID, comment, duration)
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+		p.field = (p.field + 1) % promptFieldCount
+	case tea.KeyBackspace:
+		input := []rune(*p.input())
+		if len(input) > 0 {
+			*p.input() = string(input[:len(input)-1])
+		}
+	case tea.KeyCtrlU:
+		*p.input() = ""
+	case tea.KeyRunes, tea.KeySpace:
+		*p.input() += string(msg.Runes)
+	}
+
+	return m, nil
+}
+
+// renderSilencePrompt modal centralizado com duração e comentário do silence
+func (m Model) renderSilencePrompt() string {
+	p := m.silencePrompt
+
+	field := func(index int, label, value string) string {
+		if index == p.field {
+			return m.styles.Selected.Render(fmt.Sprintf("%s%-11s %s█", cursorMark, label, value))
+		}
+		return fmt.Sprintf("  %-11s %s", label, value)
+	}
+
+	name := p.alert.AlertName
+	if name == "" {
+		name = p.alert.Summary
+	}
+
+	lines := []string{
+		m.styles.Header.Render("Reconhecer e silenciar"),
+		"",
+		fmt.Sprintf("%s [%s] como %s", name, p.alert.Cluster, m.ackUser),
+		"",
+		field(promptDuration, "Duração", p.duration),
+		field(promptComment, "Comentário", p.comment),
+	}
+	if p.err != "" {
+		lines = append(lines, "", m.styles.Critical.Render("✗ "+p.err))
+	}
+	lines = append(lines, "",
+		m.styles.Muted.Render("Cria um silence no Alertmanager com os labels do alerta"),
+		m.styles.Muted.Render("[Tab] Trocar campo  [Enter] Confirmar  [Esc] Cancelar  [Ctrl+U] Limpar"))
+
+	box := m.styles.Panel.BorderForeground(m.styles.Header.GetForeground()).Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Center, box)
+}
+
+// formatSilenceDuration duração sem sufixos zerados (2h0m0s → 2h)
+func formatSilenceDuration(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
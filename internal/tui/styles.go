@@ -0,0 +1,87 @@
+package tui
+
+import "github.com/charmbracelet/lipgloss"
+
+// Styles estilos Lipgloss usados pelas views
+type Styles struct {
+	Title    lipgloss.Style // Barra superior
+	Panel    lipgloss.Style // Borda dos painéis
+	Header   lipgloss.Style // Cabeçalho de tabelas
+	Selected lipgloss.Style // Linha selecionada
+	Muted    lipgloss.Style // Texto secundário (timestamps, rodapé)
+
+	OK       lipgloss.Style // Online, dentro do target
+	Info     lipgloss.Style
+	Warning  lipgloss.Style
+	Critical lipgloss.Style
+
+	// Status dos clusters
+	Online   lipgloss.Style
+	Degraded lipgloss.Style
+	Offline  lipgloss.Style
+
+	// Fonte das métricas
+	Prometheus    lipgloss.Style
+	MetricsServer lipgloss.Style
+	Hybrid        lipgloss.Style
+}
+
+// DefaultStyles estilos do tema escuro padrão
+func DefaultStyles() Styles {
+	return NewStyles(builtinThemes[DefaultTheme])
+}
+
+// NewStyles estilos a partir de uma paleta (cores ANSI "0"-"255" ou hex "#rrggbb")
+func NewStyles(p Palette) Styles {
+	color := func(c string) lipgloss.Style {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+	}
+
+	return Styles{
+		Title:    lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(p.TitleFg)).Background(lipgloss.Color(p.TitleBg)).Padding(0, 1),
+		Panel:    lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color(p.Border)).Padding(0, 1),
+		Header:   color(p.Header).Bold(true),
+		Selected: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(p.SelectedFg)).Background(lipgloss.Color(p.SelectedBg)),
+		Muted:    color(p.Muted),
+
+		OK:       color(p.OK),
+		Info:     color(p.Info),
+		Warning:  color(p.Warning),
+		Critical: color(p.Critical).Bold(true),
+
+		Online:   color(p.Online),
+		Degraded: color(p.Degraded),
+		Offline:  color(p.Offline).Bold(true),
+
+		Prometheus:    color(p.Prometheus),
+		MetricsServer: color(p.MetricsServer),
+		Hybrid:        color(p.Hybrid),
+	}
+}
+
+// MonochromeStyles estilos sem cores (NO_COLOR): destaque só com negrito, sublinhado e vídeo reverso
+func MonochromeStyles() Styles {
+	plain := lipgloss.NewStyle()
+	bold := lipgloss.NewStyle().Bold(true)
+
+	return Styles{
+		Title:    bold.Reverse(true).Padding(0, 1),
+		Panel:    lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1),
+		Header:   bold,
+		Selected: lipgloss.NewStyle().Reverse(true),
+		Muted:    plain,
+
+		OK:       plain,
+		Info:     plain,
+		Warning:  lipgloss.NewStyle().Underline(true),
+		Critical: bold,
+
+		Online:   plain,
+		Degraded: lipgloss.NewStyle().Underline(true),
+		Offline:  bold,
+
+		Prometheus:    plain,
+		MetricsServer: plain,
+		Hybrid:        plain,
+	}
+}
@@ -0,0 +1,200 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultTheme usado quando ui.theme está vazio
+	DefaultTheme = "dark"
+
+	// MonochromeTheme tema sem cores (também ativado pela variável NO_COLOR)
+	MonochromeTheme = "mono"
+
+	// themesDir diretório dos temas do usuário (<nome>.yaml)
+	themesDir = "~/.hpa-watchdog/themes"
+)
+
+// Palette cores de um tema: ANSI ("0"-"255") ou hex ("#rrggbb", "#rgb")
+type Palette struct {
+	TitleFg    string `yaml:"title_fg"`
+	TitleBg    string `yaml:"title_bg"`
+	Border     string `yaml:"border"`
+	Header     string `yaml:"header"`
+	SelectedFg string `yaml:"selected_fg"`
+	SelectedBg string `yaml:"selected_bg"`
+	Muted      string `yaml:"muted"`
+
+	// Severidades (OK = dentro do target)
+	OK       string `yaml:"ok"`
+	Info     string `yaml:"info"`
+	Warning  string `yaml:"warning"`
+	Critical string `yaml:"critical"`
+
+	// Status dos clusters
+	Online   string `yaml:"online"`
+	Degraded string `yaml:"degraded"`
+	Offline  string `yaml:"offline"`
+
+	// Fonte das métricas
+	Prometheus    string `yaml:"prometheus"`
+	MetricsServer string `yaml:"metrics_server"`
+	Hybrid        string `yaml:"hybrid"`
+}
+
+// themeFile tema do usuário: cores não definidas vêm do tema base (inherit, padrão dark)
+type themeFile struct {
+	Inherit string `yaml:"inherit"`
+	Palette `yaml:",inline"`
+}
+
+// builtinThemes temas embutidos (ui.theme)
+var builtinThemes = map[string]Palette{
+	"dark": {
+		TitleFg: "15", TitleBg: "62", Border: "240", Header: "111", SelectedFg: "15", SelectedBg: "237", Muted: "244",
+		OK: "42", Info: "39", Warning: "214", Critical: "196",
+		Online: "42", Degraded: "214", Offline: "196",
+		Prometheus: "141", MetricsServer: "39", Hybrid: "180",
+	},
+	"light": {
+		TitleFg: "15", TitleBg: "25", Border: "250", Header: "25", SelectedFg: "0", SelectedBg: "153", Muted: "242",
+		OK: "28", Info: "26", Warning: "130", Critical: "160",
+		Online: "28", Degraded: "130", Offline: "160",
+		Prometheus: "90", MetricsServer: "26", Hybrid: "94",
+	},
+	"monokai": {
+		TitleFg: "#272822", TitleBg: "#A6E22E", Border: "#75715E", Header: "#66D9EF", SelectedFg: "#F8F8F2", SelectedBg: "#49483E", Muted: "#75715E",
+		OK: "#A6E22E", Info: "#66D9EF", Warning: "#FD971F", Critical: "#F92672",
+		Online: "#A6E22E", Degraded: "#E6DB74", Offline: "#F92672",
+		Prometheus: "#AE81FF", MetricsServer: "#66D9EF", Hybrid: "#E6DB74",
+	},
+}
+
+// hexColor "#rgb" ou "#rrggbb"
+var hexColor = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// Themes nomes dos temas embutidos
+func Themes() []string {
+	names := []string{MonochromeTheme}
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadStyles resolve ui.theme: NO_COLOR ou "mono" = sem cores; "" = dark; nome embutido;
+// caminho de um arquivo YAML; ou <nome>.yaml em ~/.hpa-watchdog/themes.
+func LoadStyles(theme string) (Styles, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return MonochromeStyles(), nil
+	}
+
+	theme = strings.TrimSpace(theme)
+	switch {
+	case theme == "":
+		return DefaultStyles(), nil
+	case strings.EqualFold(theme, MonochromeTheme):
+		return MonochromeStyles(), nil
+	}
+
+	if palette, ok := builtinThemes[strings.ToLower(theme)]; ok {
+		return NewStyles(palette), nil
+	}
+
+	path := theme
+	if !strings.ContainsRune(theme, filepath.Separator) && filepath.Ext(theme) == "" {
+		path = filepath.Join(themesDir, theme+".yaml")
+	}
+
+	palette, err := loadPalette(path)
+	if err != nil {
+		return DefaultStyles(), fmt.Errorf("theme %q: %w", theme, err)
+	}
+	return NewStyles(palette), nil
+}
+
+// loadPalette lê um tema YAML do usuário, completando as cores com o tema base
+func loadPalette(path string) (Palette, error) {
+	expanded, err := config.ExpandPath(path)
+	if err != nil {
+		return Palette{}, err
+	}
+
+	data, err := os.ReadFile(expanded)
+	if err != nil {
+		return Palette{}, fmt.Errorf("failed to read theme file: %w", err)
+	}
+
+	var file themeFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return Palette{}, fmt.Errorf("failed to parse theme file: %w", err)
+	}
+
+	inherit := strings.ToLower(file.Inherit)
+	if inherit == "" {
+		inherit = DefaultTheme
+	}
+	base, ok := builtinThemes[inherit]
+	if !ok {
+		return Palette{}, fmt.Errorf("unknown inherit theme %q (available: dark, light, monokai)", file.Inherit)
+	}
+
+	return mergePalette(base, file.Palette)
+}
+
+// mergePalette sobrescreve as cores da base com as definidas (validando o formato)
+func mergePalette(base, override Palette) (Palette, error) {
+	fields := []struct {
+		key   string
+		base  *string
+		value string
+	}{
+		{"title_fg", &base.TitleFg, override.TitleFg},
+		{"title_bg", &base.TitleBg, override.TitleBg},
+		{"border", &base.Border, override.Border},
+		{"header", &base.Header, override.Header},
+		{"selected_fg", &base.SelectedFg, override.SelectedFg},
+		{"selected_bg", &base.SelectedBg, override.SelectedBg},
+		{"muted", &base.Muted, override.Muted},
+		{"ok", &base.OK, override.OK},
+		{"info", &base.Info, override.Info},
+		{"warning", &base.Warning, override.Warning},
+		{"critical", &base.Critical, override.Critical},
+		{"online", &base.Online, override.Online},
+		{"degraded", &base.Degraded, override.Degraded},
+		{"offline", &base.Offline, override.Offline},
+		{"prometheus", &base.Prometheus, override.Prometheus},
+		{"metrics_server", &base.MetricsServer, override.MetricsServer},
+		{"hybrid", &base.Hybrid, override.Hybrid},
+	}
+
+	for _, f := range fields {
+		if f.value == "" {
+			continue
+		}
+		if !validColor(f.value) {
+			return Palette{}, fmt.Errorf("%s: invalid color %q (use 0-255 or #rrggbb)", f.key, f.value)
+		}
+		*f.base = f.value
+	}
+	return base, nil
+}
+
+// validColor cor ANSI 0-255 ou hex
+func validColor(c string) bool {
+	if hexColor.MatchString(c) {
+		return true
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && n >= 0 && n <= 255
+}
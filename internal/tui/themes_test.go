@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestLoadStylesBuiltin(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+
+	for _, theme := range []string{"", "dark", "Light", "monokai"} {
+		styles, err := LoadStyles(theme)
+		if err != nil {
+			t.Errorf("LoadStyles(%q) unexpected error: %v", theme, err)
+		}
+		if _, ok := styles.Critical.GetForeground().(lipgloss.NoColor); ok {
+			t.Errorf("LoadStyles(%q) expected colored critical style", theme)
+		}
+	}
+
+	monokai, _ := LoadStyles("monokai")
+	if monokai.Offline.GetForeground() != lipgloss.Color("#F92672") || monokai.Prometheus.GetForeground() != lipgloss.Color("#AE81FF") {
+		t.Error("Expected monokai palette for cluster status and data source")
+	}
+
+	if got := strings.Join(Themes(), ","); got != "dark,light,mono,monokai" {
+		t.Errorf("Themes() = %s", got)
+	}
+}
+
+func TestLoadStylesMonochrome(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	// NO_COLOR vence qualquer tema configurado
+	styles, err := LoadStyles("monokai")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for name, style := range map[string]lipgloss.Style{"critical": styles.Critical, "selected": styles.Selected, "title": styles.Title, "online": styles.Online} {
+		if _, ok := style.GetForeground().(lipgloss.NoColor); !ok {
+			t.Errorf("Expected no foreground color for %s", name)
+		}
+		if _, ok := style.GetBackground().(lipgloss.NoColor); !ok {
+			t.Errorf("Expected no background color for %s", name)
+		}
+	}
+	if !styles.Selected.GetReverse() || !styles.Critical.GetBold() {
+		t.Error("Expected selection and critical highlighted without colors")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	if styles, _ := LoadStyles("mono"); !styles.Selected.GetReverse() {
+		t.Error("Expected ui.theme mono to be monochrome")
+	}
+}
+
+func TestLoadStylesUserTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	dir := filepath.Join(home, ".hpa-watchdog", "themes")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	write("ticket.yaml", "inherit: light\ncritical: \"#ff0000\"\nhybrid: \"33\"\n")
+
+	// Pelo nome (~/.hpa-watchdog/themes/ticket.yaml): cores não definidas vêm do light
+	styles, err := LoadStyles("ticket")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	light := NewStyles(builtinThemes["light"])
+	if styles.Critical.GetForeground() != lipgloss.Color("#ff0000") || styles.Hybrid.GetForeground() != lipgloss.Color("33") ||
+		styles.Warning.GetForeground() != light.Warning.GetForeground() {
+		t.Error("Expected user colors merged over the light theme")
+	}
+
+	// Pelo caminho
+	path := write("bad.yaml", "warning: orange\n")
+	if _, err := LoadStyles(path); err == nil || !strings.Contains(err.Error(), `warning: invalid color "orange"`) {
+		t.Errorf("Expected invalid color error, got %v", err)
+	}
+
+	write("unknown.yaml", "inherit: solarized\n")
+	if _, err := LoadStyles("unknown"); err == nil || !strings.Contains(err.Error(), "unknown inherit theme") {
+		t.Errorf("Expected unknown inherit error, got %v", err)
+	}
+
+	// Tema inexistente: estilos padrão e erro no rodapé da TUI
+	styles, err = LoadStyles("missing")
+	if err == nil || styles.Critical.GetForeground() != DefaultStyles().Critical.GetForeground() {
+		t.Errorf("Expected default styles with error, got %v", err)
+	}
+
+	m := New(&models.WatchdogConfig{Theme: "missing"}, newFakeSource(), Options{})
+	if m.statusStyle != statusError || !strings.Contains(m.status, "Tema inválido") {
+		t.Errorf("Expected theme error in status, got %q", m.status)
+	}
+}
@@ -0,0 +1,193 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// thresholdEditor estado do modal de edição de thresholds
+type thresholdEditor struct {
+	fields  []config.ThresholdField
+	cursor  int
+	editing bool
+	input   string
+	errors  map[string]string // Erro de validação por chave (exibido na linha)
+	unsaved map[string]bool   // Aplicados e ainda não gravados no YAML
+}
+
+func newThresholdEditor() *thresholdEditor {
+	return &thresholdEditor{
+		fields:  config.ThresholdFields(),
+		errors:  make(map[string]string),
+		unsaved: make(map[string]bool),
+	}
+}
+
+// thresholds thresholds em uso: os do ThresholdManager (editáveis) ou os da config
+func (m Model) thresholds() models.Thresholds {
+	if m.opts.Thresholds != nil {
+		return m.opts.Thresholds.Get()
+	}
+	return m.cfg.Thresholds
+}
+
+// thresholdsFor thresholds efetivos do HPA (globais + overrides por cluster, namespace e HPA)
+func (m Model) thresholdsFor(s *models.HPASnapshot) models.Thresholds {
+	if m.opts.Thresholds != nil {
+		return m.opts.Thresholds.For(s.Cluster, s.Namespace, s.Name)
+	}
+	return m.cfg.Thresholds
+}
+
+// openEditor abre o modal (preserva edições não gravadas de uma abertura anterior)
+func (m *Model) openEditor() {
+	if m.opts.Thresholds == nil {
+		m.setStatus(statusError, "Edição de thresholds indisponível")
+		return
+	}
+	if m.editor == nil {
+		m.editor = newThresholdEditor()
+	}
+	m.showEditor = true
+}
+
+// handleEditorKey teclas do modal de thresholds
+func (m Model) handleEditorKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	e := m.editor
+	field := e.fields[e.cursor]
+
+	if e.editing {
+		switch msg.Type {
+		case tea.KeyEnter:
+			m.applyThreshold(field, e.input)
+		case tea.KeyEsc:
+			e.editing = false
+			delete(e.errors, field.Key)
+		case tea.KeyBackspace:
+			if len(e.input) > 0 {
+				e.input = e.input[:len(e.input)-1]
+			}
+		case tea.KeyCtrlU:
+			e.input = ""
+		case tea.KeyRunes:
+			for _, r := range msg.Runes {
+				if strings.ContainsRune("0123456789.-", r) {
+					e.input += string(r)
+				}
+			}
+		}
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "e":
+		m.showEditor = false
+	case "up", "k":
+		e.cursor = clamp(e.cursor-1, 0, len(e.fields)-1)
+	case "down", "j":
+		e.cursor = clamp(e.cursor+1, 0, len(e.fields)-1)
+	case "home", "g":
+		e.cursor = 0
+	case "end", "G":
+		e.cursor = len(e.fields) - 1
+
+	case "enter", " ":
+		if field.Bool {
+			m.applyThreshold(field, fmt.Sprint(field.Value(m.thresholds()) != "true"))
+			break
+		}
+		e.editing = true
+		e.input = field.Value(m.thresholds())
+
+	case "w":
+		m.saveThresholds()
+	}
+
+	return m, nil
+}
+
+// applyThreshold aplica o valor pelo setter do ThresholdManager (vale no próximo scan)
+func (m *Model) applyThreshold(field config.ThresholdField, value string) {
+	e := m.editor
+
+	if err := field.Set(m.opts.Thresholds, value); err != nil {
+		e.errors[field.Key] = err.Error()
+		return
+	}
+
+	delete(e.errors, field.Key)
+	e.editing = false
+	e.unsaved[field.Key] = true
+	m.setStatus(statusOK, fmt.Sprintf("%s = %s aplicado", field.Key, field.Value(m.thresholds())))
+}
+
+// saveThresholds grava os thresholds atuais no arquivo de configuração
+func (m *Model) saveThresholds() {
+	if m.opts.SaveThresholds == nil {
+		m.setStatus(statusError, "Gravação da configuração indisponível")
+		return
+	}
+	if err := m.opts.SaveThresholds(m.thresholds()); err != nil {
+		m.setStatus(statusError, "Falha ao gravar thresholds: "+err.Error())
+		return
+	}
+
+	m.editor.unsaved = make(map[string]bool)
+	m.setStatus(statusOK, "Thresholds gravados no arquivo de configuração")
+}
+
+// renderEditor modal centralizado com todos os thresholds
+func (m Model) renderEditor() string {
+	e := m.editor
+	current := m.thresholds()
+
+	labelWidth := 0
+	for _, field := range e.fields {
+		if w := lipgloss.Width(field.Label); w > labelWidth {
+			labelWidth = w
+		}
+	}
+
+	lines := []string{m.styles.Header.Render("Thresholds"), ""}
+	for i, field := range e.fields {
+		value := field.Value(current) + field.Unit
+		if e.editing && i == e.cursor {
+			value = e.input + "█"
+		}
+
+		mark := " "
+		if e.unsaved[field.Key] {
+			mark = "*"
+		}
+
+		line := fmt.Sprintf("%s %s  %-12s %s", mark, pad(field.Label, labelWidth), value, m.styles.Muted.Render(field.Key))
+		if i == e.cursor {
+			line = m.styles.Selected.Render(cursorMark + line)
+		} else {
+			line = "  " + line
+		}
+		lines = append(lines, line)
+
+		if err, ok := e.errors[field.Key]; ok {
+			lines = append(lines, "    "+m.styles.Critical.Render("✗ "+err))
+		}
+	}
+
+	help := "[↑↓] Navegar  [Enter] Editar/alternar  [w] Gravar no YAML  [Esc] Fechar"
+	if e.editing {
+		help = "[Enter] Aplicar  [Esc] Cancelar  [Ctrl+U] Limpar"
+	}
+	lines = append(lines, "", m.styles.Muted.Render("Alterações valem a partir do próximo scan; * = não gravado"))
+	if n := len(m.opts.Thresholds.Overrides()); n > 0 {
+		lines = append(lines, m.styles.Muted.Render(fmt.Sprintf("Valores globais: %d override(s) em threshold_overrides têm precedência", n)))
+	}
+	lines = append(lines, m.styles.Muted.Render(help))
+
+	box := m.styles.Panel.BorderForeground(m.styles.Header.GetForeground()).Render(strings.Join(lines, "\n"))
+	return lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,102 @@
+package tui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/config"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// typeText digita o texto no modal
+func typeText(t *testing.T, m Model, text string) Model {
+	t.Helper()
+	return update(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)})
+}
+
+// selectField move o cursor do modal até a chave
+func selectField(t *testing.T, m Model, key string) Model {
+	t.Helper()
+	for i, field := range m.editor.fields {
+		if field.Key == key {
+			m.editor.cursor = i
+			return m
+		}
+	}
+	t.Fatalf("Field %s not found", key)
+	return m
+}
+
+func TestThresholdEditor(t *testing.T) {
+	tm := config.NewThresholdManager(config.DefaultThresholds())
+
+	var saved []models.Thresholds
+	save := func(th models.Thresholds) error {
+		saved = append(saved, th)
+		if len(saved) > 1 {
+			return errors.New("read-only file system")
+		}
+		return nil
+	}
+
+	m := New(&models.WatchdogConfig{}, newFakeSource(), Options{Thresholds: tm, SaveThresholds: save})
+	m = update(t, m, tea.WindowSizeMsg{Width: 140, Height: 40})
+
+	m = update(t, m, keyMsg("e"))
+	if !m.showEditor || !strings.Contains(m.View(), "cpu_warning_percent") {
+		t.Fatalf("Expected thresholds modal, got:\n%s", m.View())
+	}
+
+	// Valor inválido: erro na linha, nada aplicado, continua editando
+	m = selectField(t, m, "cpu_warning_percent")
+	m = update(t, m, keyMsg("enter"))
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyCtrlU})
+	m = typeText(t, m, "95")
+	m = update(t, m, keyMsg("enter"))
+	if !m.editor.editing || tm.Get().CPUWarningPercent != 85 || !strings.Contains(m.View(), "must be < cpu_critical_percent (90)") {
+		t.Errorf("Expected inline validation error, got:\n%s", m.View())
+	}
+
+	// Letras são ignoradas; Backspace corrige
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyBackspace})
+	m = typeText(t, m, "8x0")
+	m = update(t, m, keyMsg("enter"))
+	if m.editor.editing || tm.Get().CPUWarningPercent != 80 || len(m.editor.errors) != 0 || !m.editor.unsaved["cpu_warning_percent"] {
+		t.Errorf("Expected 80 applied live, got %d (errors %v)", tm.Get().CPUWarningPercent, m.editor.errors)
+	}
+
+	// Booleano alterna direto
+	m = selectField(t, m, "alert_on_config_change")
+	m = update(t, m, keyMsg("enter"))
+	if tm.Get().AlertOnConfigChange {
+		t.Error("Expected alert_on_config_change toggled off")
+	}
+
+	// Gravação: sucesso limpa os pendentes; falha aparece no rodapé
+	m = update(t, m, keyMsg("w"))
+	if len(saved) != 1 || saved[0].CPUWarningPercent != 80 || len(m.editor.unsaved) != 0 {
+		t.Errorf("Expected thresholds saved, got %v", saved)
+	}
+	m = update(t, m, keyMsg("w"))
+	if m.statusStyle != statusError || !strings.Contains(m.View(), "read-only file system") {
+		t.Errorf("Expected save error in footer, got %q", m.status)
+	}
+
+	// q não sai do modal; Esc fecha e o dashboard usa os thresholds novos
+	if _, cmd := m.Update(keyMsg("q")); cmd != nil {
+		t.Error("Expected q ignored inside the modal")
+	}
+	m = update(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.showEditor || m.thresholds().CPUWarningPercent != 80 {
+		t.Error("Expected modal closed with live thresholds")
+	}
+
+	readOnly := New(&models.WatchdogConfig{}, newFakeSource(), Options{})
+	readOnly = update(t, readOnly, keyMsg("e"))
+	if readOnly.showEditor || readOnly.statusStyle != statusError {
+		t.Error("Expected editor unavailable without ThresholdManager")
+	}
+}
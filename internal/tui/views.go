@@ -0,0 +1,488 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/charts"
+	"github.com/Paulo-Ribeiro-Log/hpa-watchdog/internal/models"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	maxAlertRows = 6 // Linhas do painel de alertas no dashboard
+	minHPARows   = 3
+	cursorMark   = "› " // Marca a linha selecionada (visível mesmo sem cores)
+	sparkWidth   = 10   // Coluna CPU 5M (sparkline do CPUHistory)
+	chartHeight  = 8    // Linhas do gráfico do HPA selecionado
+)
+
+// column coluna de tabela com largura fixa
+type column struct {
+	title string
+	width int
+}
+
+// View renderiza o dashboard
+func (m Model) View() string {
+	if m.silencePrompt != nil {
+		return lipgloss.JoinVertical(lipgloss.Left, m.renderSilencePrompt(), m.renderStatusLine(""))
+	}
+	if m.showEditor {
+		return lipgloss.JoinVertical(lipgloss.Left, m.renderEditor(), m.renderStatusLine(""))
+	}
+	if m.view == viewAlerts {
+		return m.renderAlertsView()
+	}
+
+	sections := []string{
+		m.renderTitle(),
+		m.renderClusters(),
+		m.renderHPAs(),
+	}
+	if m.showChart {
+		sections = append(sections, m.renderHPAChart())
+	}
+	sections = append(sections, m.renderAlerts(), m.renderFooter())
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderTitle barra superior com totais e último scan
+func (m Model) renderTitle() string {
+	critical := 0
+	for i := range m.alerts {
+		if m.alerts[i].Severity == models.SeverityCritical {
+			critical++
+		}
+	}
+
+	scan := "aguardando primeiro scan"
+	if !m.lastScan.IsZero() {
+		scan = fmt.Sprintf("scan #%d às %s", m.scans, m.lastScan.Format("15:04:05"))
+	}
+
+	title := fmt.Sprintf("🐕 HPA Watchdog │ Clusters: %d  HPAs: %d  Alertas: %d (%d críticos) │ %s",
+		len(m.clusters), len(m.snapshots), len(m.alerts), critical, scan)
+
+	return m.styles.Title.Width(m.width).Render(truncate(title, m.width-2))
+}
+
+// renderClusters resumo por cluster (status, HPAs, alertas, último scan)
+func (m Model) renderClusters() string {
+	columns := []column{
+		{"STATUS", 10},
+		{"CLUSTER", widest(8, 32, len(m.clusters), func(i int) string { return m.clusters[i].Name })},
+		{"HPAs", 6},
+		{"ALERTAS", 8},
+		{"ÚLTIMO SCAN", 20},
+	}
+
+	lines := []string{m.styles.Header.Render(formatRow(columns, headerCells(columns)))}
+
+	if len(m.clusters) == 0 {
+		lines = append(lines, m.styles.Muted.Render("Nenhum cluster monitorado"))
+	}
+
+	for _, cluster := range m.clusters {
+		cells := []string{
+			"● " + cluster.Status.String(),
+			cluster.Name,
+			fmt.Sprintf("%d", cluster.HPACount),
+			fmt.Sprintf("%d", cluster.AlertCount),
+			m.formatScan(cluster.LastScan),
+		}
+
+		styles := []lipgloss.Style{m.clusterStatusStyle(cluster.Status), {}, {}, {}, m.styles.Muted}
+		if cluster.AlertCount > 0 {
+			styles[3] = m.styles.Warning
+		}
+
+		lines = append(lines, formatStyledRow(columns, cells, styles))
+	}
+
+	return m.renderPanel("Clusters", lines, false)
+}
+
+// hpaColumns colunas da tabela de HPAs (larguras pelos dados visíveis)
+func (m Model) hpaColumns() []column {
+	columns := []column{
+		{"CLUSTER", widest(9, 24, len(m.snapshots), func(i int) string { return m.snapshots[i].Cluster })},
+		{"NAMESPACE", widest(11, 24, len(m.snapshots), func(i int) string { return m.snapshots[i].Namespace })},
+		{"HPA", widest(5, 32, len(m.snapshots), func(i int) string { return m.snapshots[i].Name })},
+		{"RÉPLICAS", 9},
+		{"MIN/MAX", 8},
+		{"CPU/TARGET", 11},
+		{"MEM/TARGET", 11},
+		{"CPU 5M", sparkWidth},
+		{"FONTE", 15},
+	}
+
+	// Indicador da coluna ordenada
+	arrow := " ▲"
+	if m.sortDesc {
+		arrow = " ▼"
+	}
+	switch m.sortBy {
+	case sortByName:
+		columns[2].title += arrow
+	case sortByReplicas:
+		columns[3].title += arrow
+	case sortByLimit:
+		columns[4].title += arrow
+	case sortByCPU:
+		columns[5].title += arrow
+	case sortByMemory:
+		columns[6].title += arrow
+	case sortBySource:
+		columns[8].title += arrow
+	}
+
+	return columns
+}
+
+// renderHPAs tabela de HPAs ordenável
+func (m Model) renderHPAs() string {
+	columns := m.hpaColumns()
+	lines := []string{m.styles.Header.Render("  " + formatRow(columns, headerCells(columns)))}
+
+	if len(m.snapshots) == 0 {
+		lines = append(lines, m.styles.Muted.Render("Nenhum HPA coletado ainda"))
+	}
+
+	rows := m.hpaRows()
+	start := scrollStart(m.hpaCursor, rows)
+	for i := start; i < len(m.snapshots) && i < start+rows; i++ {
+		s := &m.snapshots[i]
+
+		cells := []string{
+			s.Cluster,
+			s.Namespace,
+			s.Name,
+			fmt.Sprintf("%d→%d", s.CurrentReplicas, s.DesiredReplicas),
+			fmt.Sprintf("%d/%d", s.MinReplicas, s.MaxReplicas),
+			formatUsage(s.CPUCurrent, s.CPUTarget),
+			formatUsage(s.MemoryCurrent, s.MemoryTarget),
+			charts.Sparkline(s.CPUHistory, sparkWidth),
+			s.DataSource.String(),
+		}
+
+		if i == m.hpaCursor && m.focus == panelHPAs {
+			lines = append(lines, m.styles.Selected.Render(cursorMark+formatRow(columns, cells)))
+			continue
+		}
+
+		thresholds := m.thresholdsFor(s)
+		styles := []lipgloss.Style{{}, {}, {}, {}, m.replicaStyle(s), m.usageStyle(s.CPUCurrent, s.CPUTarget, thresholds.CPUWarningPercent, thresholds.CPUCriticalPercent),
+			m.usageStyle(s.MemoryCurrent, s.MemoryTarget, thresholds.MemoryWarningPercent, thresholds.MemoryCriticalPercent), m.styles.Info, m.dataSourceStyle(s.DataSource)}
+		lines = append(lines, "  "+formatStyledRow(columns, cells, styles))
+	}
+
+	title := fmt.Sprintf("HPAs (%d)", len(m.snapshots))
+	if len(m.snapshots) > rows {
+		title += fmt.Sprintf("  %d/%d", m.hpaCursor+1, len(m.snapshots))
+	}
+
+	return m.renderPanel(title, lines, m.focus == panelHPAs)
+}
+
+// renderHPAChart gráfico do HPA selecionado: CPU vs target (banda de desvio) com réplicas sobrepostas
+func (m Model) renderHPAChart() string {
+	title := "Histórico"
+	if key := m.selectedKey(); key != "" {
+		title += " " + key
+	}
+	return m.renderPanel(title, m.hpaChartLines(), false)
+}
+
+// hpaChartLines conteúdo do painel do gráfico (gráfico + sparkline de memória)
+func (m Model) hpaChartLines() []string {
+	if m.hpaCursor < 0 || m.hpaCursor >= len(m.snapshots) {
+		return []string{m.styles.Muted.Render("Nenhum HPA selecionado")}
+	}
+	s := &m.snapshots[m.hpaCursor]
+
+	lines := charts.HPA(s, charts.Options{Width: m.innerWidth(), Height: chartHeight, BandPercent: m.thresholdsFor(s).TargetDeviationPercent})
+	if lines == nil {
+		lines = []string{m.styles.Muted.Render("Sem histórico de CPU/réplicas (requer Prometheus)")}
+	}
+	if len(s.MemoryHistory) > 0 {
+		lines = append(lines, fmt.Sprintf("      Memória %s  %s", charts.Sparkline(s.MemoryHistory, 0), formatUsage(s.MemoryCurrent, s.MemoryTarget)))
+	}
+	return lines
+}
+
+// chartLines linhas ocupadas pelo painel do gráfico (0 se fechado)
+func (m Model) chartLines() int {
+	if !m.showChart {
+		return 0
+	}
+	// borda 2 + título 1
+	return 3 + len(m.hpaChartLines())
+}
+
+// renderAlerts lista dos alertas ativos (mais severos primeiro)
+func (m Model) renderAlerts() string {
+	var lines []string
+
+	if len(m.alerts) == 0 {
+		lines = append(lines, m.styles.OK.Render("Nenhum alerta ativo"))
+	}
+
+	start := scrollStart(m.alertCursor, maxAlertRows)
+	for i := start; i < len(m.alerts) && i < start+maxAlertRows; i++ {
+		alert := &m.alerts[i]
+
+		ack := " "
+		if alert.Acknowledged {
+			ack = "✓"
+		}
+
+		target := strings.Trim(strings.Join([]string{alert.Cluster, alert.Namespace, alert.HPAName}, "/"), "/")
+		text := fmt.Sprintf("%s %s %s %-18s %s", ack, alert.Timestamp.Format("15:04:05"), target, alert.Type.String(), alert.Summary)
+
+		if i == m.alertCursor && m.focus == panelAlerts {
+			lines = append(lines, m.styles.Selected.Render(truncate(fmt.Sprintf("%s● %-8s %s", cursorMark, severityLabel(alert.Severity), text), m.innerWidth())))
+			continue
+		}
+
+		label := m.severityStyle(alert.Severity).Render(fmt.Sprintf("● %-8s", severityLabel(alert.Severity)))
+		lines = append(lines, "  "+label+" "+truncate(text, m.innerWidth()-13))
+	}
+
+	return m.renderPanel(fmt.Sprintf("Alertas ativos (%d)", len(m.alerts)), lines, m.focus == panelAlerts)
+}
+
+// renderFooter teclas disponíveis e ordenação atual
+func (m Model) renderFooter() string {
+	direction := "▲"
+	if m.sortDesc {
+		direction = "▼"
+	}
+
+	footer := fmt.Sprintf("[2] Alertas  [Tab] Painel  [↑↓] Navegar  [Enter] Gráfico  [s/S] Ordenar: %s %s  [r] Inverter  [e] Thresholds  [F5] Atualizar  [q] Sair  │  refresh %s",
+		m.sortBy, direction, m.refreshInterval())
+
+	return m.renderStatusLine(footer)
+}
+
+// renderPanel painel com borda e título (borda destacada quando tem foco)
+func (m Model) renderPanel(title string, lines []string, focused bool) string {
+	style := m.styles.Panel.Width(m.width - 2)
+	if focused {
+		style = style.BorderForeground(m.styles.Header.GetForeground())
+	}
+
+	content := append([]string{m.styles.Header.Render(title)}, lines...)
+	return style.Render(strings.Join(content, "\n"))
+}
+
+// innerWidth largura útil dentro de um painel (borda + padding)
+func (m Model) innerWidth() int {
+	return m.width - 4
+}
+
+// hpaRows quantas linhas da tabela de HPAs cabem na tela
+func (m Model) hpaRows() int {
+	clusterLines := len(m.clusters)
+	if clusterLines == 0 {
+		clusterLines = 1
+	}
+
+	alertLines := len(m.alerts)
+	if alertLines > maxAlertRows {
+		alertLines = maxAlertRows
+	}
+	if alertLines == 0 {
+		alertLines = 1
+	}
+
+	// título + rodapé + painéis (borda 2 + título 1 [+ cabeçalho 1])
+	used := 1 + 1 + (4 + clusterLines) + (3 + alertLines) + 4 + m.chartLines()
+
+	rows := m.height - used
+	if rows < minHPARows {
+		rows = minHPARows
+	}
+	return rows
+}
+
+// pageSize linhas visíveis do painel com foco (PgUp/PgDown)
+func (m Model) pageSize() int {
+	if m.focus == panelAlerts {
+		return maxAlertRows
+	}
+	return m.hpaRows()
+}
+
+// formatScan horário do último scan com idade relativa ao último refresh
+func (m Model) formatScan(at time.Time) string {
+	if at.IsZero() {
+		return "-"
+	}
+	age := m.refreshedAt.Sub(at).Truncate(time.Second)
+	if age < 0 {
+		age = 0
+	}
+	return fmt.Sprintf("%s (%s)", at.Format("15:04:05"), formatAge(age))
+}
+
+// formatAge idade compacta: 12s, 5m, 3h, 2d
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return fmt.Sprintf("%ds", int(age.Seconds()))
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+func (m Model) clusterStatusStyle(status models.ClusterStatus) lipgloss.Style {
+	switch status {
+	case models.ClusterStatusOnline:
+		return m.styles.Online
+	case models.ClusterStatusDegraded:
+		return m.styles.Degraded
+	default:
+		return m.styles.Offline
+	}
+}
+
+func (m Model) dataSourceStyle(source models.DataSource) lipgloss.Style {
+	switch source {
+	case models.DataSourcePrometheus:
+		return m.styles.Prometheus
+	case models.DataSourceMetricsServer:
+		return m.styles.MetricsServer
+	default:
+		return m.styles.Hybrid
+	}
+}
+
+func (m Model) severityStyle(severity models.AlertSeverity) lipgloss.Style {
+	switch severity {
+	case models.SeverityCritical:
+		return m.styles.Critical
+	case models.SeverityWarning:
+		return m.styles.Warning
+	default:
+		return m.styles.Info
+	}
+}
+
+// replicaStyle destaca HPAs no limite de réplicas
+func (m Model) replicaStyle(s *models.HPASnapshot) lipgloss.Style {
+	if s.MaxReplicas > 0 && s.CurrentReplicas >= s.MaxReplicas {
+		return m.styles.Critical
+	}
+	return lipgloss.Style{}
+}
+
+// usageStyle cor do uso: crítico/warning pelos thresholds, warning acima do target
+func (m Model) usageStyle(current float64, target, warning, critical int32) lipgloss.Style {
+	switch {
+	case critical > 0 && current >= float64(critical):
+		return m.styles.Critical
+	case warning > 0 && current >= float64(warning):
+		return m.styles.Warning
+	case target > 0 && current > float64(target):
+		return m.styles.Warning
+	case target > 0:
+		return m.styles.OK
+	default:
+		return lipgloss.Style{}
+	}
+}
+
+func severityLabel(severity models.AlertSeverity) string {
+	return strings.ToUpper(severity.String())
+}
+
+// formatUsage "72%/70%" (target "-" quando o HPA não usa a métrica)
+func formatUsage(current float64, target int32) string {
+	if target <= 0 {
+		if current <= 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%.0f%%/-", current)
+	}
+	return fmt.Sprintf("%.0f%%/%d%%", current, target)
+}
+
+// scrollStart primeira linha visível para manter o cursor na tela
+func scrollStart(cursor, rows int) int {
+	if cursor < rows {
+		return 0
+	}
+	return cursor - rows + 1
+}
+
+// widest largura da coluna pelo maior valor (entre min e max)
+func widest(min, max, n int, value func(i int) string) int {
+	width := min
+	for i := 0; i < n; i++ {
+		if w := len([]rune(value(i))); w > width {
+			width = w
+		}
+	}
+	if width > max {
+		width = max
+	}
+	return width
+}
+
+func headerCells(columns []column) []string {
+	cells := make([]string, len(columns))
+	for i, c := range columns {
+		cells[i] = c.title
+	}
+	return cells
+}
+
+// formatRow alinha as células nas larguras das colunas
+func formatRow(columns []column, cells []string) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		parts[i] = pad(cells[i], c.width)
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatStyledRow como formatRow, aplicando o estilo de cada célula depois do alinhamento
+func formatStyledRow(columns []column, cells []string, styles []lipgloss.Style) string {
+	parts := make([]string, len(columns))
+	for i, c := range columns {
+		parts[i] = styles[i].Render(pad(cells[i], c.width))
+	}
+	return strings.Join(parts, " ")
+}
+
+// pad trunca ou completa com espaços até width (unicode-safe)
+func pad(s string, width int) string {
+	s = truncate(s, width)
+	if gap := width - lipgloss.Width(s); gap > 0 {
+		s += strings.Repeat(" ", gap)
+	}
+	return s
+}
+
+// truncate corta s em width colunas, terminando com "…" (unicode-safe)
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if lipgloss.Width(s) <= width {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 && lipgloss.Width(string(runes))+1 > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}